@@ -0,0 +1,97 @@
+package mylib
+
+// Keyboard is implemented by devices with a typing keyboard. Applications
+// type-assert an [InputDevice] to it to look up key names and states
+// without depending on a backend.
+type Keyboard interface {
+	InputDevice
+
+	// Keys returns the keys the keyboard has.
+	Keys() ([]InputCode, error)
+
+	// PressedKeys returns the keys currently held down.
+	PressedKeys() ([]InputCode, error)
+
+	// KeyName returns the name of the key code, such as "KEY_A", or an
+	// empty string if it is unknown.
+	KeyName(code InputCode) string
+}
+
+// Pointer is implemented by devices that move a pointer, such as mice,
+// touchpads, and trackballs.
+type Pointer interface {
+	InputDevice
+
+	// Buttons returns the buttons the pointer has, such as its left and
+	// right buttons.
+	Buttons() ([]InputCode, error)
+
+	// Relative reports whether the pointer reports relative motion, like
+	// a mouse, rather than absolute positions, like a touchpad or a
+	// graphics tablet.
+	Relative() (bool, error)
+}
+
+// Axis describes an absolute axis, such as a gamepad stick or a trigger.
+type Axis struct {
+	// Code identifies the axis.
+	Code InputCode
+
+	// Name is the name of the axis, such as "ABS_X".
+	Name string
+
+	// Value is the current position of the axis.
+	Value int32
+
+	// Minimum and Maximum bound Value.
+	Minimum, Maximum int32
+}
+
+// Gamepad is implemented by gamepads and joysticks.
+type Gamepad interface {
+	InputDevice
+
+	// Buttons returns the buttons the gamepad has.
+	Buttons() ([]InputCode, error)
+
+	// Axes returns the absolute axes of the gamepad with their current
+	// positions.
+	Axes() ([]Axis, error)
+}
+
+// Contact is a finger or stylus touching a touchscreen.
+type Contact struct {
+	// ID identifies the contact for as long as it touches the screen.
+	ID int32
+
+	// X and Y are the position of the contact, in device units.
+	X, Y int32
+}
+
+// Touchscreen is implemented by touchscreens able to track several
+// contacts at once.
+type Touchscreen interface {
+	InputDevice
+
+	// MaxContacts returns how many contacts the touchscreen can track
+	// at once.
+	MaxContacts() (int, error)
+
+	// Contacts returns the contacts currently touching the screen.
+	Contacts() ([]Contact, error)
+}
+
+// LEDController is implemented by devices with indicator LEDs, such as
+// the Caps Lock light of a keyboard.
+type LEDController interface {
+	InputDevice
+
+	// LEDs returns the LEDs the device has.
+	LEDs() ([]InputCode, error)
+
+	// LED reports whether the LED code is lit.
+	LED(code InputCode) (bool, error)
+
+	// SetLED lights or turns off the LED code.
+	SetLED(code InputCode, on bool) error
+}
@@ -0,0 +1,411 @@
+//go:build linux
+
+// Package main implements the abscalibrate CLI, an interactive wizard
+// that calibrates the absolute axes of a joystick, gamepad, or
+// touchscreen.
+//
+// Usage:
+//
+//	abscalibrate [-duration 10s] [-apply] [-save] /dev/input/eventN
+//	abscalibrate -restore /dev/input/eventN
+//
+// It first asks the user to leave the device at rest to measure noise,
+// then to move every stick, trigger, or finger to its extremes. From the
+// recorded values it computes each axis's minimum, maximum, fuzz, and
+// flat, and prints them. With -apply, it writes them to the device with
+// EVIOCSABS. With -save, it stores them as the device's profile in the
+// calibration file shared with other programs, [calibration.DefaultFile]
+// under $XDG_STATE_HOME, from where -restore applies them again, for
+// example from a udev rule after the device is plugged in.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"time"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+	"github.com/andrieee44/mylib/x/linux/input/calibration"
+)
+
+const restDuration time.Duration = 2 * time.Second
+
+type axis struct {
+	code             mylib.InputCode
+	name             string
+	info             input.AbsInfo
+	restMin, restMax int32
+	min, max         int32
+	moved            bool
+}
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "abscalibrate:", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var (
+		duration             *time.Duration
+		apply, save, restore *bool
+		path                 string
+		store                *calibration.Store
+		dev                  *input.Device
+		axes                 []*axis
+		events               chan input.Event
+		errs                 chan error
+		err                  error
+	)
+
+	duration = flag.Duration("duration", 10*time.Second, "how long to record axis extremes")
+	apply = flag.Bool("apply", false, "apply the calibration to the device")
+	save = flag.Bool("save", false, "save the calibration for the device model")
+	restore = flag.Bool("restore", false, "apply the saved calibration and exit")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "usage: abscalibrate [flags] /dev/input/eventN")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	path = flag.Arg(0)
+
+	if *save || *restore {
+		store, err = calibration.Load(calibration.DefaultFile)
+		exitIf(err)
+	}
+
+	if *restore {
+		dev, err = input.NewDevice(path)
+		exitIf(err)
+
+		err = restoreProfile(dev, store)
+		exitIf(err)
+
+		err = dev.Close()
+		exitIf(err)
+
+		return
+	}
+
+	if *apply {
+		dev, err = input.NewDevice(path)
+	} else {
+		dev, err = input.NewDevice(path, input.ReadOnly())
+	}
+
+	exitIf(err)
+
+	axes, err = deviceAxes(dev)
+	exitIf(err)
+
+	if len(axes) == 0 {
+		exitIf(errors.New("the device has no absolute axes"))
+	}
+
+	events = make(chan input.Event)
+	errs = make(chan error, 1)
+
+	go read(dev, events, errs)
+
+	fmt.Fprintf(os.Stderr, "Leave the device at rest for %s...\n", restDuration)
+	err = record(axes, events, errs, restDuration, true)
+	exitIf(err)
+
+	fmt.Fprintf(os.Stderr, "Move every axis to its extremes for %s...\n", *duration)
+	err = record(axes, events, errs, *duration, false)
+	exitIf(err)
+
+	printAxes(axes)
+
+	if *apply {
+		err = applyAxes(dev, axes)
+		exitIf(err)
+	}
+
+	if *save {
+		err = saveAxes(dev, store, axes)
+		exitIf(err)
+
+		fmt.Fprintln(os.Stderr, "Saved the calibration to", calibration.DefaultFile)
+	}
+
+	err = dev.Close()
+	exitIf(err)
+}
+
+func deviceAxes(dev *input.Device) ([]*axis, error) {
+	var (
+		codes []mylib.InputCode
+		code  mylib.InputCode
+		axes  []*axis
+		info  input.AbsInfo
+		err   error
+	)
+
+	codes, err = dev.Codes(input.EV_ABS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code = range codes {
+		if code == input.ABS_MT_SLOT || code == input.ABS_MT_TRACKING_ID {
+			continue
+		}
+
+		info, err = dev.AbsInfo(code)
+		if err != nil {
+			return nil, err
+		}
+
+		axes = append(axes, &axis{
+			code:    code,
+			name:    input.CodeName(input.EV_ABS, code),
+			info:    info,
+			restMin: info.Value,
+			restMax: info.Value,
+			min:     info.Value,
+			max:     info.Value,
+		})
+	}
+
+	return axes, nil
+}
+
+func read(dev *input.Device, events chan<- input.Event, errs chan<- error) {
+	var (
+		ev  input.Event
+		err error
+	)
+
+	for {
+		ev, err = dev.ReadEvent()
+		if err != nil {
+			errs <- err
+
+			return
+		}
+
+		if ev.Type == input.EV_ABS {
+			events <- ev
+		}
+	}
+}
+
+// record updates the rest or movement extremes of axes from the events
+// received during duration.
+func record(
+	axes []*axis,
+	events <-chan input.Event,
+	errs <-chan error,
+	duration time.Duration,
+	rest bool,
+) error {
+	var (
+		timer *time.Timer
+		ev    input.Event
+		a     *axis
+		err   error
+	)
+
+	timer = time.NewTimer(duration)
+	defer timer.Stop()
+
+	for {
+		select {
+		case ev = <-events:
+			for _, a = range axes {
+				if a.code != mylib.InputCode(ev.Code) {
+					continue
+				}
+
+				if rest {
+					a.restMin = min(a.restMin, ev.Value)
+					a.restMax = max(a.restMax, ev.Value)
+				}
+
+				a.min = min(a.min, ev.Value)
+				a.max = max(a.max, ev.Value)
+				a.moved = a.moved || !rest
+			}
+		case err = <-errs:
+			return err
+		case <-timer.C:
+			return nil
+		}
+	}
+}
+
+// calibrated returns the axis parameters derived from the recorded
+// values. Fuzz is half the noise seen at rest. Flat covers the rest
+// position when it lies near the middle of the range, as for a
+// self-centering stick, and is zero otherwise. Axes that were never moved
+// keep their reported parameters.
+func (a *axis) calibrated() input.AbsInfo {
+	var (
+		info        input.AbsInfo
+		mid, offset int32
+	)
+
+	if !a.moved || a.min == a.max {
+		return a.info
+	}
+
+	info = a.info
+	info.Minimum = a.min
+	info.Maximum = a.max
+	info.Fuzz = (a.restMax - a.restMin) / 2
+
+	mid = a.min + (a.max-a.min)/2
+	offset = max(a.restMax-mid, mid-a.restMin)
+
+	info.Flat = 0
+	if offset < (a.max-a.min)/10 {
+		info.Flat = offset
+	}
+
+	return info
+}
+
+func printAxes(axes []*axis) {
+	var (
+		a    *axis
+		info input.AbsInfo
+	)
+
+	for _, a = range axes {
+		info = a.calibrated()
+		if !a.moved {
+			fmt.Printf("%s: not moved, keeping reported values\n", a.name)
+
+			continue
+		}
+
+		fmt.Printf(
+			"%s: min %d max %d fuzz %d flat %d (was min %d max %d fuzz %d flat %d)\n",
+			a.name,
+			info.Minimum,
+			info.Maximum,
+			info.Fuzz,
+			info.Flat,
+			a.info.Minimum,
+			a.info.Maximum,
+			a.info.Fuzz,
+			a.info.Flat,
+		)
+	}
+}
+
+func applyAxes(dev *input.Device, axes []*axis) error {
+	var (
+		a   *axis
+		err error
+	)
+
+	for _, a = range axes {
+		if !a.moved {
+			continue
+		}
+
+		err = dev.SetAbsInfo(a.code, a.calibrated())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveAxes stores the calibration of the moved axes as the profile of
+// dev, keeping the saved calibration of the other axes.
+func saveAxes(dev *input.Device, store *calibration.Store, axes []*axis) error {
+	var (
+		id      input.ID
+		profile calibration.Profile
+		a       *axis
+		info    input.AbsInfo
+		err     error
+	)
+
+	id, err = dev.InputID()
+	if err != nil {
+		return err
+	}
+
+	profile = maps.Clone(store.Profile(id))
+	if profile == nil {
+		profile = make(calibration.Profile)
+	}
+
+	for _, a = range axes {
+		if !a.moved {
+			continue
+		}
+
+		info = a.calibrated()
+
+		profile[a.code] = calibration.Axis{
+			Minimum:  info.Minimum,
+			Maximum:  info.Maximum,
+			Flat:     info.Flat,
+			Fuzz:     info.Fuzz,
+			Inverted: profile[a.code].Inverted,
+		}
+	}
+
+	store.SetProfile(id, profile)
+
+	return store.Save()
+}
+
+// restoreProfile applies the saved profile of dev with EVIOCSABS. The
+// inversion of an axis cannot be set on the device and is left to
+// readers using [calibration.Store.NewAxis].
+func restoreProfile(dev *input.Device, store *calibration.Store) error {
+	var (
+		id      input.ID
+		profile calibration.Profile
+		code    mylib.InputCode
+		info    input.AbsInfo
+		err     error
+	)
+
+	id, err = dev.InputID()
+	if err != nil {
+		return err
+	}
+
+	profile = store.Profile(id)
+	if len(profile) == 0 {
+		return fmt.Errorf(
+			"no calibration saved for %04x:%04x:%04x:%04x",
+			id.Bustype,
+			id.Vendor,
+			id.Product,
+			id.Version,
+		)
+	}
+
+	for code = range profile {
+		info, err = dev.AbsInfo(code)
+		if err != nil {
+			return err
+		}
+
+		info, _ = profile.Apply(code, info)
+
+		err = dev.SetAbsInfo(code, info)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
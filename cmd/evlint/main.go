@@ -0,0 +1,288 @@
+//go:build linux
+
+// Package main implements the evlint CLI, which inspects an evdev device
+// and reports suspicious capabilities, helping to debug broken HID
+// firmware and drivers.
+//
+// Usage:
+//
+//	evlint [-watch duration] /dev/input/eventN
+//
+// It reports event types advertised without any codes, a missing EV_SYN
+// type, and absolute axes with empty or inverted ranges or with fuzz or
+// flat values wider than their range. With -watch, it also reads events
+// for the given duration and reports the keys and buttons that were
+// advertised but never emitted; press each of them during that time.
+//
+// evlint exits with status 1 if it finds any problems and with status 2
+// if it cannot inspect the device.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "evlint:", err)
+		os.Exit(2)
+	}
+}
+
+func main() {
+	var (
+		watch    *time.Duration
+		dev      *input.Device
+		problems []string
+		problem  string
+		err      error
+	)
+
+	watch = flag.Duration(
+		"watch",
+		0,
+		"read events for this long and report keys that never emit",
+	)
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: evlint [-watch duration] /dev/input/eventN")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dev, err = input.NewDevice(flag.Arg(0))
+	exitIf(err)
+
+	problems, err = lint(dev)
+	exitIf(err)
+
+	if *watch > 0 {
+		problem, err = silentKeys(dev, *watch)
+		exitIf(err)
+
+		if problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	err = dev.Close()
+	exitIf(err)
+
+	for _, problem = range problems {
+		fmt.Printf("%s: %s\n", flag.Arg(0), problem)
+	}
+
+	if len(problems) != 0 {
+		os.Exit(1)
+	}
+}
+
+func lint(dev *input.Device) ([]string, error) {
+	var (
+		problems []string
+		events   []mylib.InputEvent
+		event    mylib.InputEvent
+		codes    []mylib.InputCode
+		code     mylib.InputCode
+		info     input.AbsInfo
+		err      error
+	)
+
+	events, err = dev.Events()
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.Contains(events, input.EV_SYN) {
+		problems = append(problems, "EV_SYN is not advertised")
+	}
+
+	for _, event = range events {
+		if event == input.EV_SYN || event == input.EV_REP || event == input.EV_PWR ||
+			event == input.EV_FF_STATUS {
+			continue
+		}
+
+		codes, err = dev.Codes(event)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(codes) == 0 {
+			problems = append(problems, fmt.Sprintf(
+				"%s is advertised without any codes",
+				input.TypeName(event),
+			))
+		}
+
+		if event != input.EV_ABS {
+			continue
+		}
+
+		for _, code = range codes {
+			info, err = dev.AbsInfo(code)
+			if err != nil {
+				return nil, err
+			}
+
+			problems = append(problems, lintAxis(code, info)...)
+		}
+	}
+
+	return problems, nil
+}
+
+func lintAxis(code mylib.InputCode, info input.AbsInfo) []string {
+	var (
+		problems []string
+		name     string
+		span     int64
+	)
+
+	name = input.CodeName(input.EV_ABS, code)
+	if name == "" {
+		name = fmt.Sprintf("ABS_0x%x", code)
+	}
+
+	// The ranges of ABS_MT_SLOT and ABS_MT_TRACKING_ID are not
+	// positions, so fuzz and flat do not apply to them.
+	switch {
+	case info.Minimum == info.Maximum:
+		return []string{fmt.Sprintf(
+			"%s has an empty range, min == max == %d",
+			name,
+			info.Minimum,
+		)}
+	case info.Minimum > info.Maximum:
+		return []string{fmt.Sprintf(
+			"%s has an inverted range, min %d > max %d",
+			name,
+			info.Minimum,
+			info.Maximum,
+		)}
+	case code == input.ABS_MT_SLOT || code == input.ABS_MT_TRACKING_ID:
+		return nil
+	}
+
+	span = int64(info.Maximum) - int64(info.Minimum)
+
+	if int64(info.Fuzz) > span {
+		problems = append(problems, fmt.Sprintf(
+			"%s has fuzz %d wider than its range %d..%d",
+			name,
+			info.Fuzz,
+			info.Minimum,
+			info.Maximum,
+		))
+	}
+
+	if int64(info.Flat) > span {
+		problems = append(problems, fmt.Sprintf(
+			"%s has flat %d wider than its range %d..%d",
+			name,
+			info.Flat,
+			info.Minimum,
+			info.Maximum,
+		))
+	}
+
+	return problems
+}
+
+// silentKeys reads events from dev for the given duration and describes
+// the advertised keys that never emitted an event, or returns an empty
+// string if all of them did. Reads block, so the reading goroutine is
+// abandoned when the duration elapses.
+func silentKeys(dev *input.Device, duration time.Duration) (string, error) {
+	var (
+		codes  []mylib.InputCode
+		code   mylib.InputCode
+		seen   chan mylib.InputCode
+		errs   chan error
+		timer  *time.Timer
+		silent []string
+		err    error
+	)
+
+	codes, err = dev.Codes(input.EV_KEY)
+	if err != nil {
+		return "", err
+	}
+
+	if len(codes) == 0 {
+		return "", nil
+	}
+
+	seen = make(chan mylib.InputCode)
+	errs = make(chan error, 1)
+
+	go func() {
+		var (
+			ev      input.Event
+			readErr error
+		)
+
+		for {
+			ev, readErr = dev.ReadEvent()
+			if readErr != nil {
+				errs <- readErr
+
+				return
+			}
+
+			if ev.Type == input.EV_KEY {
+				seen <- mylib.InputCode(ev.Code)
+			}
+		}
+	}()
+
+	fmt.Fprintf(os.Stderr, "evlint: press every key and button within %s\n", duration)
+
+	timer = time.NewTimer(duration)
+	defer timer.Stop()
+
+	for len(codes) != 0 {
+		select {
+		case code = <-seen:
+			codes = slices.DeleteFunc(codes, func(c mylib.InputCode) bool {
+				return c == code
+			})
+		case err = <-errs:
+			return "", err
+		case <-timer.C:
+			for _, code = range codes {
+				silent = append(silent, keyName(code))
+			}
+
+			return fmt.Sprintf(
+				"%d advertised keys never emitted: %v",
+				len(silent),
+				silent,
+			), nil
+		}
+	}
+
+	return "", nil
+}
+
+func keyName(code mylib.InputCode) string {
+	var name string
+
+	name = input.KeyName(code)
+	if name == "" {
+		return fmt.Sprintf("KEY_0x%x", code)
+	}
+
+	return name
+}
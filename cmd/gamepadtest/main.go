@@ -0,0 +1,183 @@
+//go:build linux
+
+// Package main implements the gamepadtest CLI, a terminal visualizer for
+// evdev game controllers and an evdev-native replacement for jstest.
+//
+// Usage:
+//
+//	gamepadtest [/dev/input/eventN]
+//
+// Without an argument, it opens the first device with a BTN_SOUTH
+// button. It redraws the state of every button and a bar for every
+// absolute axis after each event frame, until interrupted.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+const barWidth int = 40
+
+type axis struct {
+	code mylib.InputCode
+	info input.AbsInfo
+}
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gamepadtest:", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var (
+		dev     *input.Device
+		name    string
+		buttons []mylib.InputCode
+		axes    []axis
+		reader  *input.SyncReader
+		ev      input.Event
+		err     error
+	)
+
+	dev, err = open()
+	exitIf(err)
+
+	name, err = dev.Name()
+	exitIf(err)
+
+	buttons, err = dev.Codes(input.EV_KEY)
+	exitIf(err)
+
+	axes, err = deviceAxes(dev)
+	exitIf(err)
+
+	reader, err = input.NewSyncReader(dev)
+	exitIf(err)
+
+	// Clear the screen once; every frame then redraws from the top left.
+	fmt.Print("\x1b[2J")
+	draw(name, buttons, axes, reader.State())
+
+	for {
+		ev, err = reader.ReadEvent()
+		exitIf(err)
+
+		if ev.Type == input.EV_SYN && ev.Code == input.SYN_REPORT {
+			draw(name, buttons, axes, reader.State())
+		}
+	}
+}
+
+func open() (*input.Device, error) {
+	var (
+		devs []*input.Device
+		dev  *input.Device
+		err  error
+	)
+
+	if len(os.Args) > 1 {
+		return input.NewDevice(os.Args[1], input.ReadOnly())
+	}
+
+	devs, err = input.Find(input.ByCapability(input.EV_KEY, input.BTN_SOUTH))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(devs) == 0 {
+		return nil, errors.New("no gamepad found")
+	}
+
+	for _, dev = range devs[1:] {
+		_ = dev.Close()
+	}
+
+	return devs[0], nil
+}
+
+func deviceAxes(dev *input.Device) ([]axis, error) {
+	var (
+		codes []mylib.InputCode
+		code  mylib.InputCode
+		axes  []axis
+		info  input.AbsInfo
+		err   error
+	)
+
+	codes, err = dev.Codes(input.EV_ABS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code = range codes {
+		info, err = dev.AbsInfo(code)
+		if err != nil {
+			return nil, err
+		}
+
+		axes = append(axes, axis{code: code, info: info})
+	}
+
+	return axes, nil
+}
+
+func draw(name string, buttons []mylib.InputCode, axes []axis, state *input.State) {
+	var (
+		builder strings.Builder
+		button  mylib.InputCode
+		a       axis
+		mark    string
+	)
+
+	fmt.Fprintf(&builder, "\x1b[H%s\x1b[K\n\nButtons:\x1b[K\n", name)
+
+	for _, button = range buttons {
+		mark = " "
+		if state.Key(button) {
+			mark = "*"
+		}
+
+		fmt.Fprintf(&builder, "  [%s] %s\x1b[K\n", mark, input.KeyName(button))
+	}
+
+	builder.WriteString("\nAxes:\x1b[K\n")
+
+	for _, a = range axes {
+		fmt.Fprintf(
+			&builder,
+			"  %-20s %s %6d\x1b[K\n",
+			input.CodeName(input.EV_ABS, a.code),
+			bar(state.Abs(a.code), a.info),
+			state.Abs(a.code),
+		)
+	}
+
+	builder.WriteString("\x1b[J")
+	fmt.Print(builder.String())
+}
+
+// bar renders value as a bar filled in proportion to its position within
+// the axis range.
+func bar(value int32, info input.AbsInfo) string {
+	var (
+		span   int64
+		filled int
+	)
+
+	span = int64(info.Maximum) - int64(info.Minimum)
+	if span > 0 {
+		filled = int((int64(value) - int64(info.Minimum)) * int64(barWidth) / span)
+	}
+
+	filled = min(max(filled, 0), barWidth)
+
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", barWidth-filled) + "]"
+}
@@ -0,0 +1,170 @@
+//go:build linux
+
+// Package main implements the idle CLI, which lets shell scripts react to
+// user inactivity without a compositor, for example to lock the screen or
+// pause playback.
+//
+// Usage:
+//
+//	idle [-timeout 5m] wait
+//	idle active
+//	idle [-timeout 5m] watch
+//
+// The wait command blocks until no input arrives for the timeout. The
+// active command blocks until the next input. The watch command runs
+// until interrupted, printing "idle" when the timeout passes without
+// input and "active N" when input resumes after N seconds of inactivity.
+//
+// Input is read from every /dev/input/event* device the user may open.
+// Inactivity is measured from the moment idle starts, as evdev keeps no
+// record of earlier input.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "idle:", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var (
+		timeout  *time.Duration
+		activity <-chan time.Time
+		last     time.Time
+		err      error
+	)
+
+	timeout = flag.Duration("timeout", 5*time.Minute, "inactivity after which the user is idle")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: idle [-timeout duration] wait|active|watch")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	activity, err = watchDevices()
+	exitIf(err)
+
+	switch flag.Arg(0) {
+	case "wait":
+		waitIdle(activity, *timeout)
+	case "active":
+		<-activity
+	case "watch":
+		for {
+			last = waitIdle(activity, *timeout)
+			fmt.Println("idle")
+
+			fmt.Printf("active %d\n", int((<-activity).Sub(last).Seconds()))
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// watchDevices opens every event device the user may read and returns a
+// channel receiving the time of each input event.
+func watchDevices() (<-chan time.Time, error) {
+	var (
+		paths    []string
+		path     string
+		dev      *input.Device
+		activity chan time.Time
+		opened   int
+		err      error
+	)
+
+	paths, err = input.NewSystem(nil).EventNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	activity = make(chan time.Time, 1)
+
+	for _, path = range paths {
+		dev, err = input.NewDevice(path, input.ReadOnly())
+		if errors.Is(err, fs.ErrPermission) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		opened++
+
+		go readDevice(dev, activity)
+	}
+
+	if opened == 0 {
+		return nil, errors.New("no readable input devices; see inputdevices -diagnose")
+	}
+
+	return activity, nil
+}
+
+func readDevice(dev *input.Device, activity chan<- time.Time) {
+	var (
+		ev  input.Event
+		err error
+	)
+
+	for {
+		ev, err = dev.ReadEvent()
+		if err != nil {
+			// The device was unplugged; the remaining devices keep
+			// reporting activity.
+			_ = dev.Close()
+
+			return
+		}
+
+		if ev.Type == input.EV_SYN {
+			continue
+		}
+
+		select {
+		case activity <- time.Now():
+		default:
+		}
+	}
+}
+
+// waitIdle blocks until no activity is received for timeout and returns
+// the time of the last activity, or of the call if there was none.
+func waitIdle(activity <-chan time.Time, timeout time.Duration) time.Time {
+	var (
+		timer *time.Timer
+		last  time.Time
+	)
+
+	last = time.Now()
+	timer = time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case last = <-activity:
+			timer.Reset(timeout)
+		case <-timer.C:
+			return last
+		}
+	}
+}
@@ -2,16 +2,33 @@
 
 package main
 
-import "github.com/andrieee44/mylib/linux/input"
+import (
+	"strings"
 
-var devices []*input.Device = func() []*input.Device {
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+func devices() ([]mylib.InputDevice, error) {
+	return input.InputDevices()
+}
+
+func diagnose() (string, error) {
 	var (
-		devs []*input.Device
-		err  error
+		diags   []*input.Diagnosis
+		diag    *input.Diagnosis
+		builder strings.Builder
+		err     error
 	)
 
-	devs, err = input.Devices()
-	exitIf(err)
+	diags, err = input.Diagnoses()
+	if err != nil {
+		return "", err
+	}
+
+	for _, diag = range diags {
+		builder.WriteString(diag.String())
+	}
 
-	return devs
-}()
+	return builder.String(), nil
+}
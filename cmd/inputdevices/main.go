@@ -3,9 +3,14 @@
 //
 // It enumerates all available devices, retrieves their ID and name, prints
 // the results to standard output, and closes each device handle.
+//
+// With the -diagnose flag, it instead explains why device nodes cannot be
+// opened, checking ownership, modes, group membership, and ACLs, and
+// suggests fixes.
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"strings"
@@ -22,22 +27,38 @@ func exitIf(err error) {
 
 func main() {
 	var (
-		devs     []mylib.InputDevice
-		dev      mylib.InputDevice
-		id, name string
-		events   []mylib.InputEvent
-		event    mylib.InputEvent
-		codes    []mylib.InputCode
-		code     mylib.InputCode
-		builder  strings.Builder
-		err      error
+		diagnoseFlag *bool
+		report       string
+		devs         []mylib.InputDevice
+		dev          mylib.InputDevice
+		id, name     string
+		events       []mylib.InputEvent
+		event        mylib.InputEvent
+		codes        []mylib.InputCode
+		code         mylib.InputCode
+		builder      strings.Builder
+		err          error
+	)
+
+	diagnoseFlag = flag.Bool(
+		"diagnose",
+		false,
+		"explain why input devices cannot be opened and suggest fixes",
 	)
+	flag.Parse()
 
-	devs = make([]mylib.InputDevice, 0, len(devices))
-	for _, dev = range devices {
-		devs = append(devs, dev)
+	if *diagnoseFlag {
+		report, err = diagnose()
+		exitIf(err)
+
+		fmt.Print(report)
+
+		return
 	}
 
+	devs, err = devices()
+	exitIf(err)
+
 	for _, dev = range devs {
 		id, err = dev.ID()
 		exitIf(err)
@@ -55,10 +76,16 @@ func main() {
 			codes, err = dev.Codes(event)
 			exitIf(err)
 
-			builder.WriteString(fmt.Sprintf("  Event Type %d (TBD):\n", event))
+			builder.WriteString(
+				fmt.Sprintf("  Event Type %d (%s):\n", event, mylib.EventName(event)),
+			)
 
 			for _, code = range codes {
-				builder.WriteString(fmt.Sprintf("    Event code %d (TBD)\n", code))
+				builder.WriteString(fmt.Sprintf(
+					"    Event code %d (%s)\n",
+					code,
+					mylib.CodeName(event, code),
+				))
 			}
 		}
 
@@ -0,0 +1,90 @@
+//go:build linux
+
+// Package main implements the keymapdump CLI, which prints the full
+// scancode to keycode table of an evdev device.
+//
+// Usage:
+//
+//	keymapdump /dev/input/eventN > keymap.txt
+//
+// Each line holds a scancode in hexadecimal and the name of its keycode,
+// such as "0x7001e KEY_1". The output can be loaded back, on the same or
+// another machine, with keymapload.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "keymapdump:", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var (
+		dev      *input.Device
+		name, id string
+		entries  []input.KeymapEntry
+		entry    input.KeymapEntry
+		scancode uint64
+		ok       bool
+		out      *bufio.Writer
+		err      error
+	)
+
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: keymapdump /dev/input/eventN")
+		os.Exit(2)
+	}
+
+	dev, err = input.NewDevice(os.Args[1], input.ReadOnly())
+	exitIf(err)
+
+	name, err = dev.Name()
+	exitIf(err)
+
+	id, err = dev.ID()
+	exitIf(err)
+
+	entries, err = dev.Keymap()
+	exitIf(err)
+
+	err = dev.Close()
+	exitIf(err)
+
+	out = bufio.NewWriter(os.Stdout)
+	fmt.Fprintf(out, "# %s (%s)\n", name, id)
+
+	for _, entry = range entries {
+		scancode, ok = entry.ScancodeValue()
+		if !ok {
+			fmt.Fprintf(out, "# index %d: %d-byte scancode skipped\n", entry.Index, entry.Len)
+
+			continue
+		}
+
+		fmt.Fprintf(out, "0x%x %s\n", scancode, keyName(mylib.InputCode(entry.Keycode)))
+	}
+
+	err = out.Flush()
+	exitIf(err)
+}
+
+func keyName(code mylib.InputCode) string {
+	var name string
+
+	name = input.KeyName(code)
+	if name == "" {
+		return fmt.Sprint(code)
+	}
+
+	return name
+}
@@ -0,0 +1,120 @@
+//go:build linux
+
+// Package main implements the keymapload CLI, which loads a scancode to
+// keycode table written by keymapdump into an evdev device.
+//
+// Usage:
+//
+//	keymapload /dev/input/eventN keymap.txt
+//
+// Each line holds a scancode, in any base accepted by Go integer
+// literals, and a KEY_* or BTN_* name or a decimal keycode. Empty lines
+// and lines starting with '#' are ignored. The table stays in effect
+// until the driver is reloaded.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "keymapload:", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var (
+		dev      *input.Device
+		file     *os.File
+		scanner  *bufio.Scanner
+		line     string
+		lineNum  int
+		scancode uint32
+		keycode  mylib.InputCode
+		err      error
+	)
+
+	if len(os.Args) != 3 {
+		fmt.Fprintln(os.Stderr, "usage: keymapload /dev/input/eventN keymap.txt")
+		os.Exit(2)
+	}
+
+	file, err = os.Open(os.Args[2])
+	exitIf(err)
+
+	dev, err = input.NewDevice(os.Args[1])
+	exitIf(err)
+
+	scanner = bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineNum++
+		line = strings.TrimSpace(scanner.Text())
+
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		scancode, keycode, err = parseLine(line)
+		if err != nil {
+			exitIf(fmt.Errorf("%s:%d: %w", os.Args[2], lineNum, err))
+		}
+
+		err = dev.SetKeymapEntry(input.NewKeymapEntry(scancode, keycode))
+		if err != nil {
+			exitIf(fmt.Errorf("%s:%d: %w", os.Args[2], lineNum, err))
+		}
+	}
+
+	exitIf(scanner.Err())
+
+	err = file.Close()
+	exitIf(err)
+
+	err = dev.Close()
+	exitIf(err)
+}
+
+func parseLine(line string) (scancode uint32, keycode mylib.InputCode, err error) {
+	var (
+		fields    []string
+		value     uint64
+		eventType mylib.InputEvent
+	)
+
+	fields = strings.Fields(line)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected scancode and keycode, got %q", line)
+	}
+
+	value, err = strconv.ParseUint(fields[0], 0, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	scancode = uint32(value)
+
+	value, err = strconv.ParseUint(fields[1], 10, 16)
+	if err == nil {
+		return scancode, mylib.InputCode(value), nil
+	}
+
+	eventType, keycode, err = input.CodeByName(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if eventType != input.EV_KEY {
+		return 0, 0, fmt.Errorf("%s is not a key", fields[1])
+	}
+
+	return scancode, keycode, nil
+}
@@ -0,0 +1,340 @@
+//go:build linux
+
+// Package main implements the sysmon CLI, which prints a summary of
+// batteries, backlights, thermal zones, rfkill switches, and hwmon
+// sensors read from sysfs.
+//
+// Usage:
+//
+//	sysmon [-json] [-watch interval]
+//
+// By default it prints the summary once as text. With -json, it prints a
+// JSON object instead, and with -watch, it prints a new summary every
+// interval until interrupted.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type summary struct {
+	Batteries  []battery   `json:"batteries"`
+	Backlights []backlight `json:"backlights"`
+	Thermal    []thermal   `json:"thermal"`
+	RFKill     []rfkill    `json:"rfkill"`
+	Hwmon      []hwmon     `json:"hwmon"`
+}
+
+type battery struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Capacity int    `json:"capacity"`
+}
+
+type backlight struct {
+	Name       string `json:"name"`
+	Brightness int    `json:"brightness"`
+	Max        int    `json:"max"`
+}
+
+type thermal struct {
+	Zone    string  `json:"zone"`
+	Type    string  `json:"type"`
+	Celsius float64 `json:"celsius"`
+}
+
+type rfkill struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Soft bool   `json:"soft_blocked"`
+	Hard bool   `json:"hard_blocked"`
+}
+
+type hwmon struct {
+	Name    string   `json:"name"`
+	Sensors []sensor `json:"sensors"`
+}
+
+type sensor struct {
+	Label string  `json:"label"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit"`
+}
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "sysmon:", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var (
+		asJSON *bool
+		watch  *time.Duration
+		err    error
+	)
+
+	asJSON = flag.Bool("json", false, "print JSON instead of text")
+	watch = flag.Duration("watch", 0, "print a new summary at this interval")
+	flag.Parse()
+
+	for {
+		err = report(collect(), *asJSON)
+		exitIf(err)
+
+		if *watch <= 0 {
+			return
+		}
+
+		time.Sleep(*watch)
+	}
+}
+
+func collect() summary {
+	return summary{
+		Batteries:  batteries(),
+		Backlights: backlights(),
+		Thermal:    thermalZones(),
+		RFKill:     rfkills(),
+		Hwmon:      hwmons(),
+	}
+}
+
+func report(sum summary, asJSON bool) error {
+	var (
+		builder strings.Builder
+		bat     battery
+		bl      backlight
+		zone    thermal
+		rf      rfkill
+		mon     hwmon
+		s       sensor
+		err     error
+	)
+
+	if asJSON {
+		return json.NewEncoder(os.Stdout).Encode(sum)
+	}
+
+	for _, bat = range sum.Batteries {
+		fmt.Fprintf(&builder, "battery %s: %d%% %s\n", bat.Name, bat.Capacity, bat.Status)
+	}
+
+	for _, bl = range sum.Backlights {
+		fmt.Fprintf(
+			&builder,
+			"backlight %s: %d/%d (%d%%)\n",
+			bl.Name,
+			bl.Brightness,
+			bl.Max,
+			bl.Brightness*100/max(bl.Max, 1),
+		)
+	}
+
+	for _, zone = range sum.Thermal {
+		fmt.Fprintf(&builder, "thermal %s (%s): %.1f°C\n", zone.Zone, zone.Type, zone.Celsius)
+	}
+
+	for _, rf = range sum.RFKill {
+		fmt.Fprintf(
+			&builder,
+			"rfkill %s (%s): soft %s, hard %s\n",
+			rf.Name,
+			rf.Type,
+			blocked(rf.Soft),
+			blocked(rf.Hard),
+		)
+	}
+
+	for _, mon = range sum.Hwmon {
+		for _, s = range mon.Sensors {
+			fmt.Fprintf(&builder, "hwmon %s %s: %g %s\n", mon.Name, s.Label, s.Value, s.Unit)
+		}
+	}
+
+	_, err = fmt.Print(builder.String())
+
+	return err
+}
+
+func blocked(b bool) string {
+	if b {
+		return "blocked"
+	}
+
+	return "unblocked"
+}
+
+func batteries() []battery {
+	var (
+		bats []battery
+		dir  string
+	)
+
+	for _, dir = range glob("/sys/class/power_supply/*") {
+		if readString(dir, "type") != "Battery" {
+			continue
+		}
+
+		bats = append(bats, battery{
+			Name:     filepath.Base(dir),
+			Status:   readString(dir, "status"),
+			Capacity: readInt(dir, "capacity"),
+		})
+	}
+
+	return bats
+}
+
+func backlights() []backlight {
+	var (
+		bls []backlight
+		dir string
+	)
+
+	for _, dir = range glob("/sys/class/backlight/*") {
+		bls = append(bls, backlight{
+			Name:       filepath.Base(dir),
+			Brightness: readInt(dir, "actual_brightness"),
+			Max:        readInt(dir, "max_brightness"),
+		})
+	}
+
+	return bls
+}
+
+func thermalZones() []thermal {
+	var (
+		zones []thermal
+		dir   string
+	)
+
+	for _, dir = range glob("/sys/class/thermal/thermal_zone*") {
+		zones = append(zones, thermal{
+			Zone:    filepath.Base(dir),
+			Type:    readString(dir, "type"),
+			Celsius: float64(readInt(dir, "temp")) / 1000,
+		})
+	}
+
+	return zones
+}
+
+func rfkills() []rfkill {
+	var (
+		rfs []rfkill
+		dir string
+	)
+
+	for _, dir = range glob("/sys/class/rfkill/rfkill*") {
+		rfs = append(rfs, rfkill{
+			Name: readString(dir, "name"),
+			Type: readString(dir, "type"),
+			Soft: readInt(dir, "soft") != 0,
+			Hard: readInt(dir, "hard") != 0,
+		})
+	}
+
+	return rfs
+}
+
+func hwmons() []hwmon {
+	var (
+		mons  []hwmon
+		mon   hwmon
+		dir   string
+		input string
+		base  string
+		kind  string
+		label string
+		scale float64
+		unit  string
+	)
+
+	for _, dir = range glob("/sys/class/hwmon/hwmon*") {
+		mon = hwmon{Name: readString(dir, "name")}
+
+		for _, input = range glob(filepath.Join(dir, "*_input")) {
+			base = strings.TrimSuffix(filepath.Base(input), "_input")
+			kind = strings.TrimRightFunc(
+				base,
+				func(r rune) bool { return r >= '0' && r <= '9' },
+			)
+
+			switch kind {
+			case "temp":
+				scale, unit = 1000, "°C"
+			case "fan":
+				scale, unit = 1, "RPM"
+			case "in":
+				scale, unit = 1000, "V"
+			case "power":
+				scale, unit = 1e6, "W"
+			case "curr":
+				scale, unit = 1000, "A"
+			default:
+				continue
+			}
+
+			label = readString(dir, base+"_label")
+			if label == "" {
+				label = base
+			}
+
+			mon.Sensors = append(mon.Sensors, sensor{
+				Label: label,
+				Value: float64(readInt(dir, base+"_input")) / scale,
+				Unit:  unit,
+			})
+		}
+
+		mons = append(mons, mon)
+	}
+
+	return mons
+}
+
+func glob(pattern string) []string {
+	var paths []string
+
+	// The only possible error is a malformed pattern.
+	paths, _ = filepath.Glob(pattern)
+	sort.Strings(paths)
+
+	return paths
+}
+
+// readString returns the trimmed contents of a sysfs attribute, or an
+// empty string if it cannot be read.
+func readString(dir, name string) string {
+	var (
+		data []byte
+		err  error
+	)
+
+	data, err = os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+// readInt returns a sysfs attribute as an integer, or 0 if it cannot be
+// read or parsed.
+func readInt(dir, name string) int {
+	var value int
+
+	value, _ = strconv.Atoi(readString(dir, name))
+
+	return value
+}
@@ -0,0 +1,167 @@
+//go:build linux
+
+// Package main implements the ueventwatch CLI, which prints kernel
+// uevents as they arrive, for debugging hotplug behavior and writing
+// udev rules.
+//
+// Usage:
+//
+//	ueventwatch [-subsystem input,usb] [-action add,remove] [-json]
+//
+// Events are read from the kernel's NETLINK_KOBJECT_UEVENT socket, so
+// they are seen before udev processes them. Each event is printed as its
+// action, device path, and properties, or as one JSON object per line
+// with -json.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// The kernel multicast group of NETLINK_KOBJECT_UEVENT. udev rebroadcasts
+// processed events on group 2.
+const kernelGroup = 1
+
+type uevent struct {
+	Action     string            `json:"action"`
+	DevPath    string            `json:"devpath"`
+	Subsystem  string            `json:"subsystem"`
+	Properties map[string]string `json:"properties"`
+}
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "ueventwatch:", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var (
+		subsystems *string
+		actions    *string
+		asJSON     *bool
+		fd         int
+		buf        []byte
+		n          int
+		event      uevent
+		ok         bool
+		err        error
+	)
+
+	subsystems = flag.String("subsystem", "", "comma-separated subsystems to show")
+	actions = flag.String("action", "", "comma-separated actions to show")
+	asJSON = flag.Bool("json", false, "print one JSON object per event")
+	flag.Parse()
+
+	fd, err = unix.Socket(unix.AF_NETLINK, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, unix.NETLINK_KOBJECT_UEVENT)
+	exitIf(err)
+
+	err = unix.Bind(fd, &unix.SockaddrNetlink{Family: unix.AF_NETLINK, Groups: kernelGroup})
+	exitIf(err)
+
+	buf = make([]byte, 64*1024)
+
+	for {
+		n, _, err = unix.Recvfrom(fd, buf, 0)
+		if err == unix.EINTR {
+			continue
+		}
+
+		exitIf(err)
+
+		event, ok = parse(buf[:n])
+		if !ok || !matches(*subsystems, event.Subsystem) || !matches(*actions, event.Action) {
+			continue
+		}
+
+		exitIf(report(event, *asJSON))
+	}
+}
+
+// parse decodes a kernel uevent, which is a header of the form
+// "action@devpath" followed by NUL-separated KEY=value properties.
+func parse(msg []byte) (uevent, bool) {
+	var (
+		fields [][]byte
+		field  []byte
+		key    string
+		value  string
+		found  bool
+		event  uevent
+	)
+
+	fields = bytes.Split(bytes.TrimRight(msg, "\x00"), []byte{0})
+	if len(fields) == 0 || !bytes.ContainsRune(fields[0], '@') {
+		return uevent{}, false
+	}
+
+	event.Properties = make(map[string]string)
+
+	for _, field = range fields[1:] {
+		key, value, found = strings.Cut(string(field), "=")
+		if found {
+			event.Properties[key] = value
+		}
+	}
+
+	event.Action = event.Properties["ACTION"]
+	event.DevPath = event.Properties["DEVPATH"]
+	event.Subsystem = event.Properties["SUBSYSTEM"]
+
+	return event, true
+}
+
+// matches reports whether value is in the comma-separated filter list.
+// An empty filter matches everything.
+func matches(filter, value string) bool {
+	var item string
+
+	if filter == "" {
+		return true
+	}
+
+	for _, item = range strings.Split(filter, ",") {
+		if strings.TrimSpace(item) == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func report(event uevent, asJSON bool) error {
+	var (
+		builder strings.Builder
+		keys    []string
+		key     string
+		err     error
+	)
+
+	if asJSON {
+		return json.NewEncoder(os.Stdout).Encode(event)
+	}
+
+	for key = range event.Properties {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	fmt.Fprintf(&builder, "%s %s (%s)\n", event.Action, event.DevPath, event.Subsystem)
+
+	for _, key = range keys {
+		fmt.Fprintf(&builder, "    %s=%s\n", key, event.Properties[key])
+	}
+
+	_, err = fmt.Print(builder.String())
+
+	return err
+}
@@ -0,0 +1,136 @@
+//go:build linux
+
+// Package main implements the watchconfig CLI, which runs a command
+// whenever an application's configuration file changes, for example to
+// live-reload a status bar or window manager from dotfiles.
+//
+// Usage:
+//
+//	watchconfig [-debounce 300ms] app/app.conf command [args...]
+//
+// The relative path is watched in $XDG_CONFIG_HOME and in every
+// directory of $XDG_CONFIG_DIRS that exists. Bursts of changes, such as
+// an editor writing a backup and then the file, are coalesced: the
+// command runs once the file has been quiet for the debounce interval.
+// The changed file is passed to the command in $WATCHCONFIG_FILE.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/andrieee44/mylib/linux/xdg"
+	"github.com/andrieee44/mylib/x/linux/config"
+)
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "watchconfig:", err)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	var (
+		debounce *time.Duration
+		ctx      context.Context
+		stop     context.CancelFunc
+		paths    []string
+		changes  <-chan string
+		changed  string
+		path     string
+		ok       bool
+		timer    *time.Timer
+		err      error
+	)
+
+	debounce = flag.Duration("debounce", 300*time.Millisecond, "quiet time before running the command")
+	flag.Usage = func() {
+		fmt.Fprintln(
+			flag.CommandLine.Output(),
+			"usage: watchconfig [-debounce duration] relpath command [args...]",
+		)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	paths = candidates(flag.Arg(0))
+	if len(paths) == 0 {
+		exitIf(errors.New("no XDG config directory exists for " + flag.Arg(0)))
+	}
+
+	ctx, stop = signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	changes, err = config.Watch(ctx, paths...)
+	exitIf(err)
+
+	timer = time.NewTimer(0)
+	<-timer.C
+
+	for {
+		select {
+		case path, ok = <-changes:
+			if !ok {
+				return
+			}
+
+			changed = path
+			timer.Reset(*debounce)
+		case <-timer.C:
+			err = run(changed, flag.Args()[1:])
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "watchconfig:", err)
+			}
+		}
+	}
+}
+
+// candidates returns the paths of relPath in the XDG config directories
+// whose parent directory exists.
+func candidates(relPath string) []string {
+	var (
+		dirs  []string
+		dir   string
+		path  string
+		paths []string
+		err   error
+	)
+
+	dirs = append([]string{xdg.ConfigHome()}, filepath.SplitList(xdg.ConfigDirs())...)
+
+	for _, dir = range dirs {
+		path = filepath.Join(dir, relPath)
+
+		_, err = os.Stat(filepath.Dir(path))
+		if err == nil {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths
+}
+
+func run(path string, args []string) error {
+	var cmd *exec.Cmd
+
+	cmd = exec.Command(args[0], args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "WATCHCONFIG_FILE="+path)
+
+	return cmd.Run()
+}
@@ -0,0 +1,47 @@
+//go:build darwin && cgo
+
+// Package input forwards to [github.com/andrieee44/mylib/x/darwin/input],
+// where the experimental macOS backend moved, so that existing imports
+// keep building.
+//
+// Deprecated: Import github.com/andrieee44/mylib/x/darwin/input instead.
+// This package will be removed in a future release.
+package input
+
+import "github.com/andrieee44/mylib/x/darwin/input"
+
+var (
+	// ErrClosed is [input.ErrClosed].
+	ErrClosed error = input.ErrClosed
+
+	// ErrInvalidEventType is [input.ErrInvalidEventType].
+	ErrInvalidEventType error = input.ErrInvalidEventType
+
+	// ErrHIDManager is [input.ErrHIDManager].
+	ErrHIDManager error = input.ErrHIDManager
+)
+
+// Device is [input.Device].
+type Device = input.Device
+
+// The event types and codes of [input].
+const (
+	EV_SYN = input.EV_SYN
+	EV_KEY = input.EV_KEY
+	EV_REL = input.EV_REL
+	EV_ABS = input.EV_ABS
+
+	SYN_REPORT = input.SYN_REPORT
+
+	KEY_A = input.KEY_A
+
+	BTN_MISC     = input.BTN_MISC
+	BTN_MOUSE    = input.BTN_MOUSE
+	BTN_JOYSTICK = input.BTN_JOYSTICK
+	BTN_GAMEPAD  = input.BTN_GAMEPAD
+)
+
+// Devices calls [input.Devices].
+func Devices() ([]*Device, error) {
+	return input.Devices()
+}
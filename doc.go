@@ -1,2 +1,34 @@
 // Package mylib is my personal collection of small libraries.
+//
+// # Device classes
+//
+// Backends return their devices as [InputDevice] values, which
+// applications type-assert to [Keyboard], [Pointer], [Gamepad], and
+// [Touchscreen] to reach class-specific methods. A device may implement
+// several of them, such as a keyboard with a built-in touchpad. Only
+// the linux/input backend implements the classes, through its Classify
+// function, which its enumeration and hotplug monitor apply. The
+// x/windows/input and x/darwin/input backends do not, so their devices
+// only implement [InputDevice].
+//
+// # Compatibility
+//
+// The following packages make up the supported API and follow semantic
+// versioning: from v1 on, exported identifiers are only removed or
+// changed incompatibly in a new major version, after being marked
+// deprecated for at least one minor release.
+//
+//   - mylib, the platform-independent interfaces in this package
+//   - linux/input
+//   - linux/ioctl
+//   - linux/xdg
+//
+// Experimental packages live under x, mirroring the layout above, and
+// may change in any release: x/linux/config, x/linux/sandbox,
+// x/linux/input/calibration, x/linux/input/gestures, x/windows/input,
+// and x/darwin/input. Their package documentation says so. Their former
+// paths outside x, such as linux/config, hold deprecated packages that
+// forward to them and will be removed in a future release. The commands
+// under cmd are programs, not APIs, and their flags and output may
+// change at any time.
 package mylib
@@ -0,0 +1,36 @@
+package mylib_test
+
+import (
+	"fmt"
+
+	"github.com/andrieee44/mylib"
+)
+
+// ledNamer names the events of a made-up backend with a single LED.
+type ledNamer struct{}
+
+func (ledNamer) EventName(eventType mylib.InputEvent) string {
+	if eventType == 0x11 {
+		return "EV_LED"
+	}
+
+	return ""
+}
+
+func (ledNamer) CodeName(eventType mylib.InputEvent, code mylib.InputCode) string {
+	if eventType == 0x11 && code == 0 {
+		return "LED_NUML"
+	}
+
+	return ""
+}
+
+func ExampleRegisterNamer() {
+	mylib.RegisterNamer(ledNamer{})
+
+	fmt.Println(mylib.EventName(0x11), mylib.CodeName(0x11, 0))
+	fmt.Println(mylib.EventName(0x12), mylib.CodeName(0x11, 1))
+	// Output:
+	// EV_LED LED_NUML
+	// 18 1
+}
@@ -0,0 +1,85 @@
+package mylib
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnsupportedEffect is returned by [HapticEffects.UploadHaptic] for
+// effects the device cannot play.
+var ErrUnsupportedEffect error = errors.New("unsupported haptic effect")
+
+// Haptics is implemented by devices with vibration motors, such as most
+// gamepads. Applications type-assert an [InputDevice] to it to vibrate
+// the device without depending on a backend.
+type Haptics interface {
+	InputDevice
+
+	// Rumble vibrates the heavy, low-frequency motor at the strong
+	// magnitude and the light, high-frequency motor at the weak one for
+	// duration. It returns immediately; the vibration stops by itself.
+	Rumble(strong, weak uint16, duration time.Duration) error
+}
+
+// HapticKind is the shape of a [HapticEffect].
+type HapticKind int
+
+const (
+	// HapticRumble drives the two motors of a gamepad at the Strong and
+	// Weak magnitudes.
+	HapticRumble HapticKind = iota
+
+	// HapticConstant applies a constant force of Level.
+	HapticConstant
+
+	// HapticSine applies a sine wave of amplitude Level.
+	HapticSine
+
+	// HapticSquare applies a square wave of amplitude Level.
+	HapticSquare
+
+	// HapticTriangle applies a triangle wave of amplitude Level.
+	HapticTriangle
+)
+
+// HapticEffect is a vibration effect for [HapticEffects.UploadHaptic].
+type HapticEffect struct {
+	// Kind is the shape of the effect.
+	Kind HapticKind
+
+	// Strong and Weak are the magnitudes of the heavy and light motors
+	// of a [HapticRumble] effect.
+	Strong, Weak uint16
+
+	// Level is the force of the other kinds, from -32768 to 32767.
+	Level int16
+
+	// Period is the duration of one cycle of a wave.
+	Period time.Duration
+
+	// Duration is how long the effect plays for each repetition.
+	Duration time.Duration
+
+	// Delay is the pause before the effect starts playing.
+	Delay time.Duration
+}
+
+// HapticEffects is implemented by [Haptics] devices able to hold effects
+// and play them on demand, such as force-feedback wheels and joysticks.
+type HapticEffects interface {
+	Haptics
+
+	// UploadHaptic stores effect on the device and returns its ID. It
+	// returns [ErrUnsupportedEffect] if the device cannot play it.
+	UploadHaptic(effect HapticEffect) (int, error)
+
+	// PlayHaptic starts playing an uploaded effect, repeating it count
+	// times.
+	PlayHaptic(id, count int) error
+
+	// StopHaptic stops playing an uploaded effect.
+	StopHaptic(id int) error
+
+	// EraseHaptic removes an uploaded effect from the device.
+	EraseHaptic(id int) error
+}
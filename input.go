@@ -1,5 +1,7 @@
 package mylib
 
+import "time"
+
 // InputEvent identifies a category of input events.
 type InputEvent uint
 
@@ -38,3 +40,33 @@ type InputDevice interface {
 	// After Close returns, no other methods should be called.
 	Close() error
 }
+
+// Event is a platform-independent input event.
+type Event struct {
+	// Time is when the event was generated.
+	Time time.Time
+
+	// Type is the category of the event, such as keys or relative axes,
+	// one of the values returned by [InputDevice.Events].
+	Type InputEvent
+
+	// Code is the input within Type that changed, one of the values
+	// returned by [InputDevice.Codes].
+	Code InputCode
+
+	// Value is the new state of the input. For keys and buttons, 0 means
+	// released, 1 pressed, and 2 auto-repeated; for axes, it is the
+	// position or the relative motion.
+	Value int32
+}
+
+// EventReader is an [InputDevice] that delivers its events.
+type EventReader interface {
+	InputDevice
+
+	// ReadInput blocks until the next event of the device is available
+	// and returns it. It returns an error once the device has failed or
+	// been closed; closing the device also interrupts a blocked call if
+	// the backend supports it.
+	ReadInput() (Event, error)
+}
@@ -0,0 +1,44 @@
+package inputtest_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/inputtest"
+)
+
+func ExampleFakeDevice() {
+	const (
+		evKey = 1
+		keyA  = 30
+	)
+
+	var (
+		dev    *inputtest.FakeDevice
+		events <-chan mylib.Event
+		errs   <-chan error
+		ev     mylib.Event
+	)
+
+	dev = inputtest.NewFakeDevice("Test Keyboard", "fake 1")
+	dev.SetCodes(evKey, keyA)
+	dev.Push(
+		mylib.Event{Type: evKey, Code: keyA, Value: 1},
+		mylib.Event{Type: evKey, Code: keyA, Value: 0},
+	)
+	dev.Fail(io.EOF)
+
+	events, errs = mylib.Stream(context.Background(), dev)
+
+	for ev = range events {
+		fmt.Println(ev.Type, ev.Code, ev.Value)
+	}
+
+	fmt.Println(<-errs)
+	// Output:
+	// 1 30 1
+	// 1 30 0
+	// mylib.Stream: FakeDevice.ReadInput: EOF
+}
@@ -0,0 +1,202 @@
+// Package inputtest provides a fake [mylib.InputDevice] for testing
+// input handling without hardware or root:
+//
+//	dev := inputtest.NewFakeDevice("Test Keyboard", "fake 1")
+//	dev.SetCodes(input.EV_KEY, input.KEY_A)
+//	dev.Push(mylib.Event{Type: input.EV_KEY, Code: input.KEY_A, Value: 1})
+//	dev.Fail(io.EOF)
+//
+//	err := handleInput(dev) // reads KEY_A, then io.EOF
+package inputtest
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/andrieee44/mylib"
+)
+
+// ErrClosed is returned by the methods of a [FakeDevice] once it is
+// closed.
+var ErrClosed error = errors.New("device closed")
+
+// ErrInvalidEventType is returned by [FakeDevice.Codes] for event types
+// the device was not given codes for.
+var ErrInvalidEventType error = errors.New("invalid event type")
+
+// FakeDevice is an input device whose capabilities and events are set
+// by the test. It is safe for concurrent use, so a test can push events
+// while the code under test reads them.
+type FakeDevice struct {
+	mutex  sync.Mutex
+	cond   sync.Cond
+	name   string
+	id     string
+	codes  map[mylib.InputEvent][]mylib.InputCode
+	events []mylib.Event
+	err    error
+	closes int
+}
+
+var _ mylib.EventReader = (*FakeDevice)(nil)
+
+// NewFakeDevice returns a FakeDevice with the given name and ID and no
+// capabilities.
+func NewFakeDevice(name, id string) *FakeDevice {
+	var dev *FakeDevice
+
+	dev = &FakeDevice{
+		name:  name,
+		id:    id,
+		codes: make(map[mylib.InputEvent][]mylib.InputCode),
+	}
+	dev.cond.L = &dev.mutex
+
+	return dev
+}
+
+// SetCodes makes the device support eventType with the given codes,
+// replacing those set before.
+func (dev *FakeDevice) SetCodes(eventType mylib.InputEvent, codes ...mylib.InputCode) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	dev.codes[eventType] = slices.Sorted(slices.Values(codes))
+}
+
+// Push queues events to be returned by [FakeDevice.ReadInput], waking a
+// blocked reader.
+func (dev *FakeDevice) Push(events ...mylib.Event) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+	defer dev.cond.Broadcast()
+
+	dev.events = append(dev.events, events...)
+}
+
+// Fail makes [FakeDevice.ReadInput] return err once the queued events
+// are read, the way a real device fails when it is unplugged.
+func (dev *FakeDevice) Fail(err error) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+	defer dev.cond.Broadcast()
+
+	dev.err = err
+}
+
+// Pending returns the number of queued events not read yet.
+func (dev *FakeDevice) Pending() int {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	return len(dev.events)
+}
+
+// Closes returns how many times [FakeDevice.Close] was called, for
+// checking that the code under test releases its devices.
+func (dev *FakeDevice) Closes() int {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	return dev.closes
+}
+
+// Name implements [mylib.InputDevice].
+func (dev *FakeDevice) Name() (string, error) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	if dev.closes != 0 {
+		return "", fmt.Errorf("FakeDevice.Name: %w", ErrClosed)
+	}
+
+	return dev.name, nil
+}
+
+// ID implements [mylib.InputDevice].
+func (dev *FakeDevice) ID() (string, error) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	if dev.closes != 0 {
+		return "", fmt.Errorf("FakeDevice.ID: %w", ErrClosed)
+	}
+
+	return dev.id, nil
+}
+
+// Events implements [mylib.InputDevice], returning the event types given
+// to [FakeDevice.SetCodes] in ascending order.
+func (dev *FakeDevice) Events() ([]mylib.InputEvent, error) {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	if dev.closes != 0 {
+		return nil, fmt.Errorf("FakeDevice.Events: %w", ErrClosed)
+	}
+
+	return slices.Sorted(maps.Keys(dev.codes)), nil
+}
+
+// Codes implements [mylib.InputDevice].
+func (dev *FakeDevice) Codes(eventType mylib.InputEvent) ([]mylib.InputCode, error) {
+	var (
+		codes []mylib.InputCode
+		ok    bool
+	)
+
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	if dev.closes != 0 {
+		return nil, fmt.Errorf("FakeDevice.Codes: %w", ErrClosed)
+	}
+
+	codes, ok = dev.codes[eventType]
+	if !ok {
+		return nil, fmt.Errorf("FakeDevice.Codes: %w %d", ErrInvalidEventType, eventType)
+	}
+
+	return slices.Clone(codes), nil
+}
+
+// ReadInput implements [mylib.EventReader]. It returns the next queued
+// event, blocking until one is pushed, the device is made to fail, or
+// it is closed.
+func (dev *FakeDevice) ReadInput() (mylib.Event, error) {
+	var ev mylib.Event
+
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	for len(dev.events) == 0 && dev.err == nil && dev.closes == 0 {
+		dev.cond.Wait()
+	}
+
+	switch {
+	case dev.closes != 0:
+		return mylib.Event{}, fmt.Errorf("FakeDevice.ReadInput: %w", ErrClosed)
+	case len(dev.events) == 0:
+		return mylib.Event{}, fmt.Errorf("FakeDevice.ReadInput: %w", dev.err)
+	}
+
+	ev = dev.events[0]
+	dev.events = dev.events[1:]
+
+	return ev, nil
+}
+
+// Close implements [mylib.InputDevice]. It wakes blocked readers, which
+// then return [ErrClosed]. It may be called more than once.
+func (dev *FakeDevice) Close() error {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+	defer dev.cond.Broadcast()
+
+	dev.closes++
+
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build linux
+
+// Package inotify reads filesystem events with [inotify(7)], for the
+// packages of this module that watch directories, such as /dev/input or
+// the directories of configuration files.
+//
+// [inotify(7)]: https://man7.org/linux/man-pages/man7/inotify.7.html
+package inotify
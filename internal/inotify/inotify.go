@@ -0,0 +1,129 @@
+//go:build linux
+
+package inotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Event is one inotify event.
+type Event struct {
+	// Wd is the watch descriptor returned by [Watcher.Add] for the
+	// watched directory.
+	Wd int
+
+	// Mask holds the IN_* bits describing the event.
+	Mask uint32
+
+	// Name is the name of the affected entry within the watched
+	// directory.
+	Name string
+}
+
+// Watcher is an inotify instance.
+type Watcher struct {
+	file *os.File
+	fd   int
+}
+
+// New returns a new inotify instance. The caller closes it.
+func New() (*Watcher, error) {
+	var (
+		fd  int
+		err error
+	)
+
+	fd, err = unix.InotifyInit1(unix.IN_NONBLOCK | unix.IN_CLOEXEC)
+	if err != nil {
+		return nil, fmt.Errorf("inotify.New: %w", err)
+	}
+
+	// A non-blocking descriptor is handled by the runtime poller, so
+	// closing the file interrupts a pending read.
+	return &Watcher{file: os.NewFile(uintptr(fd), "inotify"), fd: fd}, nil
+}
+
+// Add watches the directory at path for the events in mask and returns
+// the watch descriptor reported in their [Event.Wd].
+func (watcher *Watcher) Add(path string, mask uint32) (int, error) {
+	var (
+		wd  int
+		err error
+	)
+
+	wd, err = unix.InotifyAddWatch(watcher.fd, path, mask)
+	if err != nil {
+		return 0, fmt.Errorf("Watcher.Add: %s: %w", path, err)
+	}
+
+	return wd, nil
+}
+
+// Run calls handle with every event read until handle returns false or
+// ctx is done, in which case it returns nil, or until reading fails, in
+// which case it returns the error. A pending read is interrupted by
+// closing the watcher when ctx is done, so Run leaves it closed then.
+func (watcher *Watcher) Run(ctx context.Context, handle func(Event) bool) error {
+	var (
+		stop   func() bool
+		buf    []byte
+		n      int
+		offset int
+		raw    *unix.InotifyEvent
+		ev     Event
+		err    error
+	)
+
+	stop = context.AfterFunc(ctx, func() {
+		_ = watcher.file.Close()
+	})
+	defer stop()
+
+	buf = make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+
+	for {
+		n, err = watcher.file.Read(buf)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("Watcher.Run: %w", err)
+		}
+
+		for offset = 0; offset+unix.SizeofInotifyEvent <= n; {
+			raw = (*unix.InotifyEvent)(unsafe.Pointer(&buf[offset]))
+			ev = Event{
+				Wd:   int(raw.Wd),
+				Mask: raw.Mask,
+				Name: unix.ByteSliceToString(
+					buf[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(raw.Len)],
+				),
+			}
+			offset += unix.SizeofInotifyEvent + int(raw.Len)
+
+			if !handle(ev) {
+				return nil
+			}
+		}
+	}
+}
+
+// Close closes the inotify instance. Closing it again, for example after
+// [Watcher.Run] closed it, does nothing.
+func (watcher *Watcher) Close() error {
+	var err error
+
+	err = watcher.file.Close()
+	if err != nil && !errors.Is(err, os.ErrClosed) {
+		return fmt.Errorf("Watcher.Close: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build linux
+
+// Package config forwards to [github.com/andrieee44/mylib/x/linux/config],
+// where the experimental configuration loader moved, so that existing
+// imports keep building.
+//
+// Deprecated: Import github.com/andrieee44/mylib/x/linux/config instead.
+// This package will be removed in a future release.
+package config
+
+import (
+	"context"
+
+	"github.com/andrieee44/mylib/x/linux/config"
+)
+
+// ErrIncludeCycle is [config.ErrIncludeCycle].
+var ErrIncludeCycle error = config.ErrIncludeCycle
+
+type (
+	// Config is [config.Config].
+	Config = config.Config
+
+	// Entry is [config.Entry].
+	Entry = config.Entry
+
+	// Loader is [config.Loader].
+	Loader = config.Loader
+
+	// SyntaxError is [config.SyntaxError].
+	SyntaxError = config.SyntaxError
+)
+
+// Find calls [config.Find].
+func Find(relPath string) (*Config, error) {
+	return config.Find(relPath)
+}
+
+// Load calls [config.Load].
+func Load(path string) (*Config, error) {
+	return config.Load(path)
+}
+
+// Watch calls [config.Watch].
+func Watch(ctx context.Context, paths ...string) (<-chan string, error) {
+	return config.Watch(ctx, paths...)
+}
+
+// NewLoader calls [config.NewLoader].
+func NewLoader(getenv func(string) string) *Loader {
+	return config.NewLoader(getenv)
+}
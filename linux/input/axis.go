@@ -0,0 +1,133 @@
+//go:build linux
+
+package input
+
+// NormalizeAbs scales value, an ABS_* value of an axis described by
+// info, to the range -1 to 1, like joydev does for joysticks. Values
+// within info.Flat of the center of the axis read as 0, and the rest of
+// the range is stretched so that the ends of the axis still read as -1
+// and 1.
+func NormalizeAbs(value int32, info AbsInfo) float64 {
+	var (
+		center float64
+		half   float64
+		offset float64
+		flat   float64
+	)
+
+	if info.Maximum <= info.Minimum {
+		return 0
+	}
+
+	center = (float64(info.Minimum) + float64(info.Maximum)) / 2
+	half = (float64(info.Maximum) - float64(info.Minimum)) / 2
+	offset = float64(value) - center
+	flat = min(float64(max(info.Flat, 0)), half)
+
+	switch {
+	case flat == half || offset >= -flat && offset <= flat:
+		return 0
+	case offset > 0:
+		return min((offset-flat)/(half-flat), 1)
+	default:
+		return max((offset+flat)/(half-flat), -1)
+	}
+}
+
+// NormalizeTrigger scales value, an ABS_* value of a trigger or another
+// one-sided axis described by info, to the range 0 to 1. Values within
+// info.Flat of info.Minimum read as 0.
+func NormalizeTrigger(value int32, info AbsInfo) float64 {
+	var (
+		span   float64
+		offset float64
+		flat   float64
+	)
+
+	if info.Maximum <= info.Minimum {
+		return 0
+	}
+
+	span = float64(info.Maximum) - float64(info.Minimum)
+	offset = float64(value) - float64(info.Minimum)
+	flat = min(float64(max(info.Flat, 0)), span)
+
+	if flat == span || offset <= flat {
+		return 0
+	}
+
+	return min((offset-flat)/(span-flat), 1)
+}
+
+// Axis turns the raw values of an absolute axis into normalized ones. It
+// filters noise using info.Fuzz the way the kernel does before
+// delivering events, so a Fuzz larger than the driver's smooths a noisy
+// stick further, and then normalizes the result with [NormalizeAbs], or
+// with [NormalizeTrigger] for triggers.
+type Axis struct {
+	info     AbsInfo
+	trigger  bool
+	inverted bool
+	value    int32
+}
+
+// NewAxis returns an Axis for an axis described by info, such as the
+// result of [Device.AbsInfo], starting at info.Value. trigger selects
+// the range 0 to 1 instead of -1 to 1.
+func NewAxis(info AbsInfo, trigger bool) *Axis {
+	return &Axis{info: info, trigger: trigger, value: info.Value}
+}
+
+// Update filters a new raw value of the axis and returns the normalized
+// position.
+func (axis *Axis) Update(value int32) float64 {
+	var fuzz int32
+
+	fuzz = axis.info.Fuzz
+
+	switch {
+	case fuzz <= 0:
+		axis.value = value
+	case value > axis.value-fuzz/2 && value < axis.value+fuzz/2:
+	case value > axis.value-fuzz && value < axis.value+fuzz:
+		axis.value = (axis.value*3 + value) / 4
+	case value > axis.value-fuzz*2 && value < axis.value+fuzz*2:
+		axis.value = (axis.value + value) / 2
+	default:
+		axis.value = value
+	}
+
+	return axis.Value()
+}
+
+// SetInverted flips the direction of the axis, so that -1 reads as 1 or,
+// for a trigger, 0 reads as 1.
+func (axis *Axis) SetInverted(inverted bool) {
+	axis.inverted = inverted
+}
+
+// Value returns the normalized position of the axis.
+func (axis *Axis) Value() float64 {
+	var value float64
+
+	if axis.trigger {
+		value = NormalizeTrigger(axis.value, axis.info)
+		if axis.inverted {
+			return 1 - value
+		}
+
+		return value
+	}
+
+	value = NormalizeAbs(axis.value, axis.info)
+	if axis.inverted {
+		return -value
+	}
+
+	return value
+}
+
+// Raw returns the filtered raw value of the axis.
+func (axis *Axis) Raw() int32 {
+	return axis.value
+}
@@ -0,0 +1,168 @@
+//go:build linux
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrNoBattery is returned by [Device.Battery] when the device reports no
+// battery.
+var ErrNoBattery error = errors.New("no battery")
+
+// Battery is the charge state of the battery powering a device, as read
+// from its power_supply entry in sysfs.
+type Battery struct {
+	// Name is the name of the power supply, such as
+	// "ps-controller-battery-aa:bb:cc:dd:ee:ff" or "hidpp_battery_0".
+	Name string
+
+	// Capacity is the charge in percent, or -1 if the driver reports only
+	// CapacityLevel.
+	Capacity int
+
+	// CapacityLevel is the coarse charge level, such as "Normal", "Low",
+	// or "Critical", or empty if the driver does not report one.
+	CapacityLevel string
+
+	// Status is the charging status, such as "Charging", "Discharging",
+	// "Full", or "Unknown".
+	Status string
+}
+
+// Battery returns the battery of the device, such as that of a Bluetooth
+// gamepad or of a mouse behind a Logitech receiver. The kernel registers
+// these batteries as power supplies beside the input device in sysfs;
+// Battery finds it by walking up from the device's sysfs node. It
+// returns [ErrNoBattery] if there is none.
+func (dev *Device) Battery() (*Battery, error) {
+	var (
+		stat    unix.Stat_t
+		battery *Battery
+		err     error
+	)
+
+	err = dev.control(func(fd uintptr) error {
+		return unix.Fstat(int(fd), &stat)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Device.Battery: %w", err)
+	}
+
+	battery, err = NewSystem(nil).battery(fmt.Sprintf(
+		"sys/dev/char/%d:%d",
+		unix.Major(uint64(stat.Rdev)),
+		unix.Minor(uint64(stat.Rdev)),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("Device.Battery: %w", err)
+	}
+
+	return battery, nil
+}
+
+// Battery is like [Device.Battery], but finds the battery of the event
+// device event, such as "event3" or "/dev/input/event3", without opening
+// it.
+func (sys *System) Battery(event string) (*Battery, error) {
+	var (
+		battery *Battery
+		err     error
+	)
+
+	battery, err = sys.battery(path.Join("sys/class/input", filepath.Base(event)))
+	if err != nil {
+		return nil, fmt.Errorf("System.Battery: %w", err)
+	}
+
+	return battery, nil
+}
+
+// battery finds the battery of the device whose sysfs node is linked
+// as link.
+func (sys *System) battery(link string) (*Battery, error) {
+	var (
+		dir     string
+		matches []string
+		supply  string
+		err     error
+	)
+
+	dir, err = sys.resolve(link)
+	if err != nil {
+		return nil, err
+	}
+
+	for ; strings.HasPrefix(dir, "sys/devices/"); dir = path.Dir(dir) {
+		matches, err = fs.Glob(sys.fsys, path.Join(dir, "power_supply", "*"))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, supply = range matches {
+			if sys.isBattery(supply) {
+				return sys.newBattery(supply)
+			}
+		}
+	}
+
+	return nil, ErrNoBattery
+}
+
+// isBattery reports whether the power supply at dir is a battery.
+func (sys *System) isBattery(dir string) bool {
+	var (
+		kind string
+		err  error
+	)
+
+	kind, err = sysfsString(sys.fsys, dir, "type")
+
+	return err == nil && kind == "Battery"
+}
+
+func (sys *System) newBattery(dir string) (*Battery, error) {
+	var (
+		battery  *Battery
+		capacity string
+		err      error
+	)
+
+	battery = &Battery{Name: path.Base(dir), Capacity: -1}
+
+	capacity, err = sysfsString(sys.fsys, dir, "capacity")
+	if err == nil {
+		battery.Capacity, err = strconv.Atoi(capacity)
+	}
+
+	if err != nil && !unreported(err) {
+		return nil, err
+	}
+
+	battery.CapacityLevel, err = sysfsString(sys.fsys, dir, "capacity_level")
+	if err != nil && !unreported(err) {
+		return nil, err
+	}
+
+	battery.Status, err = sysfsString(sys.fsys, dir, "status")
+	if err != nil && !unreported(err) {
+		return nil, err
+	}
+
+	return battery, nil
+}
+
+// unreported reports whether err means that the driver does not report
+// an attribute, either at all or, with ENODATA, not yet, as with a HID
+// battery before the device's first battery report.
+func unreported(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, unix.ENODATA)
+}
@@ -0,0 +1,120 @@
+//go:build linux
+
+package input_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+type batteryTest struct {
+	name      string
+	supply    map[string]string
+	want      input.Battery
+	noBattery bool
+	fails     bool
+}
+
+func TestSystemBattery(t *testing.T) {
+	const (
+		hid    = "sys/devices/pci0000:00/usb1/1-2/0003:046D:C52B.0001"
+		event  = hid + "/input/input5/event3"
+		supply = hid + "/power_supply/hidpp_battery_0/"
+	)
+
+	var (
+		tests   []batteryTest
+		test    batteryTest
+		fsys    fstest.MapFS
+		file    string
+		data    string
+		battery *input.Battery
+		err     error
+	)
+
+	requireSymlinks(t)
+
+	tests = []batteryTest{
+		{
+			name: "capacity",
+			supply: map[string]string{
+				"type":     "Battery\n",
+				"capacity": "85\n",
+				"status":   "Discharging\n",
+			},
+			want: input.Battery{Name: "hidpp_battery_0", Capacity: 85, Status: "Discharging"},
+		},
+		{
+			name: "level only",
+			supply: map[string]string{
+				"type":           "Battery\n",
+				"capacity_level": "Low\n",
+				"status":         "Unknown\n",
+			},
+			want: input.Battery{
+				Name:          "hidpp_battery_0",
+				Capacity:      -1,
+				CapacityLevel: "Low",
+				Status:        "Unknown",
+			},
+		},
+		{
+			name:      "not a battery",
+			supply:    map[string]string{"type": "Mains\n"},
+			noBattery: true,
+		},
+		{
+			name:      "no power supply",
+			noBattery: true,
+		},
+		{
+			name:   "malformed capacity",
+			supply: map[string]string{"type": "Battery\n", "capacity": "full\n"},
+			fails:  true,
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fsys = fstest.MapFS{
+				"sys/class/input/event3": symlink("../../devices/pci0000:00/usb1/1-2/" +
+					"0003:046D:C52B.0001/input/input5/event3"),
+				event + "/dev": {Data: []byte("13:67\n")},
+			}
+
+			for file, data = range test.supply {
+				fsys[supply+file] = &fstest.MapFile{Data: []byte(data)}
+			}
+
+			battery, err = input.NewSystem(fsys).Battery("/dev/input/event3")
+
+			switch {
+			case test.noBattery:
+				if !errors.Is(err, input.ErrNoBattery) {
+					t.Errorf("Battery = %+v, %v, want ErrNoBattery", battery, err)
+				}
+			case test.fails:
+				if err == nil {
+					t.Errorf("Battery = %+v, want an error", battery)
+				}
+			case err != nil:
+				t.Errorf("Battery: %v", err)
+			case *battery != test.want:
+				t.Errorf("Battery = %+v, want %+v", *battery, test.want)
+			}
+		})
+	}
+}
+
+func TestSystemBatteryMissingDevice(t *testing.T) {
+	var err error
+
+	_, err = input.NewSystem(fstest.MapFS{}).Battery("event3")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Battery = %v, want fs.ErrNotExist", err)
+	}
+}
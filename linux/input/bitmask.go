@@ -0,0 +1,136 @@
+//go:build linux
+
+package input
+
+import (
+	"iter"
+	"math/bits"
+	"slices"
+
+	"github.com/andrieee44/mylib"
+)
+
+// Bitmask is a set of event codes. It is stored like the kernel's
+// bitmaps, as an array of native unsigned longs, so that it can be
+// passed to ioctls such as [EVIOCGBIT] directly on every architecture,
+// including big-endian ones.
+//
+// The zero Bitmask is empty and ready to use. It grows as codes are set,
+// and testing a code beyond its size reports false.
+type Bitmask struct {
+	words []uint
+}
+
+// NewBitmask returns an empty Bitmask with room for the codes 0 through
+// maxCode, such as NewBitmask(KEY_MAX) for a key bitmap.
+func NewBitmask(maxCode uint) *Bitmask {
+	return &Bitmask{words: make([]uint, maxCode/bits.UintSize+1)}
+}
+
+// Set adds code to the set.
+func (mask *Bitmask) Set(code mylib.InputCode) {
+	var word int
+
+	word = int(code / bits.UintSize)
+	if word >= len(mask.words) {
+		mask.words = append(mask.words, make([]uint, word+1-len(mask.words))...)
+	}
+
+	mask.words[word] |= 1 << (code % bits.UintSize)
+}
+
+// Clear removes code from the set.
+func (mask *Bitmask) Clear(code mylib.InputCode) {
+	var word int
+
+	word = int(code / bits.UintSize)
+	if word < len(mask.words) {
+		mask.words[word] &^= 1 << (code % bits.UintSize)
+	}
+}
+
+// Test reports whether code is in the set.
+func (mask *Bitmask) Test(code mylib.InputCode) bool {
+	var word int
+
+	word = int(code / bits.UintSize)
+
+	return word < len(mask.words) && mask.words[word]&(1<<(code%bits.UintSize)) != 0
+}
+
+// Count returns the number of codes in the set.
+func (mask *Bitmask) Count() int {
+	var (
+		count int
+		word  uint
+	)
+
+	for _, word = range mask.words {
+		count += bits.OnesCount(word)
+	}
+
+	return count
+}
+
+// Iterate returns an iterator over the codes in the set, in ascending
+// order.
+func (mask *Bitmask) Iterate() iter.Seq[mylib.InputCode] {
+	return func(yield func(mylib.InputCode) bool) {
+		var (
+			index int
+			word  uint
+			bit   int
+		)
+
+		for index, word = range mask.words {
+			for word != 0 {
+				bit = bits.TrailingZeros(word)
+				word &^= 1 << bit
+
+				if !yield(mylib.InputCode(index*bits.UintSize + bit)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Codes returns the codes in the set, in ascending order.
+func (mask *Bitmask) Codes() []mylib.InputCode {
+	return slices.Collect(mask.Iterate())
+}
+
+// size returns the size of the bitmap in bytes, for ioctl request codes.
+func (mask *Bitmask) size() uint {
+	return uint(len(mask.words)) * bits.UintSize / 8
+}
+
+// truncate drops the bits beyond the first n bytes of the bitmap, such
+// as when the kernel reports that it filled only n bytes of it. The
+// kernel copies whole longs, so n is rounded down to a word boundary.
+func (mask *Bitmask) truncate(n int) {
+	mask.words = mask.words[:min(len(mask.words), max(n, 0)*8/bits.UintSize)]
+}
+
+// bitmaskFromBytes returns the Bitmask whose bit n is bit n%8 of
+// buf[n/8], the layout of bitmaps dumped byte by byte, such as by evemu.
+func bitmaskFromBytes(buf []byte) *Bitmask {
+	var (
+		mask  *Bitmask
+		index int
+		b     byte
+		bit   int
+	)
+
+	mask = &Bitmask{}
+
+	for index, b = range buf {
+		for b != 0 {
+			bit = bits.TrailingZeros8(b)
+			b &^= 1 << bit
+			mask.Set(mylib.InputCode(index*8 + bit))
+		}
+	}
+
+	return mask
+}
@@ -0,0 +1,154 @@
+//go:build linux
+
+package input
+
+import (
+	"fmt"
+	"sync"
+)
+
+// BufferStats counts the events lost while reading a device through a
+// [BufferedReader].
+type BufferStats struct {
+	// Overruns is the number of times the reader's own buffer filled up
+	// and was emptied.
+	Overruns uint64
+
+	// Dropped is the number of events discarded by those overruns.
+	Dropped uint64
+
+	// KernelDropped is the number of [SYN_DROPPED] events reported by the
+	// kernel, each marking an overflow of its own event queue.
+	KernelDropped uint64
+}
+
+// BufferedReader drains a device from a goroutine of its own into a ring
+// buffer, so that a slow consumer does not let the kernel's much smaller
+// queue overflow.
+//
+// If the ring buffer fills up regardless, it is emptied and a synthetic
+// [SYN_DROPPED] is queued, just as the kernel does with its queue, so a
+// consumer handles both overflows the same way: by discarding events up
+// to the next [SYN_REPORT] and resynchronizing, for example with
+// [State.Resync].
+type BufferedReader struct {
+	dev   *Device
+	mutex sync.Mutex
+	cond  *sync.Cond
+	ring  []Event
+	head  int
+	count int
+	stats BufferStats
+	err   error
+}
+
+// NewBufferedReader starts draining dev into a buffer holding up to
+// capacity events. The goroutine stops once reading dev fails, such as
+// when dev is closed.
+func NewBufferedReader(dev *Device, capacity int) *BufferedReader {
+	var reader *BufferedReader
+
+	reader = &BufferedReader{
+		dev:  dev,
+		ring: make([]Event, max(capacity, 2)),
+	}
+	reader.cond = sync.NewCond(&reader.mutex)
+
+	go reader.drain()
+
+	return reader
+}
+
+// ReadEvent returns the next buffered event, blocking until one arrives.
+// Once the device fails, the remaining events are returned before the
+// error.
+func (reader *BufferedReader) ReadEvent() (Event, error) {
+	var ev Event
+
+	reader.mutex.Lock()
+	defer reader.mutex.Unlock()
+
+	for reader.count == 0 && reader.err == nil {
+		reader.cond.Wait()
+	}
+
+	if reader.count == 0 {
+		return Event{}, fmt.Errorf("BufferedReader.ReadEvent: %w", reader.err)
+	}
+
+	ev = reader.ring[reader.head]
+	reader.head = (reader.head + 1) % len(reader.ring)
+	reader.count--
+
+	return ev, nil
+}
+
+// Buffered returns the number of events waiting to be read.
+func (reader *BufferedReader) Buffered() int {
+	reader.mutex.Lock()
+	defer reader.mutex.Unlock()
+
+	return reader.count
+}
+
+// Stats returns the events lost so far.
+func (reader *BufferedReader) Stats() BufferStats {
+	reader.mutex.Lock()
+	defer reader.mutex.Unlock()
+
+	return reader.stats
+}
+
+func (reader *BufferedReader) drain() {
+	var (
+		ev  Event
+		err error
+	)
+
+	for err == nil {
+		if reader.dev.nonblock {
+			ev, err = waitEvent(reader.dev, reader.dev.ReadEvent)
+		} else {
+			ev, err = reader.dev.ReadEvent()
+		}
+
+		reader.push(ev, err)
+	}
+}
+
+func (reader *BufferedReader) push(ev Event, err error) {
+	reader.mutex.Lock()
+	defer reader.mutex.Unlock()
+	defer reader.cond.Broadcast()
+
+	if err != nil {
+		reader.err = err
+
+		return
+	}
+
+	if ev.Type == EV_SYN && ev.Code == SYN_DROPPED {
+		reader.stats.KernelDropped++
+	}
+
+	if reader.count == len(reader.ring) {
+		reader.stats.Overruns++
+		reader.stats.Dropped += uint64(reader.count)
+		reader.head = 0
+		reader.count = 0
+
+		reader.append(Event{
+			Sec:  ev.Sec,
+			Usec: ev.Usec,
+			Type: EV_SYN,
+			Code: SYN_DROPPED,
+		})
+	}
+
+	reader.append(ev)
+}
+
+func (reader *BufferedReader) append(ev Event) {
+	reader.ring[(reader.head+reader.count)%len(reader.ring)] = ev
+	reader.count++
+}
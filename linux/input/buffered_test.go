@@ -0,0 +1,169 @@
+//go:build linux
+
+package input
+
+import (
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type bufferedTest struct {
+	name     string
+	capacity int
+	steps    []bufferedStep
+	stats    BufferStats
+}
+
+// bufferedStep pushes events into a [BufferedReader], as its draining
+// goroutine would, then reads read events from it.
+type bufferedStep struct {
+	push []Event
+	read []Event
+}
+
+func TestBufferedReaderRing(t *testing.T) {
+	var (
+		a, b, c, d Event
+		dropped    Event
+		tests      []bufferedTest
+		test       bufferedTest
+		reader     *BufferedReader
+		step       bufferedStep
+		ev         Event
+		got        []Event
+		err        error
+	)
+
+	a = Event{Sec: 1, Type: EV_KEY, Code: KEY_A, Value: 1}
+	b = Event{Sec: 2, Type: EV_KEY, Code: KEY_B, Value: 1}
+	c = Event{Sec: 3, Type: EV_KEY, Code: KEY_C, Value: 1}
+	d = Event{Sec: 4, Type: EV_KEY, Code: KEY_D, Value: 1}
+	dropped = Event{Sec: 3, Type: EV_SYN, Code: SYN_DROPPED}
+
+	tests = []bufferedTest{
+		{
+			name:     "in order",
+			capacity: 3,
+			steps:    []bufferedStep{{push: []Event{a, b, c}, read: []Event{a, b, c}}},
+		},
+		{
+			name:     "overrun",
+			capacity: 2,
+			steps:    []bufferedStep{{push: []Event{a, b, c}, read: []Event{dropped, c}}},
+			stats:    BufferStats{Overruns: 1, Dropped: 2},
+		},
+		{
+			name:     "wrapped around",
+			capacity: 3,
+			steps: []bufferedStep{
+				{push: []Event{a, b}, read: []Event{a}},
+				{push: []Event{c, d}, read: []Event{b, c, d}},
+			},
+		},
+		{
+			name:     "kernel overflow",
+			capacity: 3,
+			steps: []bufferedStep{{
+				push: []Event{a, {Sec: 2, Type: EV_SYN, Code: SYN_DROPPED}},
+				read: []Event{a, {Sec: 2, Type: EV_SYN, Code: SYN_DROPPED}},
+			}},
+			stats: BufferStats{KernelDropped: 1},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			reader = &BufferedReader{ring: make([]Event, test.capacity)}
+			reader.cond = sync.NewCond(&reader.mutex)
+
+			for _, step = range test.steps {
+				for _, ev = range step.push {
+					reader.push(ev, nil)
+				}
+
+				got = nil
+				for range step.read {
+					ev, err = reader.ReadEvent()
+					if err != nil {
+						t.Fatalf("ReadEvent: %v", err)
+					}
+
+					got = append(got, ev)
+				}
+
+				if !slices.Equal(got, step.read) {
+					t.Errorf("read %v, want %v", got, step.read)
+				}
+			}
+
+			if reader.Buffered() != 0 {
+				t.Errorf("Buffered = %d, want 0", reader.Buffered())
+			}
+
+			if reader.Stats() != test.stats {
+				t.Errorf("Stats = %+v, want %+v", reader.Stats(), test.stats)
+			}
+		})
+	}
+}
+
+func TestBufferedReaderError(t *testing.T) {
+	var (
+		pipeReader, pipeWriter *os.File
+		feed, dev              *Device
+		reader                 *BufferedReader
+		events                 []Event
+		ev, want               Event
+		err                    error
+	)
+
+	pipeReader, pipeWriter, err = os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed = NewDeviceFromFile(pipeWriter)
+	events = []Event{
+		{Sec: 1, Type: EV_KEY, Code: KEY_A, Value: 1},
+		{Sec: 1, Type: EV_SYN, Code: SYN_REPORT},
+	}
+
+	for _, ev = range events {
+		err = feed.WriteEvent(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = feed.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev = NewDeviceFromFile(pipeReader)
+	defer dev.Close()
+
+	reader = NewBufferedReader(dev, 16)
+
+	// The buffered events come before the error that stopped draining.
+	for _, want = range events {
+		ev, err = reader.ReadEvent()
+		if err != nil || ev != want {
+			t.Fatalf("ReadEvent = %v, %v, want %v", ev, err, want)
+		}
+	}
+
+	_, err = reader.ReadEvent()
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("ReadEvent = %v, want io.EOF", err)
+	}
+
+	_, err = reader.ReadEvent()
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("ReadEvent after the error = %v, want io.EOF", err)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build linux
+
+// Package calibration forwards to
+// [github.com/andrieee44/mylib/x/linux/input/calibration], where the
+// experimental calibration profiles moved, so that existing imports keep
+// building.
+//
+// Deprecated: Import github.com/andrieee44/mylib/x/linux/input/calibration
+// instead. This package will be removed in a future release.
+package calibration
+
+import "github.com/andrieee44/mylib/x/linux/input/calibration"
+
+// DefaultFile is [calibration.DefaultFile].
+const DefaultFile = calibration.DefaultFile
+
+type (
+	// Axis is [calibration.Axis].
+	Axis = calibration.Axis
+
+	// Profile is [calibration.Profile].
+	Profile = calibration.Profile
+
+	// Recorder is [calibration.Recorder].
+	Recorder = calibration.Recorder
+
+	// Store is [calibration.Store].
+	Store = calibration.Store
+)
+
+// Load calls [calibration.Load].
+func Load(relPath string) (*Store, error) {
+	return calibration.Load(relPath)
+}
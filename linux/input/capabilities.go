@@ -0,0 +1,394 @@
+//go:build linux
+
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/andrieee44/mylib"
+)
+
+// Capabilities describes everything an event device reports about
+// itself, as returned by [Device.Capabilities]. It is encoded to JSON
+// and YAML with symbolic names; see [Capabilities.MarshalJSON].
+type Capabilities struct {
+	// Name is the device name, as returned by [Device.Name].
+	Name string
+
+	// Phys is the physical topology path, or empty if the driver sets
+	// none.
+	Phys string
+
+	// Uniq is the unique identifier, or empty if the driver sets none.
+	Uniq string
+
+	// ID holds the bus type, vendor, product, and version.
+	ID ID
+
+	// Properties lists the device's input properties.
+	Properties []Property
+
+	// Codes maps each supported event type, as reported by
+	// [Device.Events], to its supported codes.
+	Codes map[mylib.InputEvent][]mylib.InputCode
+
+	// Abs holds the parameters of each axis in Codes[EV_ABS].
+	Abs map[mylib.InputCode]AbsInfo
+
+	// Repeat reports whether the device supports EV_REP, the kernel's
+	// software auto-repeat.
+	Repeat bool
+
+	// RepeatDelay and RepeatPeriod are the auto-repeat settings, as
+	// returned by [Device.Repeat], if Repeat is set.
+	RepeatDelay, RepeatPeriod time.Duration
+
+	// FFEffects is how many force-feedback effects the device can hold,
+	// or zero if it does not support EV_FF.
+	FFEffects int
+}
+
+// Capabilities queries everything the device reports about itself in
+// one call, issuing each of the ioctls it needs in turn.
+func (dev *Device) Capabilities() (*Capabilities, error) {
+	var (
+		caps   *Capabilities
+		mask   *Bitmask
+		events []mylib.InputEvent
+		ev     mylib.InputEvent
+		axis   mylib.InputCode
+		info   AbsInfo
+		err    error
+	)
+
+	caps = &Capabilities{
+		Codes: make(map[mylib.InputEvent][]mylib.InputCode),
+		Abs:   make(map[mylib.InputCode]AbsInfo),
+	}
+
+	caps.Name, err = dev.Name()
+	if err == nil {
+		caps.Phys, err = dev.Phys()
+	}
+
+	if err == nil {
+		caps.Uniq, err = dev.Uniq()
+	}
+
+	if err == nil {
+		err = deviceIoctl(dev, EVIOCGID, &caps.ID)
+	}
+
+	if err == nil {
+		caps.Properties, err = dev.Properties()
+	}
+
+	if err == nil {
+		events, err = dev.Events()
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Device.Capabilities: %w", err)
+	}
+
+	for _, ev = range events {
+		caps.Codes[ev], err = dev.Codes(ev)
+		if err != nil {
+			return nil, fmt.Errorf("Device.Capabilities: %w", err)
+		}
+	}
+
+	for _, axis = range caps.Codes[EV_ABS] {
+		info, err = dev.AbsInfo(axis)
+		if err != nil {
+			return nil, fmt.Errorf("Device.Capabilities: %w", err)
+		}
+
+		caps.Abs[axis] = info
+	}
+
+	// Events leaves EV_REP out, since it has no codes of its own.
+	mask = NewBitmask(EV_MAX)
+
+	err = dev.readBitmask(EVIOCGBIT(0, mask.size()), mask)
+	if err != nil {
+		return nil, fmt.Errorf("Device.Capabilities: %w", err)
+	}
+
+	if mask.Test(EV_REP) {
+		caps.Repeat = true
+
+		caps.RepeatDelay, caps.RepeatPeriod, err = dev.Repeat()
+		if err != nil {
+			return nil, fmt.Errorf("Device.Capabilities: %w", err)
+		}
+	}
+
+	if slices.Contains(events, EV_FF) {
+		caps.FFEffects, err = dev.EffectCapacity()
+		if err != nil {
+			return nil, fmt.Errorf("Device.Capabilities: %w", err)
+		}
+	}
+
+	return caps, nil
+}
+
+// capabilitiesDoc is the serialized form of [Capabilities], with event
+// types, codes, and properties written as their names.
+type capabilitiesDoc struct {
+	Name       string                `json:"name" yaml:"name"`
+	Phys       string                `json:"phys,omitempty" yaml:"phys,omitempty"`
+	Uniq       string                `json:"uniq,omitempty" yaml:"uniq,omitempty"`
+	ID         idDoc                 `json:"id" yaml:"id"`
+	Properties []string              `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Codes      map[string][]string   `json:"codes" yaml:"codes"`
+	Abs        map[string]absInfoDoc `json:"abs,omitempty" yaml:"abs,omitempty"`
+	Repeat     *repeatDoc            `json:"repeat,omitempty" yaml:"repeat,omitempty"`
+	FFEffects  int                   `json:"ff_effects,omitempty" yaml:"ff_effects,omitempty"`
+}
+
+type idDoc struct {
+	Bustype uint16 `json:"bustype" yaml:"bustype"`
+	Vendor  uint16 `json:"vendor" yaml:"vendor"`
+	Product uint16 `json:"product" yaml:"product"`
+	Version uint16 `json:"version" yaml:"version"`
+}
+
+type absInfoDoc struct {
+	Value      int32 `json:"value" yaml:"value"`
+	Minimum    int32 `json:"minimum" yaml:"minimum"`
+	Maximum    int32 `json:"maximum" yaml:"maximum"`
+	Fuzz       int32 `json:"fuzz,omitempty" yaml:"fuzz,omitempty"`
+	Flat       int32 `json:"flat,omitempty" yaml:"flat,omitempty"`
+	Resolution int32 `json:"resolution,omitempty" yaml:"resolution,omitempty"`
+}
+
+type repeatDoc struct {
+	Delay  string `json:"delay" yaml:"delay"`
+	Period string `json:"period" yaml:"period"`
+}
+
+// MarshalJSON encodes caps as a JSON object that names event types,
+// codes, and properties, such as {"codes":{"EV_KEY":["KEY_A"]}}, so that
+// snapshots of different devices or kernels can be compared. Unknown
+// types and codes are encoded as decimal strings, and the auto-repeat
+// settings as durations such as "250ms".
+func (caps *Capabilities) MarshalJSON() ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	data, err = json.Marshal(caps.doc())
+	if err != nil {
+		return nil, fmt.Errorf("Capabilities.MarshalJSON: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalJSON decodes capabilities encoded by
+// [Capabilities.MarshalJSON].
+func (caps *Capabilities) UnmarshalJSON(data []byte) error {
+	var (
+		doc capabilitiesDoc
+		err error
+	)
+
+	err = json.Unmarshal(data, &doc)
+	if err == nil {
+		err = caps.fromDoc(doc)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Capabilities.UnmarshalJSON: %w", err)
+	}
+
+	return nil
+}
+
+// MarshalYAML returns the value to encode in place of caps, laid out
+// like [Capabilities.MarshalJSON]. It implements the Marshaler interface
+// of gopkg.in/yaml.v3 and compatible packages.
+func (caps *Capabilities) MarshalYAML() (any, error) {
+	return caps.doc(), nil
+}
+
+// UnmarshalYAML decodes capabilities encoded by
+// [Capabilities.MarshalYAML]. It implements the unmarshaler interface
+// taking a decoding function, which gopkg.in/yaml.v2 and
+// gopkg.in/yaml.v3 both accept.
+func (caps *Capabilities) UnmarshalYAML(unmarshal func(any) error) error {
+	var (
+		doc capabilitiesDoc
+		err error
+	)
+
+	err = unmarshal(&doc)
+	if err == nil {
+		err = caps.fromDoc(doc)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Capabilities.UnmarshalYAML: %w", err)
+	}
+
+	return nil
+}
+
+func (caps *Capabilities) doc() capabilitiesDoc {
+	var (
+		doc   capabilitiesDoc
+		prop  Property
+		ev    mylib.InputEvent
+		codes []mylib.InputCode
+		code  mylib.InputCode
+		names []string
+		axis  mylib.InputCode
+		info  AbsInfo
+	)
+
+	doc = capabilitiesDoc{
+		Name:      caps.Name,
+		Phys:      caps.Phys,
+		Uniq:      caps.Uniq,
+		ID:        idDoc(caps.ID),
+		Codes:     make(map[string][]string, len(caps.Codes)),
+		FFEffects: caps.FFEffects,
+	}
+
+	for _, prop = range caps.Properties {
+		doc.Properties = append(doc.Properties, prop.String())
+	}
+
+	for ev, codes = range caps.Codes {
+		names = make([]string, 0, len(codes))
+		for _, code = range codes {
+			names = append(names, formatCode(ev, code))
+		}
+
+		doc.Codes[formatType(ev)] = names
+	}
+
+	if len(caps.Abs) != 0 {
+		doc.Abs = make(map[string]absInfoDoc, len(caps.Abs))
+	}
+
+	for axis, info = range caps.Abs {
+		doc.Abs[formatCode(EV_ABS, axis)] = absInfoDoc(info)
+	}
+
+	if caps.Repeat {
+		doc.Repeat = &repeatDoc{
+			Delay:  caps.RepeatDelay.String(),
+			Period: caps.RepeatPeriod.String(),
+		}
+	}
+
+	return doc
+}
+
+func (caps *Capabilities) fromDoc(doc capabilitiesDoc) error {
+	var (
+		result Capabilities
+		name   string
+		prop   Property
+		names  []string
+		ev     mylib.InputEvent
+		code   mylib.InputCode
+		info   absInfoDoc
+		err    error
+	)
+
+	result = Capabilities{
+		Name:      doc.Name,
+		Phys:      doc.Phys,
+		Uniq:      doc.Uniq,
+		ID:        ID(doc.ID),
+		Codes:     make(map[mylib.InputEvent][]mylib.InputCode, len(doc.Codes)),
+		Abs:       make(map[mylib.InputCode]AbsInfo, len(doc.Abs)),
+		FFEffects: doc.FFEffects,
+	}
+
+	for _, name = range doc.Properties {
+		prop, err = parseProperty(name)
+		if err != nil {
+			return err
+		}
+
+		result.Properties = append(result.Properties, prop)
+	}
+
+	for name, names = range doc.Codes {
+		ev, err = parseType(name)
+		if err != nil {
+			return err
+		}
+
+		result.Codes[ev] = make([]mylib.InputCode, 0, len(names))
+
+		for _, name = range names {
+			code, err = parseCode(name)
+			if err != nil {
+				return err
+			}
+
+			result.Codes[ev] = append(result.Codes[ev], code)
+		}
+	}
+
+	for name, info = range doc.Abs {
+		code, err = parseCode(name)
+		if err != nil {
+			return err
+		}
+
+		result.Abs[code] = AbsInfo(info)
+	}
+
+	if doc.Repeat != nil {
+		result.Repeat = true
+
+		result.RepeatDelay, err = time.ParseDuration(doc.Repeat.Delay)
+		if err == nil {
+			result.RepeatPeriod, err = time.ParseDuration(doc.Repeat.Period)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	*caps = result
+
+	return nil
+}
+
+// parseProperty returns the property named name, such as
+// "INPUT_PROP_POINTER", or given as a decimal string.
+func parseProperty(name string) (Property, error) {
+	var (
+		value uint64
+		prop  Property
+		known string
+		err   error
+	)
+
+	value, err = strconv.ParseUint(name, 10, 16)
+	if err == nil {
+		return Property(value), nil
+	}
+
+	for prop, known = range propertyNames {
+		if known == name {
+			return prop, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w %q", ErrInvalidProperty, name)
+}
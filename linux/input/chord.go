@@ -0,0 +1,213 @@
+//go:build linux
+
+package input
+
+import (
+	"slices"
+	"time"
+
+	"github.com/andrieee44/mylib"
+)
+
+// Chord is a set of keys that act as other keys when pressed together,
+// such as J+K producing Escape.
+type Chord struct {
+	// Keys are the physical keys of the chord.
+	Keys []mylib.InputCode
+
+	// Emit are the keys emitted for the chord, pressed in order and
+	// released in reverse order, so that a modifier listed first wraps
+	// the keys after it.
+	Emit []mylib.InputCode
+}
+
+// ChordFilter is a [Filter] that turns chords into the keys they emit.
+//
+// A press of a key that belongs to a chord is held back. Once every key
+// of a chord is down, the held presses are swallowed and the chord's
+// keys are pressed; they are released when any key of the chord is
+// released, and the remaining keys of the chord are swallowed until
+// they are released too. The held presses are instead emitted
+// unchanged, in order, when the first of them is older than the
+// timeout, judged by the timestamps of later events or by
+// [ChordFilter.Flush] if the device reports nothing else by then, or
+// when any other key event arrives first. [Remapper] calls Flush on its
+// own; other readers should wait with a read deadline from
+// [ChordFilter.FlushAfter].
+type ChordFilter struct {
+	chords    []Chord
+	timeout   time.Duration
+	pending   []Event
+	swallowed map[mylib.InputCode]int
+	down      map[int]bool
+	last      time.Duration
+	out       []Event
+}
+
+var (
+	_ Filter  = (*ChordFilter)(nil)
+	_ Flusher = (*ChordFilter)(nil)
+)
+
+// NewChordFilter returns a ChordFilter recognizing chords whose keys are
+// all pressed within timeout of the first one.
+func NewChordFilter(timeout time.Duration, chords ...Chord) *ChordFilter {
+	return &ChordFilter{
+		chords:    chords,
+		timeout:   timeout,
+		swallowed: make(map[mylib.InputCode]int),
+		down:      make(map[int]bool),
+	}
+}
+
+// Filter applies chord recognition to frame.
+func (filter *ChordFilter) Filter(frame []Event) []Event {
+	var (
+		ev    Event
+		code  mylib.InputCode
+		index int
+		ok    bool
+	)
+
+	filter.out = filter.out[:0]
+
+	for _, ev = range frame {
+		filter.last = eventTime(ev)
+
+		if len(filter.pending) != 0 &&
+			filter.last-eventTime(filter.pending[0]) >= filter.timeout {
+			filter.flush()
+		}
+
+		if ev.Type != EV_KEY {
+			filter.out = append(filter.out, ev)
+
+			continue
+		}
+
+		code = mylib.InputCode(ev.Code)
+
+		index, ok = filter.swallowed[code]
+		if ok {
+			if ev.Value == 0 {
+				delete(filter.swallowed, code)
+				filter.release(ev, index)
+			}
+
+			continue
+		}
+
+		if ev.Value == 1 && filter.member(code) {
+			filter.pending = append(filter.pending, ev)
+			filter.match(ev)
+
+			continue
+		}
+
+		filter.flush()
+		filter.out = append(filter.out, ev)
+	}
+
+	return filter.out
+}
+
+// FlushAfter implements [Flusher], returning the time left until the
+// first held-back press times out.
+func (filter *ChordFilter) FlushAfter() (time.Duration, bool) {
+	if len(filter.pending) == 0 {
+		return 0, false
+	}
+
+	return max(eventTime(filter.pending[0])+filter.timeout-filter.last, 0), true
+}
+
+// Flush implements [Flusher], emitting the held-back presses unchanged,
+// each in a frame of its own.
+func (filter *ChordFilter) Flush() []Event {
+	filter.out = nil
+	filter.flush()
+
+	return filter.out
+}
+
+// member reports whether code belongs to any chord.
+func (filter *ChordFilter) member(code mylib.InputCode) bool {
+	var chord Chord
+
+	for _, chord = range filter.chords {
+		if slices.Contains(chord.Keys, code) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match presses the first chord whose keys are all held back, if any.
+func (filter *ChordFilter) match(at Event) {
+	var (
+		index int
+		chord Chord
+		key   mylib.InputCode
+		code  mylib.InputCode
+		all   bool
+	)
+
+	for index, chord = range filter.chords {
+		all = len(chord.Keys) != 0
+		for _, key = range chord.Keys {
+			all = all && slices.ContainsFunc(filter.pending, func(ev Event) bool {
+				return mylib.InputCode(ev.Code) == key
+			})
+		}
+
+		if !all {
+			continue
+		}
+
+		filter.pending = slices.DeleteFunc(filter.pending, func(ev Event) bool {
+			return slices.Contains(chord.Keys, mylib.InputCode(ev.Code))
+		})
+		filter.flush()
+
+		for _, key = range chord.Keys {
+			filter.swallowed[key] = index
+		}
+
+		for _, code = range chord.Emit {
+			filter.out = append(filter.out, synthKey(at, code, 1))
+		}
+
+		filter.out = append(filter.out, synthSyn(at))
+		filter.down[index] = true
+
+		return
+	}
+}
+
+// release releases the keys of the chord at index if it is still down.
+func (filter *ChordFilter) release(at Event, index int) {
+	var i int
+
+	if !filter.down[index] {
+		return
+	}
+
+	for i = len(filter.chords[index].Emit) - 1; i >= 0; i-- {
+		filter.out = append(filter.out, synthKey(at, filter.chords[index].Emit[i], 0))
+	}
+
+	filter.out = append(filter.out, synthSyn(at))
+	delete(filter.down, index)
+}
+
+// flush emits the held-back presses unchanged.
+func (filter *ChordFilter) flush() {
+	var ev Event
+
+	for _, ev = range filter.pending {
+		filter.out = append(filter.out, ev, synthSyn(ev))
+	}
+
+	filter.pending = filter.pending[:0]
+}
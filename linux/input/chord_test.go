@@ -0,0 +1,196 @@
+//go:build linux
+
+package input_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+// testChords returns J+K producing Escape and D+F producing Ctrl+C.
+func testChords() []input.Chord {
+	return []input.Chord{
+		{Keys: []mylib.InputCode{input.KEY_J, input.KEY_K}, Emit: []mylib.InputCode{input.KEY_ESC}},
+		{
+			Keys: []mylib.InputCode{input.KEY_D, input.KEY_F},
+			Emit: []mylib.InputCode{input.KEY_LEFTCTRL, input.KEY_C},
+		},
+	}
+}
+
+func TestChordFilter(t *testing.T) {
+	var (
+		tests []filterTest
+		test  filterTest
+	)
+
+	tests = []filterTest{
+		{
+			name: "chord",
+			frames: [][]input.Event{
+				frame(at(0, input.EV_MSC, input.MSC_SCAN, 0x7000d), key(0, input.KEY_J, 1)),
+				frame(key(10, input.KEY_K, 1)),
+				frame(key(50, input.KEY_J, 0)),
+				frame(key(60, input.KEY_K, 0)),
+				frame(key(70, input.KEY_J, 1)),
+			},
+			flush: true,
+			want: []input.Event{
+				at(0, input.EV_MSC, input.MSC_SCAN, 0x7000d), syn(0),
+				key(10, input.KEY_ESC, 1), syn(10),
+				syn(10),
+				key(50, input.KEY_ESC, 0), syn(50),
+				syn(50),
+				syn(60),
+				syn(70),
+				key(70, input.KEY_J, 1), syn(70),
+			},
+		},
+		{
+			name: "emitted in order, released in reverse",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_F, 1), key(0, input.KEY_D, 1)),
+				frame(key(30, input.KEY_F, 0)),
+			},
+			want: []input.Event{
+				key(0, input.KEY_LEFTCTRL, 1), key(0, input.KEY_C, 1), syn(0),
+				syn(0),
+				key(30, input.KEY_C, 0), key(30, input.KEY_LEFTCTRL, 0), syn(30),
+				syn(30),
+			},
+		},
+		{
+			name: "timed out",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_J, 1)),
+				frame(key(60, input.KEY_K, 1)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(0),
+				key(0, input.KEY_J, 1), syn(0),
+				syn(60),
+				key(60, input.KEY_K, 1), syn(60),
+			},
+		},
+		{
+			name: "timed out on deadline",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_J, 1)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(0),
+				key(0, input.KEY_J, 1), syn(0),
+			},
+		},
+		{
+			name: "interrupted",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_J, 1)),
+				frame(key(10, input.KEY_A, 1)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(0, input.KEY_J, 1), syn(0),
+				key(10, input.KEY_A, 1), syn(10),
+			},
+		},
+		{
+			name: "released before the chord",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_J, 1)),
+				frame(key(20, input.KEY_J, 0)),
+				frame(key(30, input.KEY_K, 1)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(0),
+				key(0, input.KEY_J, 1), syn(0),
+				key(20, input.KEY_J, 0), syn(20),
+				syn(30),
+				key(30, input.KEY_K, 1), syn(30),
+			},
+		},
+		{
+			name: "keys of different chords",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_J, 1)),
+				frame(key(10, input.KEY_D, 1)),
+				frame(key(20, input.KEY_K, 1)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(0),
+				syn(10),
+				key(10, input.KEY_D, 1), syn(10),
+				key(20, input.KEY_ESC, 1), syn(20),
+				syn(20),
+			},
+		},
+		{
+			name: "SYN_DROPPED passes",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_J, 1)),
+				{at(5, input.EV_SYN, input.SYN_DROPPED, 0)},
+				frame(key(10, input.KEY_K, 1)),
+			},
+			want: []input.Event{
+				syn(0),
+				at(5, input.EV_SYN, input.SYN_DROPPED, 0),
+				key(10, input.KEY_ESC, 1), syn(10),
+				syn(10),
+			},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkEvents(
+				t,
+				filterFrames(input.NewChordFilter(50*time.Millisecond, testChords()...), test.frames, test.flush),
+				test.want,
+			)
+		})
+	}
+}
+
+func TestChordFilterFlushAfter(t *testing.T) {
+	var (
+		filter *input.ChordFilter
+		after  time.Duration
+		ok     bool
+	)
+
+	filter = input.NewChordFilter(50*time.Millisecond, testChords()...)
+
+	_, ok = filter.FlushAfter()
+	if ok {
+		t.Error("FlushAfter reported held-back presses before any")
+	}
+
+	filter.Filter(frame(key(0, input.KEY_J, 1)))
+
+	after, ok = filter.FlushAfter()
+	if !ok || after != 50*time.Millisecond {
+		t.Errorf("FlushAfter = %v, %t, want 50ms, true", after, ok)
+	}
+
+	// The deadline counts from the first held-back press.
+	filter.Filter(frame(key(30, input.KEY_D, 1)))
+
+	after, ok = filter.FlushAfter()
+	if !ok || after != 20*time.Millisecond {
+		t.Errorf("FlushAfter = %v, %t, want 20ms, true", after, ok)
+	}
+
+	filter.Flush()
+
+	_, ok = filter.FlushAfter()
+	if ok {
+		t.Error("FlushAfter reported held-back presses after Flush")
+	}
+}
@@ -0,0 +1,463 @@
+//go:build linux
+
+package input
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/andrieee44/mylib"
+)
+
+// The methods of the class interfaces are implemented by small types
+// embedded in the classified devices, so that a device of several
+// classes implements each of their interfaces. [mylib.Pointer] and
+// [mylib.Gamepad] share buttonMethods, whose Buttons returns the buttons
+// of every class the device has.
+type (
+	keyMethods struct {
+		dev *Device
+	}
+
+	buttonMethods struct {
+		dev      *Device
+		isButton func(mylib.InputCode) bool
+	}
+
+	relativeMethods struct {
+		dev *Device
+	}
+
+	axisMethods struct {
+		dev *Device
+	}
+
+	contactMethods struct {
+		dev *Device
+	}
+)
+
+// class is a set of the device classes of [Classify].
+type class uint8
+
+const (
+	classKeyboard class = 1 << iota
+	classPointer
+	classGamepad
+	classTouchscreen
+)
+
+// The classified devices, one for each combination of classes.
+type (
+	keyboardDevice struct {
+		*Device
+		keyMethods
+	}
+
+	pointerDevice struct {
+		*Device
+		buttonMethods
+		relativeMethods
+	}
+
+	gamepadDevice struct {
+		*Device
+		buttonMethods
+		axisMethods
+	}
+
+	touchscreenDevice struct {
+		*Device
+		contactMethods
+	}
+
+	keyboardPointerDevice struct {
+		*Device
+		keyMethods
+		buttonMethods
+		relativeMethods
+	}
+
+	keyboardGamepadDevice struct {
+		*Device
+		keyMethods
+		buttonMethods
+		axisMethods
+	}
+
+	keyboardTouchscreenDevice struct {
+		*Device
+		keyMethods
+		contactMethods
+	}
+
+	pointerGamepadDevice struct {
+		*Device
+		buttonMethods
+		relativeMethods
+		axisMethods
+	}
+
+	pointerTouchscreenDevice struct {
+		*Device
+		buttonMethods
+		relativeMethods
+		contactMethods
+	}
+
+	gamepadTouchscreenDevice struct {
+		*Device
+		buttonMethods
+		axisMethods
+		contactMethods
+	}
+
+	keyboardPointerGamepadDevice struct {
+		*Device
+		keyMethods
+		buttonMethods
+		relativeMethods
+		axisMethods
+	}
+
+	keyboardPointerTouchscreenDevice struct {
+		*Device
+		keyMethods
+		buttonMethods
+		relativeMethods
+		contactMethods
+	}
+
+	keyboardGamepadTouchscreenDevice struct {
+		*Device
+		keyMethods
+		buttonMethods
+		axisMethods
+		contactMethods
+	}
+
+	pointerGamepadTouchscreenDevice struct {
+		*Device
+		buttonMethods
+		relativeMethods
+		axisMethods
+		contactMethods
+	}
+
+	keyboardPointerGamepadTouchscreenDevice struct {
+		*Device
+		keyMethods
+		buttonMethods
+		relativeMethods
+		axisMethods
+		contactMethods
+	}
+)
+
+var (
+	_ mylib.Keyboard    = keyboardDevice{}
+	_ mylib.Pointer     = pointerDevice{}
+	_ mylib.Gamepad     = gamepadDevice{}
+	_ mylib.Touchscreen = touchscreenDevice{}
+	_ mylib.Keyboard    = keyboardPointerDevice{}
+	_ mylib.Pointer     = keyboardPointerDevice{}
+	_ mylib.Keyboard    = keyboardGamepadDevice{}
+	_ mylib.Gamepad     = keyboardGamepadDevice{}
+	_ mylib.Keyboard    = keyboardTouchscreenDevice{}
+	_ mylib.Touchscreen = keyboardTouchscreenDevice{}
+	_ mylib.Pointer     = pointerGamepadDevice{}
+	_ mylib.Gamepad     = pointerGamepadDevice{}
+	_ mylib.Pointer     = pointerTouchscreenDevice{}
+	_ mylib.Touchscreen = pointerTouchscreenDevice{}
+	_ mylib.Gamepad     = gamepadTouchscreenDevice{}
+	_ mylib.Touchscreen = gamepadTouchscreenDevice{}
+	_ mylib.Keyboard    = keyboardPointerGamepadDevice{}
+	_ mylib.Pointer     = keyboardPointerGamepadDevice{}
+	_ mylib.Gamepad     = keyboardPointerGamepadDevice{}
+	_ mylib.Keyboard    = keyboardPointerTouchscreenDevice{}
+	_ mylib.Pointer     = keyboardPointerTouchscreenDevice{}
+	_ mylib.Touchscreen = keyboardPointerTouchscreenDevice{}
+	_ mylib.Keyboard    = keyboardGamepadTouchscreenDevice{}
+	_ mylib.Gamepad     = keyboardGamepadTouchscreenDevice{}
+	_ mylib.Touchscreen = keyboardGamepadTouchscreenDevice{}
+	_ mylib.Pointer     = pointerGamepadTouchscreenDevice{}
+	_ mylib.Gamepad     = pointerGamepadTouchscreenDevice{}
+	_ mylib.Touchscreen = pointerGamepadTouchscreenDevice{}
+	_ mylib.Keyboard    = keyboardPointerGamepadTouchscreenDevice{}
+	_ mylib.Pointer     = keyboardPointerGamepadTouchscreenDevice{}
+	_ mylib.Gamepad     = keyboardPointerGamepadTouchscreenDevice{}
+	_ mylib.Touchscreen = keyboardPointerGamepadTouchscreenDevice{}
+)
+
+// Classify returns dev wrapped so that it implements each of
+// [mylib.Keyboard], [mylib.Pointer], [mylib.Gamepad], and
+// [mylib.Touchscreen] that matches its capabilities, letting
+// applications type-assert for them. A laptop keyboard sharing its node
+// with a pointing stick, for example, is both a Keyboard and a Pointer.
+// A device matching none is returned as is. The result still implements
+// [mylib.EventReader] and the other interfaces of [Device], closing it
+// closes dev, and [AsDevice] returns dev from it.
+//
+// A device is a touchscreen if it tracks multi-touch positions directly
+// on a screen ([INPUT_PROP_DIRECT]), a gamepad if it has joystick or
+// gamepad buttons, a pointer if it has mouse buttons or moves along
+// [REL_X], and a keyboard if it has [KEY_A].
+func Classify(dev *Device) (mylib.InputDevice, error) {
+	var (
+		caps     *Capabilities
+		keys     []mylib.InputCode
+		classes  class
+		isButton func(mylib.InputCode) bool
+		keyM     keyMethods
+		buttonM  buttonMethods
+		relM     relativeMethods
+		axisM    axisMethods
+		contactM contactMethods
+		err      error
+	)
+
+	caps, err = dev.Capabilities()
+	if err != nil {
+		return nil, fmt.Errorf("input.Classify: %w", err)
+	}
+
+	keys = caps.Codes[EV_KEY]
+
+	if slices.Contains(keys, KEY_A) {
+		classes |= classKeyboard
+	}
+
+	if slices.ContainsFunc(keys, isMouseButton) || slices.Contains(caps.Codes[EV_REL], REL_X) {
+		classes |= classPointer
+		isButton = isMouseButton
+	}
+
+	if slices.ContainsFunc(keys, isGamepadButton) {
+		classes |= classGamepad
+		isButton = isGamepadButton
+	}
+
+	if classes&classPointer != 0 && classes&classGamepad != 0 {
+		isButton = func(code mylib.InputCode) bool {
+			return isMouseButton(code) || isGamepadButton(code)
+		}
+	}
+
+	if slices.Contains(caps.Properties, INPUT_PROP_DIRECT) && hasAbs(caps, ABS_MT_POSITION_X) {
+		classes |= classTouchscreen
+	}
+
+	keyM = keyMethods{dev: dev}
+	buttonM = buttonMethods{dev: dev, isButton: isButton}
+	relM = relativeMethods{dev: dev}
+	axisM = axisMethods{dev: dev}
+	contactM = contactMethods{dev: dev}
+
+	switch classes {
+	case classKeyboard:
+		return keyboardDevice{dev, keyM}, nil
+	case classPointer:
+		return pointerDevice{dev, buttonM, relM}, nil
+	case classGamepad:
+		return gamepadDevice{dev, buttonM, axisM}, nil
+	case classTouchscreen:
+		return touchscreenDevice{dev, contactM}, nil
+	case classKeyboard | classPointer:
+		return keyboardPointerDevice{dev, keyM, buttonM, relM}, nil
+	case classKeyboard | classGamepad:
+		return keyboardGamepadDevice{dev, keyM, buttonM, axisM}, nil
+	case classKeyboard | classTouchscreen:
+		return keyboardTouchscreenDevice{dev, keyM, contactM}, nil
+	case classPointer | classGamepad:
+		return pointerGamepadDevice{dev, buttonM, relM, axisM}, nil
+	case classPointer | classTouchscreen:
+		return pointerTouchscreenDevice{dev, buttonM, relM, contactM}, nil
+	case classGamepad | classTouchscreen:
+		return gamepadTouchscreenDevice{dev, buttonM, axisM, contactM}, nil
+	case classKeyboard | classPointer | classGamepad:
+		return keyboardPointerGamepadDevice{dev, keyM, buttonM, relM, axisM}, nil
+	case classKeyboard | classPointer | classTouchscreen:
+		return keyboardPointerTouchscreenDevice{dev, keyM, buttonM, relM, contactM}, nil
+	case classKeyboard | classGamepad | classTouchscreen:
+		return keyboardGamepadTouchscreenDevice{dev, keyM, buttonM, axisM, contactM}, nil
+	case classPointer | classGamepad | classTouchscreen:
+		return pointerGamepadTouchscreenDevice{dev, buttonM, relM, axisM, contactM}, nil
+	case classKeyboard | classPointer | classGamepad | classTouchscreen:
+		return keyboardPointerGamepadTouchscreenDevice{dev, keyM, buttonM, relM, axisM, contactM}, nil
+	default:
+		return dev, nil
+	}
+}
+
+// AsDevice returns the [Device] underlying dev, which is either a Device
+// or the result of [Classify].
+func AsDevice(dev mylib.InputDevice) (*Device, bool) {
+	var (
+		unwrapper interface{ device() *Device }
+		ok        bool
+	)
+
+	unwrapper, ok = dev.(interface{ device() *Device })
+	if !ok {
+		return nil, false
+	}
+
+	return unwrapper.device(), true
+}
+
+// device returns dev, and the Device embedded in a classified device.
+func (dev *Device) device() *Device {
+	return dev
+}
+
+// Keys implements [mylib.Keyboard].
+func (methods keyMethods) Keys() ([]mylib.InputCode, error) {
+	return methods.dev.Codes(EV_KEY)
+}
+
+// PressedKeys implements [mylib.Keyboard] with [Device.KeyState].
+func (methods keyMethods) PressedKeys() ([]mylib.InputCode, error) {
+	return methods.dev.KeyState()
+}
+
+// KeyName implements [mylib.Keyboard] with [CodeName].
+func (methods keyMethods) KeyName(code mylib.InputCode) string {
+	return CodeName(EV_KEY, code)
+}
+
+// Buttons implements [mylib.Pointer] and [mylib.Gamepad], returning the
+// mouse buttons, the joystick and gamepad buttons, or both.
+func (methods buttonMethods) Buttons() ([]mylib.InputCode, error) {
+	return methods.dev.buttons(methods.isButton)
+}
+
+// Relative implements [mylib.Pointer].
+func (methods relativeMethods) Relative() (bool, error) {
+	var (
+		codes []mylib.InputCode
+		err   error
+	)
+
+	codes, err = methods.dev.Codes(EV_REL)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(codes, REL_X), nil
+}
+
+// Axes implements [mylib.Gamepad].
+func (methods axisMethods) Axes() ([]mylib.Axis, error) {
+	var (
+		codes []mylib.InputCode
+		code  mylib.InputCode
+		info  AbsInfo
+		axes  []mylib.Axis
+		err   error
+	)
+
+	codes, err = methods.dev.Codes(EV_ABS)
+	if err != nil {
+		return nil, err
+	}
+
+	axes = make([]mylib.Axis, 0, len(codes))
+
+	for _, code = range codes {
+		info, err = methods.dev.AbsInfo(code)
+		if err != nil {
+			return nil, err
+		}
+
+		axes = append(axes, mylib.Axis{
+			Code:    code,
+			Name:    CodeName(EV_ABS, code),
+			Value:   info.Value,
+			Minimum: info.Minimum,
+			Maximum: info.Maximum,
+		})
+	}
+
+	return axes, nil
+}
+
+// MaxContacts implements [mylib.Touchscreen], returning the number of
+// multi-touch slots.
+func (methods contactMethods) MaxContacts() (int, error) {
+	var (
+		info AbsInfo
+		err  error
+	)
+
+	info, err = methods.dev.AbsInfo(ABS_MT_SLOT)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(info.Maximum) + 1, nil
+}
+
+// Contacts implements [mylib.Touchscreen] with [Device.MTSlots].
+func (methods contactMethods) Contacts() ([]mylib.Contact, error) {
+	var (
+		ids, xs, ys []int32
+		contacts    []mylib.Contact
+		slot        int
+		id          int32
+		err         error
+	)
+
+	ids, err = methods.dev.MTSlots(ABS_MT_TRACKING_ID)
+	if err == nil {
+		xs, err = methods.dev.MTSlots(ABS_MT_POSITION_X)
+	}
+
+	if err == nil {
+		ys, err = methods.dev.MTSlots(ABS_MT_POSITION_Y)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for slot, id = range ids {
+		if id >= 0 {
+			contacts = append(contacts, mylib.Contact{ID: id, X: xs[slot], Y: ys[slot]})
+		}
+	}
+
+	return contacts, nil
+}
+
+// buttons returns the key codes of dev for which isButton is true.
+func (dev *Device) buttons(isButton func(mylib.InputCode) bool) ([]mylib.InputCode, error) {
+	var (
+		codes []mylib.InputCode
+		err   error
+	)
+
+	codes, err = dev.Codes(EV_KEY)
+	if err != nil {
+		return nil, err
+	}
+
+	return slices.DeleteFunc(codes, func(code mylib.InputCode) bool {
+		return !isButton(code)
+	}), nil
+}
+
+func hasAbs(caps *Capabilities, axis mylib.InputCode) bool {
+	var ok bool
+
+	_, ok = caps.Abs[axis]
+
+	return ok
+}
+
+func isMouseButton(code mylib.InputCode) bool {
+	return code >= BTN_MOUSE && code < BTN_JOYSTICK
+}
+
+func isGamepadButton(code mylib.InputCode) bool {
+	return code >= BTN_JOYSTICK && code < BTN_DIGI
+}
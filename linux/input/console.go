@@ -0,0 +1,107 @@
+//go:build linux
+
+package input
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/ioctl"
+)
+
+// From linux/kd.h.
+const (
+	kdgkbent     = 0x4b46
+	nrKeys       = 256
+	ktLatin      = 0
+	ktLetter     = 11
+	nrTypes      = 15
+	unicodeFlag  = 0xf000
+	consoleLevel = 4
+)
+
+// kbEntry is struct kbentry from linux/kd.h, used by KDGKBENT.
+type kbEntry struct {
+	table uint8
+	index uint8
+	value uint16
+}
+
+// ConsoleKeymap reads the keymap loaded into the kernel console, for
+// example by loadkeys, through the KDGKBENT ioctl on the terminal at
+// path, such as "/dev/tty" or "/dev/tty1". Use it with [NewKeyboard] so
+// that non-US layouts produce the right characters. The process must be
+// able to open the terminal, which usually means running on it.
+//
+// The plain, Shift, AltGr, and Shift+AltGr levels are read from the
+// kernel's tables 0 through 3. Entries that are not characters, such as
+// function keys, dead keys, and the keypad, are left out; copy them from
+// [USKeymap] if needed.
+func ConsoleKeymap(path string) (Keymap, error) {
+	var (
+		file   *os.File
+		keymap Keymap
+		entry  kbEntry
+		levels [consoleLevel]rune
+		index  int
+		level  int
+		found  bool
+		err    error
+	)
+
+	file, err = os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("input.ConsoleKeymap: %w", err)
+	}
+
+	defer file.Close()
+
+	keymap = make(Keymap)
+
+	for index = range nrKeys {
+		levels, found = [consoleLevel]rune{}, false
+
+		for level = range consoleLevel {
+			entry = kbEntry{table: uint8(level), index: uint8(index)}
+
+			err = ioctl.Any(file.Fd(), kdgkbent, &entry)
+			if err != nil {
+				return nil, fmt.Errorf("input.ConsoleKeymap: %w", err)
+			}
+
+			levels[level] = consoleRune(entry.value)
+			found = found || levels[level] != 0
+		}
+
+		if found {
+			keymap[mylib.InputCode(index)] = levels
+		}
+	}
+
+	return keymap, nil
+}
+
+// consoleRune converts a console keymap value to the character it
+// produces, or 0 if it produces none.
+func consoleRune(value uint16) rune {
+	var typ, val uint16
+
+	typ, val = value>>8, value&0xff
+
+	switch {
+	case typ >= nrTypes:
+		return rune(value ^ unicodeFlag)
+	case typ == ktLatin || typ == ktLetter:
+		// Control characters are produced by Ctrl combinations, which
+		// the Keyboard derives itself.
+		if val < 0x20 || val == 0x7f {
+			return 0
+		}
+
+		return rune(val)
+	default:
+		return 0
+	}
+}
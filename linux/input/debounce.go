@@ -0,0 +1,134 @@
+//go:build linux
+
+package input
+
+import (
+	"slices"
+	"time"
+
+	"github.com/andrieee44/mylib"
+)
+
+// Debounce is a [Filter] for chattering contacts, such as worn mouse
+// switches that report a click as press, release, press, release, or
+// flaky SW_* sensors.
+//
+// A release, value 0, of a debounced key or switch is held back. If the
+// same code turns on again within Window, the release and the new press
+// are both dropped, so that the bounce never reaches the reader. The
+// release is emitted once a later event is at least Window newer, judged
+// by event timestamps, or by [Debounce.Flush] if the device reports
+// nothing else within Window. [Remapper] calls Flush on its own; other
+// readers should wait with a read deadline from [Debounce.FlushAfter].
+type Debounce struct {
+	// Window is how soon after a release a press counts as a bounce.
+	Window time.Duration
+
+	// Keys lists the EV_KEY codes to debounce, such as BTN_LEFT. If
+	// empty, no keys are debounced.
+	Keys []mylib.InputCode
+
+	// Switches lists the EV_SW codes to debounce, such as SW_LID. If
+	// empty, no switches are debounced.
+	Switches []mylib.InputCode
+
+	pending []Event
+	out     []Event
+}
+
+var (
+	_ Filter  = (*Debounce)(nil)
+	_ Flusher = (*Debounce)(nil)
+)
+
+// Filter removes bounces from frame.
+func (filter *Debounce) Filter(frame []Event) []Event {
+	var (
+		ev    Event
+		index int
+	)
+
+	filter.out = filter.out[:0]
+
+	for _, ev = range frame {
+		filter.expire(eventTime(ev))
+
+		if !filter.debounced(ev) {
+			filter.out = append(filter.out, ev)
+
+			continue
+		}
+
+		index = slices.IndexFunc(filter.pending, func(pending Event) bool {
+			return pending.Type == ev.Type && pending.Code == ev.Code
+		})
+
+		switch {
+		case ev.Value == 0 && index < 0:
+			filter.pending = append(filter.pending, ev)
+		case ev.Value == 1 && index >= 0:
+			filter.pending = slices.Delete(filter.pending, index, index+1)
+		default:
+			filter.out = append(filter.out, ev)
+		}
+	}
+
+	return filter.out
+}
+
+func (filter *Debounce) debounced(ev Event) bool {
+	switch ev.Type {
+	case EV_KEY:
+		return slices.Contains(filter.Keys, mylib.InputCode(ev.Code))
+	case EV_SW:
+		return slices.Contains(filter.Switches, mylib.InputCode(ev.Code))
+	default:
+		return false
+	}
+}
+
+// expire emits the held-back releases that are at least Window older
+// than now.
+func (filter *Debounce) expire(now time.Duration) {
+	var (
+		kept []Event
+		ev   Event
+	)
+
+	kept = filter.pending[:0]
+
+	for _, ev = range filter.pending {
+		if now-eventTime(ev) < filter.Window {
+			kept = append(kept, ev)
+
+			continue
+		}
+
+		filter.out = append(filter.out, ev, synthSyn(ev))
+	}
+
+	filter.pending = kept
+}
+
+// FlushAfter implements [Flusher], returning Window while a release is
+// held back.
+func (filter *Debounce) FlushAfter() (time.Duration, bool) {
+	return filter.Window, len(filter.pending) != 0
+}
+
+// Flush implements [Flusher], emitting every held-back release, each in
+// a frame of its own.
+func (filter *Debounce) Flush() []Event {
+	var (
+		events []Event
+		ev     Event
+	)
+
+	for _, ev = range filter.pending {
+		events = append(events, ev, synthSyn(ev))
+	}
+
+	filter.pending = filter.pending[:0]
+
+	return events
+}
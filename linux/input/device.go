@@ -3,48 +3,163 @@
 package input
 
 import (
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
 
 	"github.com/andrieee44/mylib"
 	"github.com/andrieee44/mylib/linux/ioctl"
 	"golang.org/x/sys/unix"
 )
 
+// ErrClosed is returned by the methods of a [Device] once it is closed.
+var ErrClosed error = errors.New("device closed")
+
 // Device represents an evdev input device.
 // It wraps the opened /dev/input/eventN file.
+//
+// A Device is safe for concurrent use, so one goroutine may read its
+// events while others issue control calls such as [Device.Grab] or
+// [Device.UploadEffect]. Concurrent calls to [Device.ReadEvent] are
+// serialized and each returns whole events, but they share the event
+// stream, so no reader sees every frame.
 type Device struct {
-	file *os.File
-	fd   uintptr
+	file       *os.File
+	nonblock   bool
+	readMutex  sync.Mutex
+	clockMutex sync.Mutex
+	clock      atomic.Int32
+	latency    *latencyRecorder
+	closed     atomic.Bool
+
+	// rumbleMutex guards rumble, the ID of the effect reused by
+	// [Device.Rumble], valid if hasRumble is set.
+	rumbleMutex sync.Mutex
+	rumble      int16
+	hasRumble   bool
+}
+
+var (
+	_ mylib.InputDevice   = (*Device)(nil)
+	_ mylib.EventReader   = (*Device)(nil)
+	_ mylib.LEDController = (*Device)(nil)
+)
+
+// Property is an input device property, one of the INPUT_PROP_*
+// constants. Properties describe how a device should be interpreted,
+// such as whether a touchpad is a clickpad.
+type Property uint
+
+// String returns the INPUT_PROP_* name of the property.
+func (prop Property) String() string {
+	var (
+		name string
+		ok   bool
+	)
+
+	name, ok = propertyNames[prop]
+	if !ok {
+		return fmt.Sprintf("INPUT_PROP_%#02x", uint(prop))
+	}
+
+	return name
 }
 
-var _ mylib.InputDevice = (*Device)(nil)
+// Switch is the state of a single switch, such as a laptop lid or a
+// headphone jack.
+type Switch struct {
+	// Code is the SW_* code of the switch.
+	Code mylib.InputCode
+
+	// Name is the SW_* name of the switch, such as "SW_LID".
+	Name string
+
+	// On reports whether the switch is active, for example whether the
+	// lid is closed or headphones are inserted.
+	On bool
+}
 
 // NewDevice opens the evdev device at the given path and returns a Device.
 // The path is cleaned before opening, and the device file is opened
-// in read-write mode. The caller is responsible for closing the device
-// when no longer needed.
-func NewDevice(path string) (*Device, error) {
+// in read-write mode unless the [ReadOnly] option is given. The caller
+// is responsible for closing the device when no longer needed.
+func NewDevice(path string, opts ...Option) (*Device, error) {
 	var (
-		device *Device
-		file   *os.File
-		err    error
+		device  *Device
+		options openOptions
+		opt     Option
+		fd      int
+		err     error
 	)
 
-	file, err = os.OpenFile(filepath.Clean(path), os.O_RDWR, 0)
-	if err != nil {
-		return nil, fmt.Errorf("input.NewDevice: %w", err)
+	options.flags = os.O_RDWR
+	for _, opt = range opts {
+		opt(&options)
+	}
+
+	path = filepath.Clean(path)
+	device = &Device{nonblock: options.nonblock}
+	if options.latencySamples != 0 {
+		device.latency = newLatencyRecorder(options.latencySamples)
 	}
 
-	device = &Device{
-		file: file,
-		fd:   file.Fd(),
+	if options.nonblock {
+		// The descriptor is kept out of os.File.Fd, which would switch it
+		// back to blocking mode.
+		fd, err = unix.Open(path, options.flags|unix.O_NONBLOCK|unix.O_CLOEXEC, 0)
+		if err != nil {
+			return nil, fmt.Errorf("input.NewDevice: %w", &os.PathError{Op: "open", Path: path, Err: err})
+		}
+
+		device.file = os.NewFile(uintptr(fd), path)
+	} else {
+		device.file, err = os.OpenFile(path, options.flags, 0)
+		if err != nil {
+			return nil, fmt.Errorf("input.NewDevice: %w", err)
+		}
+
+		// Fd puts the descriptor in blocking mode for the reads in
+		// ReadEvent.
+		_ = device.file.Fd()
+	}
+
+	if options.grab {
+		err = device.Grab()
+		if err != nil {
+			_ = device.file.Close()
+
+			return nil, fmt.Errorf("input.NewDevice: %w", err)
+		}
 	}
 
 	return device, nil
 }
 
+// NewDeviceFromFile returns a Device that reads from and writes to an
+// already open file, such as a descriptor received from
+// systemd-logind's TakeDevice or from a privileged helper. The Device
+// takes ownership of file and closes it on [Device.Close].
+//
+// The file does not have to be an event device: [Device.ReadEvent]
+// decodes events from any stream, such as a pipe fed with recorded
+// events, while the ioctl-based methods then fail with ENOTTY.
+func NewDeviceFromFile(file *os.File) *Device {
+	// Fd puts the descriptor in blocking mode for the reads in
+	// ReadEvent.
+	_ = file.Fd()
+
+	return &Device{file: file}
+}
+
 // Devices scans /dev/input for event devices, opens each one, and
 // returns a slice of Device pointers. If any device fails to open,
 // an error is returned and no devices are returned.
@@ -57,7 +172,7 @@ func Devices() ([]*Device, error) {
 		err     error
 	)
 
-	paths, err = filepath.Glob("/dev/input/event*")
+	paths, err = NewSystem(nil).EventNodes()
 	if err != nil {
 		return nil, fmt.Errorf("input.Devices: %w", err)
 	}
@@ -75,10 +190,95 @@ func Devices() ([]*Device, error) {
 	return devices, nil
 }
 
+// InputDevices is like [Devices], but returns each device classified
+// with [Classify], so that the result can be type-asserted to the
+// device class interfaces of the mylib package, such as
+// [mylib.Keyboard].
+func InputDevices() ([]mylib.InputDevice, error) {
+	var (
+		devices    []*Device
+		device     *Device
+		classified []mylib.InputDevice
+		inputDev   mylib.InputDevice
+		err        error
+	)
+
+	devices, err = Devices()
+	if err != nil {
+		return nil, fmt.Errorf("input.InputDevices: %w", err)
+	}
+
+	classified = make([]mylib.InputDevice, 0, len(devices))
+	for _, device = range devices {
+		inputDev, err = Classify(device)
+		if err != nil {
+			for _, device = range devices {
+				_ = device.Close()
+			}
+
+			return nil, fmt.Errorf("input.InputDevices: %w", err)
+		}
+
+		classified = append(classified, inputDev)
+	}
+
+	return classified, nil
+}
+
 // Name returns the human-readable name of the evdev device.
 // It sends the [EVIOCGNAME] ioctl to read up to 256 bytes and
 // converts the null-terminated result into a Go string.
 func (dev *Device) Name() (string, error) {
+	var (
+		name string
+		err  error
+	)
+
+	name, err = dev.ioctlString(EVIOCGNAME)
+	if err != nil {
+		return "", fmt.Errorf("Device.Name: %w", err)
+	}
+
+	return name, nil
+}
+
+// Phys returns the physical location of the device in the system
+// topology, such as "usb-0000:00:14.0-1/input0". It sends the
+// [EVIOCGPHYS] ioctl and returns an empty string if the driver does not
+// report one.
+func (dev *Device) Phys() (string, error) {
+	var (
+		phys string
+		err  error
+	)
+
+	phys, err = dev.ioctlString(EVIOCGPHYS)
+	if err != nil {
+		return "", fmt.Errorf("Device.Phys: %w", err)
+	}
+
+	return phys, nil
+}
+
+// Uniq returns the unique identifier of the device, such as the MAC
+// address of a Bluetooth device or the serial number of a USB device.
+// It sends the [EVIOCGUNIQ] ioctl and returns an empty string if the
+// driver does not report one.
+func (dev *Device) Uniq() (string, error) {
+	var (
+		uniq string
+		err  error
+	)
+
+	uniq, err = dev.ioctlString(EVIOCGUNIQ)
+	if err != nil {
+		return "", fmt.Errorf("Device.Uniq: %w", err)
+	}
+
+	return uniq, nil
+}
+
+func (dev *Device) ioctlString(req func(length uint) uint) (string, error) {
 	var (
 		buf []byte
 		err error
@@ -86,9 +286,13 @@ func (dev *Device) Name() (string, error) {
 
 	buf = make([]byte, 256)
 
-	err = ioctl.Any(dev.fd, EVIOCGNAME(256), &buf[0])
+	err = deviceIoctl(dev, req(uint(len(buf))), &buf[0])
+	if errors.Is(err, unix.ENOENT) {
+		return "", nil
+	}
+
 	if err != nil {
-		return "", fmt.Errorf("Device.Name: %w", err)
+		return "", err
 	}
 
 	return unix.ByteSliceToString(buf), nil
@@ -105,7 +309,7 @@ func (dev *Device) ID() (string, error) {
 		err error
 	)
 
-	err = ioctl.Any(dev.fd, EVIOCGID, &id)
+	err = deviceIoctl(dev, EVIOCGID, &id)
 	if err != nil {
 		return "", fmt.Errorf("Device.ID: %w", err)
 	}
@@ -119,38 +323,61 @@ func (dev *Device) ID() (string, error) {
 	), nil
 }
 
+// InputID returns the bus type, vendor, product, and version of the
+// device, as issued by the [EVIOCGID] ioctl, for code that needs them as
+// numbers rather than as the string returned by [Device.ID].
+func (dev *Device) InputID() (ID, error) {
+	var (
+		id  ID
+		err error
+	)
+
+	err = deviceIoctl(dev, EVIOCGID, &id)
+	if err != nil {
+		return ID{}, fmt.Errorf("Device.InputID: %w", err)
+	}
+
+	return id, nil
+}
+
+// DriverVersion returns the version of the evdev driver, decoded from
+// the [EVIOCGVERSION] ioctl, for example 1.0.1 for [EV_VERSION]. It is
+// useful for feature-gating newer ioctls.
+func (dev *Device) DriverVersion() (major, minor, patch uint, err error) {
+	var version int32
+
+	err = deviceIoctl(dev, EVIOCGVERSION, &version)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("Device.DriverVersion: %w", err)
+	}
+
+	return uint(version >> 16), uint(version>>8) & 0xff, uint(version) & 0xff, nil
+}
+
 // Events returns a slice of all supported event types for the device.
 func (dev *Device) Events() ([]mylib.InputEvent, error) {
 	var (
-		buf       []byte
+		mask      *Bitmask
 		events    []mylib.InputEvent
-		eventType mylib.InputEvent
+		eventType mylib.InputCode
 		err       error
 	)
 
-	buf = make([]byte, (EV_MAX+7)/8)
+	mask = NewBitmask(EV_MAX)
 
-	err = ioctl.Any(
-		dev.fd,
-		EVIOCGBIT(0, uint(len(buf))),
-		&buf[0],
-	)
+	err = dev.readBitmask(EVIOCGBIT(0, mask.size()), mask)
 	if err != nil {
 		return nil, fmt.Errorf("Device.Events: %w", err)
 	}
 
-	events = make([]mylib.InputEvent, 0, EV_CNT)
-
-	for eventType = range EV_CNT {
-		if !TestBit(buf, uint(eventType)) {
-			continue
-		}
+	events = make([]mylib.InputEvent, 0, mask.Count())
 
+	for eventType = range mask.Iterate() {
 		if eventType == EV_REP {
 			continue
 		}
 
-		events = append(events, eventType)
+		events = append(events, mylib.InputEvent(eventType))
 	}
 
 	return events, nil
@@ -160,46 +387,611 @@ func (dev *Device) Events() ([]mylib.InputEvent, error) {
 // eventType.
 func (dev *Device) Codes(eventType mylib.InputEvent) ([]mylib.InputCode, error) {
 	var (
-		buf            []byte
-		codes          []mylib.InputCode
-		maxCodes, code uint
-		ok             bool
-		err            error
+		mask *Bitmask
+		err  error
+	)
+
+	mask, err = dev.CodeMask(eventType)
+	if err != nil {
+		return nil, fmt.Errorf("Device.Codes: %w", err)
+	}
+
+	return mask.Codes(), nil
+}
+
+// CodeMask is like [Device.Codes], but returns the supported codes as a
+// [Bitmask], for testing many codes without building a slice.
+func (dev *Device) CodeMask(eventType mylib.InputEvent) (*Bitmask, error) {
+	var (
+		mask     *Bitmask
+		maxCodes uint
+		ok       bool
+		err      error
 	)
 
 	maxCodes, ok = MaxCodes(eventType)
 	if !ok {
-		return nil, fmt.Errorf("Device.Codes: %w %d", ErrInvalidEventType, eventType)
+		return nil, fmt.Errorf("Device.CodeMask: %w %d", ErrInvalidEventType, eventType)
 	}
 
-	buf = make([]byte, (maxCodes+7)/8)
+	mask = NewBitmask(maxCodes)
+
+	err = dev.readBitmask(EVIOCGBIT(uint(eventType), mask.size()), mask)
+	if err != nil {
+		return nil, fmt.Errorf("Device.CodeMask: %w", err)
+	}
 
-	err = ioctl.Any(
-		dev.fd,
-		EVIOCGBIT(uint(eventType), uint(len(buf))),
-		&buf[0],
+	return mask, nil
+}
+
+// EventMask returns the event codes of eventType that are currently
+// forwarded to this file descriptor. It issues the [EVIOCGMASK] ioctl
+// with a bitmask buffer sized for eventType. By default, every code is
+// forwarded.
+func (dev *Device) EventMask(eventType mylib.InputEvent) ([]mylib.InputCode, error) {
+	var (
+		bitmask *Bitmask
+		mask    Mask
+		maxCode uint
+		ok      bool
+		err     error
 	)
+
+	maxCode, ok = MaxCodes(eventType)
+	if !ok {
+		return nil, fmt.Errorf("Device.EventMask: %w %d", ErrInvalidEventType, eventType)
+	}
+
+	bitmask = NewBitmask(maxCode)
+	mask = Mask{
+		Type:      uint32(eventType),
+		CodesSize: uint32(bitmask.size()),
+		CodesPtr:  uint64(uintptr(unsafe.Pointer(&bitmask.words[0]))),
+	}
+
+	err = deviceIoctl(dev, EVIOCGMASK(), &mask)
+	runtime.KeepAlive(bitmask)
+
 	if err != nil {
-		return nil, fmt.Errorf("Device.Codes: %w", err)
+		return nil, fmt.Errorf("Device.EventMask: %w", err)
 	}
 
-	codes = make([]mylib.InputCode, 0, maxCodes+1)
+	return bitmask.Codes(), nil
+}
 
-	for code = range maxCodes + 1 {
-		if !TestBit(buf, code) {
-			continue
+// SetEventMask changes which event codes of eventType are forwarded to
+// this file descriptor. Only the given codes are delivered afterwards;
+// all other codes of eventType are filtered by the kernel. The mask only
+// affects this file descriptor, not the global state of the device.
+// It issues the [EVIOCSMASK] ioctl.
+func (dev *Device) SetEventMask(
+	eventType mylib.InputEvent,
+	codes []mylib.InputCode,
+) error {
+	var (
+		bitmask *Bitmask
+		mask    Mask
+		maxCode uint
+		code    mylib.InputCode
+		ok      bool
+		err     error
+	)
+
+	maxCode, ok = MaxCodes(eventType)
+	if !ok {
+		return fmt.Errorf("Device.SetEventMask: %w %d", ErrInvalidEventType, eventType)
+	}
+
+	bitmask = NewBitmask(maxCode)
+
+	for _, code = range codes {
+		if uint(code) > maxCode {
+			return fmt.Errorf("Device.SetEventMask: %w %d", ErrInvalidEventCode, code)
 		}
 
-		codes = append(codes, mylib.InputCode(code))
+		bitmask.Set(code)
+	}
+
+	mask = Mask{
+		Type:      uint32(eventType),
+		CodesSize: uint32(bitmask.size()),
+		CodesPtr:  uint64(uintptr(unsafe.Pointer(&bitmask.words[0]))),
+	}
+
+	err = deviceIoctl(dev, EVIOCSMASK(), &mask)
+	runtime.KeepAlive(bitmask)
+
+	if err != nil {
+		return fmt.Errorf("Device.SetEventMask: %w", err)
+	}
+
+	return nil
+}
+
+// Repeat returns the keyboard auto-repeat settings of the device: the
+// delay before a held key starts repeating and the period between
+// repeats. It issues the [EVIOCGREP] ioctl.
+func (dev *Device) Repeat() (delay, period time.Duration, err error) {
+	var rep [2]uint32
+
+	err = deviceIoctl(dev, EVIOCGREP, &rep)
+	if err != nil {
+		return 0, 0, fmt.Errorf("Device.Repeat: %w", err)
+	}
+
+	return time.Duration(rep[0]) * time.Millisecond,
+		time.Duration(rep[1]) * time.Millisecond,
+		nil
+}
+
+// SetRepeat changes the keyboard auto-repeat settings of the device.
+// Both delay and period are truncated to whole milliseconds.
+// It issues the [EVIOCSREP] ioctl.
+func (dev *Device) SetRepeat(delay, period time.Duration) error {
+	var (
+		rep [2]uint32
+		err error
+	)
+
+	rep = [2]uint32{
+		uint32(delay.Milliseconds()),
+		uint32(period.Milliseconds()),
+	}
+
+	err = deviceIoctl(dev, EVIOCSREP, &rep)
+	if err != nil {
+		return fmt.Errorf("Device.SetRepeat: %w", err)
+	}
+
+	return nil
+}
+
+// AbsInfo returns the parameters of the absolute axis: its current
+// value, minimum, maximum, fuzz, flat, and resolution. It issues the
+// [EVIOCGABS] ioctl.
+func (dev *Device) AbsInfo(axis mylib.InputCode) (AbsInfo, error) {
+	var (
+		info AbsInfo
+		err  error
+	)
+
+	if axis > ABS_MAX {
+		return AbsInfo{}, fmt.Errorf("Device.AbsInfo: %w %d", ErrInvalidEventCode, axis)
+	}
+
+	err = deviceIoctl(dev, EVIOCGABS(uint(axis)), &info)
+	if err != nil {
+		return AbsInfo{}, fmt.Errorf("Device.AbsInfo: %w", err)
+	}
+
+	return info, nil
+}
+
+// SetAbsInfo writes the parameters of the absolute axis back to the
+// kernel, for example to correct its range, dead zone, or fuzz after
+// calibration. It issues the [EVIOCSABS] ioctl.
+func (dev *Device) SetAbsInfo(axis mylib.InputCode, info AbsInfo) error {
+	var err error
+
+	if axis > ABS_MAX {
+		return fmt.Errorf("Device.SetAbsInfo: %w %d", ErrInvalidEventCode, axis)
+	}
+
+	err = deviceIoctl(dev, EVIOCSABS(uint(axis)), &info)
+	if err != nil {
+		return fmt.Errorf("Device.SetAbsInfo: %w", err)
+	}
+
+	return nil
+}
+
+// readBitmask issues req to fill mask and drops the bits beyond the
+// bytes the kernel reported writing, in case the running kernel knows
+// fewer codes of the type than this package.
+func (dev *Device) readBitmask(req uint, mask *Bitmask) error {
+	var (
+		n   int
+		err error
+	)
+
+	err = dev.control(func(fd uintptr) error {
+		var ioctlErr error
+
+		n, ioctlErr = ioctl.AnyN(fd, req, &mask.words[0])
+
+		return ioctlErr
+	})
+	if err != nil {
+		return err
+	}
+
+	mask.truncate(n)
+
+	return nil
+}
+
+// KeyState returns the keys and buttons currently held down on the
+// device. It issues the [EVIOCGKEY] ioctl, letting applications seed
+// their key state at startup instead of waiting for the next transition.
+func (dev *Device) KeyState() ([]mylib.InputCode, error) {
+	var (
+		mask *Bitmask
+		err  error
+	)
+
+	mask = NewBitmask(KEY_MAX)
+
+	err = dev.readBitmask(EVIOCGKEY(mask.size()), mask)
+	if err != nil {
+		return nil, fmt.Errorf("Device.KeyState: %w", err)
+	}
+
+	return mask.Codes(), nil
+}
+
+// LEDState returns the LEDs currently lit on the device, such as
+// [LED_CAPSL]. It issues the [EVIOCGLED] ioctl.
+func (dev *Device) LEDState() ([]mylib.InputCode, error) {
+	var (
+		mask *Bitmask
+		err  error
+	)
+
+	mask = NewBitmask(LED_MAX)
+
+	err = dev.readBitmask(EVIOCGLED(mask.size()), mask)
+	if err != nil {
+		return nil, fmt.Errorf("Device.LEDState: %w", err)
+	}
+
+	return mask.Codes(), nil
+}
+
+// LEDs implements [mylib.LEDController], returning the [EV_LED] codes
+// of the device.
+func (dev *Device) LEDs() ([]mylib.InputCode, error) {
+	var (
+		codes []mylib.InputCode
+		err   error
+	)
+
+	codes, err = dev.Codes(EV_LED)
+	if err != nil {
+		return nil, fmt.Errorf("Device.LEDs: %w", err)
 	}
 
 	return codes, nil
 }
 
+// LED implements [mylib.LEDController], reporting whether the LED code,
+// such as [LED_CAPSL], is lit. It returns [ErrInvalidEventCode] if the
+// device has no such LED.
+func (dev *Device) LED(code mylib.InputCode) (bool, error) {
+	var (
+		mask *Bitmask
+		err  error
+	)
+
+	mask, err = dev.CodeMask(EV_LED)
+	if err != nil {
+		return false, fmt.Errorf("Device.LED: %w", err)
+	}
+
+	if !mask.Test(code) {
+		return false, fmt.Errorf("Device.LED: %w %d", ErrInvalidEventCode, code)
+	}
+
+	mask = NewBitmask(LED_MAX)
+
+	err = dev.readBitmask(EVIOCGLED(mask.size()), mask)
+	if err != nil {
+		return false, fmt.Errorf("Device.LED: %w", err)
+	}
+
+	return mask.Test(code), nil
+}
+
+// MTSlots returns the current value of the multi-touch axis in every
+// slot, indexed by slot number, using the [EVIOCGMTSLOTS] ioctl. The
+// number of slots is taken from the range of [ABS_MT_SLOT].
+func (dev *Device) MTSlots(axis mylib.InputCode) ([]int32, error) {
+	var (
+		info AbsInfo
+		buf  []int32
+		err  error
+	)
+
+	if axis <= ABS_MT_SLOT || axis > ABS_MAX {
+		return nil, fmt.Errorf("Device.MTSlots: %w %d", ErrInvalidEventCode, axis)
+	}
+
+	info, err = dev.AbsInfo(ABS_MT_SLOT)
+	if err != nil {
+		return nil, fmt.Errorf("Device.MTSlots: %w", err)
+	}
+
+	buf = make([]int32, 1+max(info.Maximum+1, 0))
+	buf[0] = int32(axis)
+
+	err = deviceIoctl(dev, EVIOCGMTSLOTS(uint(len(buf)*4)), &buf[0])
+	if err != nil {
+		return nil, fmt.Errorf("Device.MTSlots: %w", err)
+	}
+
+	return buf[1:], nil
+}
+
+// Properties returns the properties of the device, such as
+// [INPUT_PROP_POINTER] or [INPUT_PROP_BUTTONPAD]. It issues the
+// [EVIOCGPROP] ioctl.
+func (dev *Device) Properties() ([]Property, error) {
+	var (
+		mask  *Bitmask
+		props []Property
+		prop  mylib.InputCode
+		err   error
+	)
+
+	mask = NewBitmask(INPUT_PROP_MAX)
+
+	err = dev.readBitmask(EVIOCGPROP(mask.size()), mask)
+	if err != nil {
+		return nil, fmt.Errorf("Device.Properties: %w", err)
+	}
+
+	props = make([]Property, 0, mask.Count())
+
+	for prop = range mask.Iterate() {
+		props = append(props, Property(prop))
+	}
+
+	return props, nil
+}
+
+// Switches returns the state of every switch the device supports.
+// It issues the [EVIOCGSW] ioctl, letting power-management daemons read
+// the lid, dock, and jack states at startup.
+func (dev *Device) Switches() ([]Switch, error) {
+	var (
+		mask     *Bitmask
+		codes    []mylib.InputCode
+		code     mylib.InputCode
+		switches []Switch
+		err      error
+	)
+
+	codes, err = dev.Codes(EV_SW)
+	if err != nil {
+		return nil, fmt.Errorf("Device.Switches: %w", err)
+	}
+
+	mask = NewBitmask(SW_MAX)
+
+	err = dev.readBitmask(EVIOCGSW(mask.size()), mask)
+	if err != nil {
+		return nil, fmt.Errorf("Device.Switches: %w", err)
+	}
+
+	switches = make([]Switch, 0, len(codes))
+	for _, code = range codes {
+		switches = append(switches, Switch{
+			Code: code,
+			Name: CodeName(EV_SW, code),
+			On:   mask.Test(code),
+		})
+	}
+
+	return switches, nil
+}
+
+// Sounds returns the sound codes, such as [SND_BELL] or [SND_TONE],
+// that are currently active on the device. It issues the [EVIOCGSND]
+// ioctl.
+func (dev *Device) Sounds() ([]mylib.InputCode, error) {
+	var (
+		mask *Bitmask
+		err  error
+	)
+
+	mask = NewBitmask(SND_MAX)
+
+	err = dev.readBitmask(EVIOCGSND(mask.size()), mask)
+	if err != nil {
+		return nil, fmt.Errorf("Device.Sounds: %w", err)
+	}
+
+	return mask.Codes(), nil
+}
+
+// Beep writes an [EV_SND] event to the device. For [SND_BELL] and
+// [SND_CLICK], a non-zero value starts the sound and zero stops it; for
+// [SND_TONE], value is the tone frequency in hertz, with zero stopping
+// the tone.
+func (dev *Device) Beep(code mylib.InputCode, value int32) error {
+	var err error
+
+	if code > SND_MAX {
+		return fmt.Errorf("Device.Beep: %w %d", ErrInvalidEventCode, code)
+	}
+
+	err = dev.WriteEvent(Event{Type: EV_SND, Code: uint16(code), Value: value})
+	if err != nil {
+		return fmt.Errorf("Device.Beep: %w", err)
+	}
+
+	return nil
+}
+
+// SetLED implements [mylib.LEDController]. It lights or turns off an
+// LED of the device, such as [LED_CAPSL], by writing an [EV_LED] event
+// to it.
+func (dev *Device) SetLED(code mylib.InputCode, on bool) error {
+	var (
+		value int32
+		err   error
+	)
+
+	if code > LED_MAX {
+		return fmt.Errorf("Device.SetLED: %w %d", ErrInvalidEventCode, code)
+	}
+
+	if on {
+		value = 1
+	}
+
+	err = dev.WriteEvent(Event{Type: EV_LED, Code: uint16(code), Value: value})
+	if err != nil {
+		return fmt.Errorf("Device.SetLED: %w", err)
+	}
+
+	return nil
+}
+
+// ReadEvent blocks until the next input event is available on the
+// device and returns it. If the device was opened with [NonBlocking], it
+// instead returns an error wrapping [unix.EAGAIN] when no event is
+// queued.
+func (dev *Device) ReadEvent() (Event, error) {
+	var (
+		raw rawEvent
+		ev  Event
+		err error
+	)
+
+	dev.readMutex.Lock()
+	defer dev.readMutex.Unlock()
+
+	if dev.nonblock {
+		raw, err = dev.readEventNonblock()
+	} else {
+		err = binary.Read(dev.file, binary.NativeEndian, &raw)
+	}
+
+	if err != nil {
+		return Event{}, fmt.Errorf("Device.ReadEvent: %w", dev.closedErr(err))
+	}
+
+	ev = raw.event()
+	if dev.latency != nil {
+		dev.latency.record(ev.Since(dev.clock.Load()))
+	}
+
+	return ev, nil
+}
+
+// ReadInput implements [mylib.EventReader]. It returns the next event
+// like [Device.ReadEvent], stamped on the wall clock by [Event.Time].
+// For a device opened with [NonBlocking], it waits for the event
+// instead of returning [unix.EAGAIN], and closing the device interrupts
+// the wait.
+func (dev *Device) ReadInput() (mylib.Event, error) {
+	var (
+		ev  Event
+		err error
+	)
+
+	if dev.nonblock {
+		ev, err = waitEvent(dev, dev.ReadEvent)
+	} else {
+		ev, err = dev.ReadEvent()
+	}
+
+	if err != nil {
+		return mylib.Event{}, fmt.Errorf("Device.ReadInput: %w", err)
+	}
+
+	return mylib.Event{
+		Time:  ev.Time(dev.clock.Load()),
+		Type:  mylib.InputEvent(ev.Type),
+		Code:  mylib.InputCode(ev.Code),
+		Value: ev.Value,
+	}, nil
+}
+
+// WriteEvent writes a single input event to the device. The kernel
+// routes written events to the device driver, which is how LEDs, sounds,
+// and force-feedback playback are controlled. The timestamp is ignored.
+func (dev *Device) WriteEvent(ev Event) error {
+	var (
+		raw rawEvent
+		err error
+	)
+
+	raw = newRawEvent(ev)
+
+	err = binary.Write(dev.file, binary.NativeEndian, &raw)
+	if err != nil {
+		return fmt.Errorf("Device.WriteEvent: %w", dev.closedErr(err))
+	}
+
+	return nil
+}
+
+// Grab gives the calling process exclusive access to the device's events
+// with [EVIOCGRAB]. Other readers, including the display server, stop
+// receiving events until [Device.Ungrab] is called or the device is
+// closed.
+func (dev *Device) Grab() error {
+	var err error
+
+	err = dev.ioctlValue(EVIOCGRAB(), 1)
+	if err != nil {
+		return fmt.Errorf("Device.Grab: %w", err)
+	}
+
+	return nil
+}
+
+// Ungrab releases a grab taken with [Device.Grab].
+func (dev *Device) Ungrab() error {
+	var err error
+
+	err = dev.ioctlValue(EVIOCGRAB(), 0)
+	if err != nil {
+		return fmt.Errorf("Device.Ungrab: %w", err)
+	}
+
+	return nil
+}
+
+// SetClock selects the clock the kernel stamps the events of the device
+// with, one of [unix.CLOCK_REALTIME], the default, [unix.CLOCK_MONOTONIC],
+// or [unix.CLOCK_BOOTTIME]. It issues the [EVIOCSCLOCKID] ioctl; events
+// already queued keep their old timestamps.
+func (dev *Device) SetClock(clock int32) error {
+	var err error
+
+	dev.clockMutex.Lock()
+	defer dev.clockMutex.Unlock()
+
+	err = deviceIoctl(dev, EVIOCSCLOCKID(), &clock)
+	if err != nil {
+		return fmt.Errorf("Device.SetClock: %w", err)
+	}
+
+	dev.clock.Store(clock)
+
+	return nil
+}
+
+// Clock returns the clock set with [Device.SetClock], for passing to
+// [Event.Time] and [Event.Since].
+func (dev *Device) Clock() int32 {
+	return dev.clock.Load()
+}
+
 // Close closes the evdev device by closing its underlying file handle.
+// Calling it again does nothing, and every other method then returns
+// [ErrClosed]. Reads blocked on a device opened with [NonBlocking],
+// such as those of [Merge] or a [BufferedReader], return at once; a
+// blocking read returns when the next event arrives.
 func (dev *Device) Close() error {
 	var err error
 
+	if dev.closed.Swap(true) {
+		return nil
+	}
+
 	err = dev.file.Close()
 	if err != nil {
 		return fmt.Errorf("Device.Close: %w", err)
@@ -207,3 +999,121 @@ func (dev *Device) Close() error {
 
 	return nil
 }
+
+// control calls fn with the descriptor of the device, which cannot be
+// closed, and so reused for another file, until fn returns. It returns
+// [ErrClosed] once the device is closed.
+func (dev *Device) control(fn func(fd uintptr) error) error {
+	var (
+		raw   syscall.RawConn
+		fnErr error
+		err   error
+	)
+
+	if dev.closed.Load() {
+		return ErrClosed
+	}
+
+	raw, err = dev.file.SyscallConn()
+	if err == nil {
+		err = raw.Control(func(fd uintptr) {
+			fnErr = fn(fd)
+		})
+	}
+
+	if err != nil {
+		return dev.closedErr(err)
+	}
+
+	return fnErr
+}
+
+// ioctlValue issues req on the device with an integer argument.
+func (dev *Device) ioctlValue(req uint, arg uintptr) error {
+	return dev.control(func(fd uintptr) error {
+		return ioctl.Value(fd, req, arg)
+	})
+}
+
+// closedErr returns [ErrClosed] in place of the error the file reports
+// when it is used after the device was closed.
+func (dev *Device) closedErr(err error) error {
+	if dev.closed.Load() || errors.Is(err, os.ErrClosed) {
+		return ErrClosed
+	}
+
+	return err
+}
+
+// deviceIoctl issues req on dev with arg, like [ioctl.Any].
+func deviceIoctl[T any](dev *Device, req uint, arg *T) error {
+	return dev.control(func(fd uintptr) error {
+		return ioctl.Any(fd, req, arg)
+	})
+}
+
+func (dev *Device) readEventNonblock() (rawEvent, error) {
+	var (
+		buf []byte
+		raw rawEvent
+		n   int
+		err error
+	)
+
+	buf = make([]byte, binary.Size(raw))
+
+	err = dev.control(func(fd uintptr) error {
+		var readErr error
+
+		n, readErr = unix.Read(int(fd), buf)
+
+		return readErr
+	})
+	if err != nil {
+		return rawEvent{}, err
+	}
+
+	switch n {
+	case 0:
+		return rawEvent{}, io.EOF
+	case len(buf):
+	default:
+		return rawEvent{}, io.ErrUnexpectedEOF
+	}
+
+	_, err = binary.Decode(buf, binary.NativeEndian, &raw)
+	if err != nil {
+		return rawEvent{}, err
+	}
+
+	return raw, nil
+}
+
+// waitEvent calls read until it returns an event or an error other than
+// [unix.EAGAIN], waiting for dev, which must be non-blocking, to become
+// readable in between. The wait goes through the runtime poller, so a
+// read deadline set on the file of dev, or closing dev, interrupts it.
+func waitEvent(dev *Device, read func() (Event, error)) (Event, error) {
+	var (
+		raw     syscall.RawConn
+		ev      Event
+		readErr error
+		err     error
+	)
+
+	raw, err = dev.file.SyscallConn()
+	if err != nil {
+		return Event{}, dev.closedErr(err)
+	}
+
+	err = raw.Read(func(uintptr) bool {
+		ev, readErr = read()
+
+		return !errors.Is(readErr, unix.EAGAIN)
+	})
+	if err != nil {
+		return Event{}, dev.closedErr(err)
+	}
+
+	return ev, readErr
+}
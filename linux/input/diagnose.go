@@ -0,0 +1,329 @@
+//go:build linux
+
+package input
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Diagnosis explains whether, and why not, the calling process can open
+// an evdev device node.
+type Diagnosis struct {
+	// Path is the device node that was inspected.
+	Path string
+
+	// Mode is the file mode of the device node.
+	Mode os.FileMode
+
+	// UID is the user ID owning the device node.
+	UID uint32
+
+	// GID is the group ID owning the device node.
+	GID uint32
+
+	// Group is the name of the group owning the device node, or its
+	// numeric ID if the name cannot be resolved.
+	Group string
+
+	// Readable reports whether the process may open the node for reading.
+	Readable bool
+
+	// Writable reports whether the process may open the node for writing.
+	Writable bool
+
+	// InGroup reports whether the process currently runs with the
+	// group owning the device node.
+	InGroup bool
+
+	// InGroupDatabase reports whether the user is listed as a member of
+	// the group owning the device node in the group database. It can be
+	// true while InGroup is false until the user logs in again.
+	InGroupDatabase bool
+
+	// UnknownUser reports that the user or their groups could not be
+	// looked up, as is common in containers without a passwd entry for
+	// the user. InGroupDatabase is then false.
+	UnknownUser bool
+
+	// UserACL reports whether a POSIX ACL entry, such as the one
+	// systemd-logind adds for uaccess-tagged devices, grants the user
+	// read access to the device node.
+	UserACL bool
+
+	// Hints holds human-readable explanations and suggested fixes.
+	// It is empty if the device node is both readable and writable.
+	Hints []string
+}
+
+// Diagnose inspects the device node at path and reports why the calling
+// process may be unable to open it. It checks the node's ownership and
+// mode, the process's group membership, and udev-assigned ACLs, and
+// suggests fixes for each problem it finds.
+func Diagnose(path string) (*Diagnosis, error) {
+	var (
+		diag *Diagnosis
+		stat unix.Stat_t
+		err  error
+	)
+
+	path = filepath.Clean(path)
+
+	err = unix.Stat(path, &stat)
+	if err != nil {
+		return nil, fmt.Errorf("input.Diagnose: %w", err)
+	}
+
+	diag = &Diagnosis{
+		Path:     path,
+		Mode:     os.FileMode(stat.Mode & 0o777),
+		UID:      stat.Uid,
+		GID:      stat.Gid,
+		Group:    groupName(stat.Gid),
+		Readable: unix.Access(path, unix.R_OK) == nil,
+		Writable: unix.Access(path, unix.W_OK) == nil,
+		InGroup:  inProcessGroups(stat.Gid),
+	}
+
+	diag.InGroupDatabase, err = inGroupDatabase(stat.Gid)
+	diag.UnknownUser = err != nil
+
+	diag.UserACL, err = userACL(path, uint32(os.Geteuid()))
+	if err != nil {
+		return nil, fmt.Errorf("input.Diagnose: %w", err)
+	}
+
+	diag.Hints = diag.hints()
+
+	return diag, nil
+}
+
+// Diagnoses runs [Diagnose] on every event device node in /dev/input,
+// using NewSystem(nil).
+func Diagnoses() ([]*Diagnosis, error) {
+	return NewSystem(nil).Diagnoses()
+}
+
+// Diagnoses runs [Diagnose] on every event device node listed by
+// [System.EventNodes]. The nodes themselves are inspected on the host,
+// since their permissions are enforced by the kernel.
+func (sys *System) Diagnoses() ([]*Diagnosis, error) {
+	var (
+		diags []*Diagnosis
+		diag  *Diagnosis
+		paths []string
+		path  string
+		err   error
+	)
+
+	paths, err = sys.EventNodes()
+	if err != nil {
+		return nil, fmt.Errorf("System.Diagnoses: %w", err)
+	}
+
+	diags = make([]*Diagnosis, 0, len(paths))
+	for _, path = range paths {
+		diag, err = Diagnose(path)
+		if err != nil {
+			return nil, fmt.Errorf("System.Diagnoses: %w", err)
+		}
+
+		diags = append(diags, diag)
+	}
+
+	return diags, nil
+}
+
+// String formats the diagnosis as a short multi-line report.
+func (diag *Diagnosis) String() string {
+	var (
+		builder strings.Builder
+		hint    string
+	)
+
+	fmt.Fprintf(
+		&builder,
+		"%s: mode %#o uid %d group %s readable %t writable %t\n",
+		diag.Path,
+		uint32(diag.Mode),
+		diag.UID,
+		diag.Group,
+		diag.Readable,
+		diag.Writable,
+	)
+
+	for _, hint = range diag.Hints {
+		fmt.Fprintf(&builder, "  - %s\n", hint)
+	}
+
+	return builder.String()
+}
+
+func (diag *Diagnosis) hints() []string {
+	var hints []string
+
+	if diag.Readable && diag.Writable {
+		return nil
+	}
+
+	if diag.Readable {
+		return []string{
+			"the node is readable but not writable; open it read-only " +
+				"unless force feedback or LED control is needed",
+		}
+	}
+
+	if diag.Mode&0o040 == 0 {
+		hints = append(hints, fmt.Sprintf(
+			"the node mode %#o denies group access; add a udev rule such as "+
+				`KERNEL=="event*", SUBSYSTEM=="input", MODE="0660", GROUP="input"`,
+			uint32(diag.Mode),
+		))
+	}
+
+	switch {
+	case diag.InGroup:
+	case diag.GID == 0:
+		hints = append(
+			hints,
+			"the node belongs to group root, which users should not join; "+
+				"add a udev rule giving it a dedicated group, such as "+
+				`KERNEL=="event*", SUBSYSTEM=="input", MODE="0660", GROUP="input", `+
+				`or tag it for seat access with TAG+="uaccess"`,
+		)
+	case diag.InGroupDatabase:
+		hints = append(hints, fmt.Sprintf(
+			"the user is a member of group %s, but the current session "+
+				"predates it; log out and back in",
+			diag.Group,
+		))
+	case diag.UnknownUser:
+		hints = append(hints, fmt.Sprintf(
+			"the groups of the user are unknown; make sure the user is a "+
+				"member of group %s",
+			diag.Group,
+		))
+	default:
+		hints = append(hints, fmt.Sprintf(
+			"the user is not a member of group %s; run "+
+				"'sudo usermod -aG %s $USER' and log in again",
+			diag.Group,
+			diag.Group,
+		))
+	}
+
+	if !diag.UserACL {
+		hints = append(
+			hints,
+			"no udev ACL grants the user access; systemd-logind only adds "+
+				"uaccess ACLs for devices on the seat of the active session",
+		)
+	}
+
+	return hints
+}
+
+func groupName(gid uint32) string {
+	var (
+		group *user.Group
+		id    string
+		err   error
+	)
+
+	id = strconv.FormatUint(uint64(gid), 10)
+
+	group, err = user.LookupGroupId(id)
+	if err != nil {
+		return id
+	}
+
+	return group.Name
+}
+
+func inProcessGroups(gid uint32) bool {
+	var (
+		groups []int
+		err    error
+	)
+
+	if uint32(os.Getegid()) == gid {
+		return true
+	}
+
+	groups, err = unix.Getgroups()
+	if err != nil {
+		return false
+	}
+
+	return slices.Contains(groups, int(gid))
+}
+
+func inGroupDatabase(gid uint32) (bool, error) {
+	var (
+		current *user.User
+		ids     []string
+		err     error
+	)
+
+	current, err = user.Current()
+	if err != nil {
+		return false, err
+	}
+
+	ids, err = current.GroupIds()
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(ids, strconv.FormatUint(uint64(gid), 10)), nil
+}
+
+func userACL(path string, uid uint32) (bool, error) {
+	const (
+		headerSize = 4
+		entrySize  = 8
+		aclUser    = 0x02
+		aclRead    = 0x04
+	)
+
+	var (
+		buf        []byte
+		size       int
+		tag, perm  uint16
+		id         uint32
+		entryStart int
+		err        error
+	)
+
+	buf = make([]byte, 1024)
+
+	size, err = unix.Getxattr(path, "system.posix_acl_access", buf)
+	if errors.Is(err, unix.ENODATA) || errors.Is(err, unix.ENOTSUP) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	for entryStart = headerSize; entryStart+entrySize <= size; entryStart += entrySize {
+		tag = binary.LittleEndian.Uint16(buf[entryStart:])
+		perm = binary.LittleEndian.Uint16(buf[entryStart+2:])
+		id = binary.LittleEndian.Uint32(buf[entryStart+4:])
+
+		if tag == aclUser && id == uid && perm&aclRead != 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
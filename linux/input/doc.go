@@ -3,6 +3,29 @@
 // Package input implements the userspace api [input.h] and event constants
 // in [input-event-codes.h] in the Linux kernel.
 //
+// A [Device] wraps an open event node. Reading its events looks like:
+//
+//	dev, err := input.NewDevice("/dev/input/event3", input.ReadOnly())
+//	if err != nil {
+//		return err
+//	}
+//	defer dev.Close()
+//
+//	for {
+//		ev, err := dev.ReadEvent()
+//		if err != nil {
+//			return err
+//		}
+//
+//		fmt.Println(ev) // EV_KEY KEY_A press @ 1700000000.123456
+//	}
+//
+// [NewDeviceFromFile] accepts any open file, so the same loop can decode
+// events from a pipe or from a descriptor passed by systemd-logind. To
+// pick a device by what it is rather than by its node, use [Find]:
+//
+//	pads, err := input.Find(input.ByCapability(input.EV_KEY, input.BTN_SOUTH))
+//
 // [input.h]: https://github.com/torvalds/linux/blob/master/include/uapi/linux/input.h
 // [input-event-codes.h]: https://github.com/torvalds/linux/blob/master/include/uapi/linux/input-event-codes.h
 package input
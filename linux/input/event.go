@@ -0,0 +1,234 @@
+//go:build linux
+
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/andrieee44/mylib"
+	"golang.org/x/sys/unix"
+)
+
+// newRawEvent converts ev to the layout of the kernel.
+func newRawEvent(ev Event) rawEvent {
+	return rawEvent{
+		Sec:   kernelULong(ev.Sec),
+		Usec:  kernelULong(ev.Usec),
+		Type:  ev.Type,
+		Code:  ev.Code,
+		Value: ev.Value,
+	}
+}
+
+// event converts raw to an [Event].
+func (raw rawEvent) event() Event {
+	return Event{
+		Sec:   uint64(raw.Sec),
+		Usec:  uint64(raw.Usec),
+		Type:  raw.Type,
+		Code:  raw.Code,
+		Value: raw.Value,
+	}
+}
+
+// Time returns the timestamp of ev as a [time.Time]. clock is the clock
+// the device stamps its events with, as returned by [Device.Clock].
+// Timestamps of [unix.CLOCK_REALTIME] are converted directly; those of
+// other clocks, such as [unix.CLOCK_MONOTONIC], are placed on the wall
+// clock by their distance from the current time of clock, and carry a
+// monotonic reading like the result of [time.Now].
+func (ev Event) Time(clock int32) time.Time {
+	if clock == unix.CLOCK_REALTIME {
+		return time.Unix(int64(ev.Sec), int64(ev.Usec)*int64(time.Microsecond))
+	}
+
+	return time.Now().Add(-ev.Since(clock))
+}
+
+// Since returns the time elapsed since ev was stamped, measured on
+// clock, the clock of the device as returned by [Device.Clock].
+func (ev Event) Since(clock int32) time.Duration {
+	var (
+		now unix.Timespec
+		err error
+	)
+
+	err = unix.ClockGettime(clock, &now)
+	if err != nil {
+		return time.Since(ev.Time(unix.CLOCK_REALTIME))
+	}
+
+	return time.Duration(now.Nano()) - eventTime(ev)
+}
+
+// String formats ev as its type and code names followed by its value and
+// timestamp, such as "EV_KEY KEY_A press @ 1700000000.123456". Key values
+// 0, 1, and 2 are shown as release, press, and repeat. Unknown types and
+// codes are shown as numbers.
+func (ev Event) String() string {
+	return fmt.Sprintf(
+		"%s %s %s @ %d.%06d",
+		ev.typeName(),
+		ev.codeName(),
+		ev.valueName(),
+		ev.Sec,
+		ev.Usec,
+	)
+}
+
+// MarshalJSON encodes ev as a JSON object whose type and code fields hold
+// the names of the event type and code, such as
+// {"sec":1700000000,"usec":123456,"type":"EV_KEY","code":"KEY_A","value":1}.
+// Unknown types and codes are encoded as decimal strings.
+func (ev Event) MarshalJSON() ([]byte, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	data, err = json.Marshal(struct {
+		Sec   uint64 `json:"sec"`
+		Usec  uint64 `json:"usec"`
+		Type  string `json:"type"`
+		Code  string `json:"code"`
+		Value int32  `json:"value"`
+	}{
+		Sec:   ev.Sec,
+		Usec:  ev.Usec,
+		Type:  ev.typeName(),
+		Code:  ev.codeName(),
+		Value: ev.Value,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Event.MarshalJSON: %w", err)
+	}
+
+	return data, nil
+}
+
+// UnmarshalJSON decodes an event encoded by [Event.MarshalJSON]. The type
+// and code fields may hold names or decimal strings.
+func (ev *Event) UnmarshalJSON(data []byte) error {
+	var (
+		fields struct {
+			Sec   uint64 `json:"sec"`
+			Usec  uint64 `json:"usec"`
+			Type  string `json:"type"`
+			Code  string `json:"code"`
+			Value int32  `json:"value"`
+		}
+		eventType mylib.InputEvent
+		code      mylib.InputCode
+		err       error
+	)
+
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return fmt.Errorf("Event.UnmarshalJSON: %w", err)
+	}
+
+	eventType, err = parseType(fields.Type)
+	if err != nil {
+		return fmt.Errorf("Event.UnmarshalJSON: %w", err)
+	}
+
+	code, err = parseCode(fields.Code)
+	if err != nil {
+		return fmt.Errorf("Event.UnmarshalJSON: %w", err)
+	}
+
+	*ev = Event{
+		Sec:   fields.Sec,
+		Usec:  fields.Usec,
+		Type:  uint16(eventType),
+		Code:  uint16(code),
+		Value: fields.Value,
+	}
+
+	return nil
+}
+
+func (ev Event) typeName() string {
+	return formatType(mylib.InputEvent(ev.Type))
+}
+
+func (ev Event) codeName() string {
+	return formatCode(mylib.InputEvent(ev.Type), mylib.InputCode(ev.Code))
+}
+
+// formatType returns the name of eventType, or its decimal value if it
+// is unknown, the inverse of parseType.
+func formatType(eventType mylib.InputEvent) string {
+	var name string
+
+	name = TypeName(eventType)
+	if name == "" {
+		return strconv.FormatUint(uint64(eventType), 10)
+	}
+
+	return name
+}
+
+// formatCode returns the name of code, or its decimal value if it is
+// unknown, the inverse of parseCode.
+func formatCode(eventType mylib.InputEvent, code mylib.InputCode) string {
+	var name string
+
+	name = CodeName(eventType, code)
+	if name == "" {
+		return strconv.FormatUint(uint64(code), 10)
+	}
+
+	return name
+}
+
+func (ev Event) valueName() string {
+	if ev.Type != EV_KEY {
+		return strconv.FormatInt(int64(ev.Value), 10)
+	}
+
+	switch ev.Value {
+	case 0:
+		return "release"
+	case 1:
+		return "press"
+	case 2:
+		return "repeat"
+	default:
+		return strconv.FormatInt(int64(ev.Value), 10)
+	}
+}
+
+func parseType(name string) (mylib.InputEvent, error) {
+	var (
+		value uint64
+		err   error
+	)
+
+	value, err = strconv.ParseUint(name, 10, 16)
+	if err == nil {
+		return mylib.InputEvent(value), nil
+	}
+
+	return TypeByName(name)
+}
+
+func parseCode(name string) (mylib.InputCode, error) {
+	var (
+		value uint64
+		code  mylib.InputCode
+		err   error
+	)
+
+	value, err = strconv.ParseUint(name, 10, 16)
+	if err == nil {
+		return mylib.InputCode(value), nil
+	}
+
+	_, code, err = CodeByName(name)
+
+	return code, err
+}
@@ -0,0 +1,122 @@
+//go:build linux
+
+package input_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"testing/fstest"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+func ExampleDevice_ReadEvent() {
+	var (
+		reader, writer *os.File
+		feed, dev      *input.Device
+		ev             input.Event
+		err            error
+	)
+
+	// A pipe fed with events stands in for an event device node.
+	reader, writer, err = os.Pipe()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	feed = input.NewDeviceFromFile(writer)
+
+	for _, ev = range []input.Event{
+		{Type: input.EV_KEY, Code: input.KEY_A, Value: 1},
+		{Type: input.EV_SYN, Code: input.SYN_REPORT},
+	} {
+		err = feed.WriteEvent(ev)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = feed.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	dev = input.NewDeviceFromFile(reader)
+	defer dev.Close()
+
+	for {
+		ev, err = dev.ReadEvent()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Println(ev)
+	}
+	// Output:
+	// EV_KEY KEY_A press @ 0.000000
+	// EV_SYN SYN_REPORT 0 @ 0.000000
+}
+
+func ExampleCodeByName() {
+	var (
+		eventType mylib.InputEvent
+		code      mylib.InputCode
+		err       error
+	)
+
+	// BTN_A is an alias of BTN_SOUTH, the name CodeName prefers.
+	eventType, code, err = input.CodeByName("BTN_A")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(input.TypeName(eventType), code, input.CodeName(eventType, code))
+	// Output:
+	// EV_KEY 304 BTN_SOUTH
+}
+
+func ExampleSystem_SysInfo() {
+	var (
+		sys  *input.System
+		info *input.DeviceInfo
+		code mylib.InputCode
+		err  error
+	)
+
+	// An in-memory tree stands in for the host's /sys.
+	sys = input.NewSystem(fstest.MapFS{
+		"sys/class/input/event3/device/name":             {Data: []byte("Test Keyboard\n")},
+		"sys/class/input/event3/device/phys":             {Data: []byte("usb-0000:00:14.0-2/input0\n")},
+		"sys/class/input/event3/device/uniq":             {Data: []byte("\n")},
+		"sys/class/input/event3/device/modalias":         {Data: []byte("input:b0003v046DpC31Ce0111-e0,1,k1E\n")},
+		"sys/class/input/event3/device/id/bustype":       {Data: []byte("0003\n")},
+		"sys/class/input/event3/device/id/vendor":        {Data: []byte("046d\n")},
+		"sys/class/input/event3/device/id/product":       {Data: []byte("c31c\n")},
+		"sys/class/input/event3/device/id/version":       {Data: []byte("0111\n")},
+		"sys/class/input/event3/device/properties":       {Data: []byte("0\n")},
+		"sys/class/input/event3/device/capabilities/ev":  {Data: []byte("3\n")},
+		"sys/class/input/event3/device/capabilities/key": {Data: []byte("40000000\n")},
+	})
+
+	info, err = sys.SysInfo("/dev/input/event3")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%s %04x:%04x\n", info.Name, info.ID.Vendor, info.ID.Product)
+
+	for _, code = range info.Codes[input.EV_KEY] {
+		fmt.Println(input.CodeName(input.EV_KEY, code))
+	}
+	// Output:
+	// Test Keyboard 046d:c31c
+	// KEY_A
+}
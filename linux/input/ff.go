@@ -0,0 +1,332 @@
+//go:build linux
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"time"
+	"unsafe"
+)
+
+// ErrEffectMismatch is returned when the effect-specific data does not
+// match the type of a force-feedback effect.
+var ErrEffectMismatch error = errors.New("effect data does not match effect type")
+
+// ErrInvalidDuration is returned by [Device.Rumble] for durations that
+// are not positive.
+var ErrInvalidDuration error = errors.New("invalid duration")
+
+// FFEffectData is the effect-specific part of a force-feedback effect,
+// stored in the [FFEffect] U union. It is implemented by
+// [FFRumbleEffect], [FFConstantEffect], [FFPeriodicEffect],
+// [FFConditionEffect], and [FFRampEffect].
+type FFEffectData interface {
+	ffTypes() []uint16
+	putUnion(union *[ffUnionSize]byte)
+}
+
+var (
+	_ FFEffectData = FFRumbleEffect{}
+	_ FFEffectData = FFConstantEffect{}
+	_ FFEffectData = FFPeriodicEffect{}
+	_ FFEffectData = FFConditionEffect{}
+	_ FFEffectData = FFRampEffect{}
+)
+
+func (FFRumbleEffect) ffTypes() []uint16 { return []uint16{FF_RUMBLE} }
+
+func (FFConstantEffect) ffTypes() []uint16 { return []uint16{FF_CONSTANT} }
+
+func (FFPeriodicEffect) ffTypes() []uint16 { return []uint16{FF_PERIODIC} }
+
+func (FFRampEffect) ffTypes() []uint16 { return []uint16{FF_RAMP} }
+
+func (FFConditionEffect) ffTypes() []uint16 {
+	return []uint16{FF_SPRING, FF_FRICTION, FF_DAMPER, FF_INERTIA}
+}
+
+func (effect FFRumbleEffect) putUnion(union *[ffUnionSize]byte) {
+	putUnion(union, effect)
+}
+
+func (effect FFConstantEffect) putUnion(union *[ffUnionSize]byte) {
+	putUnion(union, effect)
+}
+
+func (effect FFPeriodicEffect) putUnion(union *[ffUnionSize]byte) {
+	putUnion(union, effect)
+}
+
+func (effect FFRampEffect) putUnion(union *[ffUnionSize]byte) {
+	putUnion(union, effect)
+}
+
+// putUnion writes the condition to both axes of the ff_condition_effect
+// pair in the union.
+func (effect FFConditionEffect) putUnion(union *[ffUnionSize]byte) {
+	putUnion(union, [2]FFConditionEffect{effect, effect})
+}
+
+func putUnion[T any](union *[ffUnionSize]byte, value T) {
+	*union = [ffUnionSize]byte{}
+	copy(union[:], unsafe.Slice((*byte)(unsafe.Pointer(&value)), unsafe.Sizeof(value)))
+}
+
+// getUnion decodes the union as a value of type T.
+func getUnion[T any](union *[ffUnionSize]byte) T {
+	var value T
+
+	copy(unsafe.Slice((*byte)(unsafe.Pointer(&value)), unsafe.Sizeof(value)), union[:])
+
+	return value
+}
+
+// SetRumble sets the type of the effect to [FF_RUMBLE] and stores rumble
+// in its union.
+func (effect *FFEffect) SetRumble(rumble FFRumbleEffect) {
+	effect.Type = FF_RUMBLE
+	rumble.putUnion(&effect.U)
+}
+
+// SetConstant sets the type of the effect to [FF_CONSTANT] and stores
+// constant in its union.
+func (effect *FFEffect) SetConstant(constant FFConstantEffect) {
+	effect.Type = FF_CONSTANT
+	constant.putUnion(&effect.U)
+}
+
+// SetPeriodic sets the type of the effect to [FF_PERIODIC] and stores
+// periodic in its union. The union holds CustomData as a plain address,
+// which does not keep the samples alive, so they must be kept reachable
+// until the effect has been uploaded.
+func (effect *FFEffect) SetPeriodic(periodic FFPeriodicEffect) {
+	effect.Type = FF_PERIODIC
+	periodic.putUnion(&effect.U)
+}
+
+// SetRamp sets the type of the effect to [FF_RAMP] and stores ramp in its
+// union.
+func (effect *FFEffect) SetRamp(ramp FFRampEffect) {
+	effect.Type = FF_RAMP
+	ramp.putUnion(&effect.U)
+}
+
+// SetCondition sets the type of the effect to effectType, one of
+// [FF_SPRING], [FF_FRICTION], [FF_DAMPER], or [FF_INERTIA], and stores
+// the conditions of the horizontal and vertical axes in its union. It
+// returns [ErrEffectMismatch] if effectType is not a condition type.
+func (effect *FFEffect) SetCondition(effectType uint16, conditions [2]FFConditionEffect) error {
+	if !slices.Contains(FFConditionEffect{}.ffTypes(), effectType) {
+		return fmt.Errorf("FFEffect.SetCondition: %w: type %#x", ErrEffectMismatch, effectType)
+	}
+
+	effect.Type = effectType
+	putUnion(&effect.U, conditions)
+
+	return nil
+}
+
+// Rumble decodes the union of an [FF_RUMBLE] effect. It returns
+// [ErrEffectMismatch] if the effect has another type.
+func (effect *FFEffect) Rumble() (FFRumbleEffect, error) {
+	if effect.Type != FF_RUMBLE {
+		return FFRumbleEffect{}, fmt.Errorf("FFEffect.Rumble: %w: type %#x", ErrEffectMismatch, effect.Type)
+	}
+
+	return getUnion[FFRumbleEffect](&effect.U), nil
+}
+
+// Constant decodes the union of an [FF_CONSTANT] effect. It returns
+// [ErrEffectMismatch] if the effect has another type.
+func (effect *FFEffect) Constant() (FFConstantEffect, error) {
+	if effect.Type != FF_CONSTANT {
+		return FFConstantEffect{}, fmt.Errorf("FFEffect.Constant: %w: type %#x", ErrEffectMismatch, effect.Type)
+	}
+
+	return getUnion[FFConstantEffect](&effect.U), nil
+}
+
+// Periodic decodes the union of an [FF_PERIODIC] effect. It returns
+// [ErrEffectMismatch] if the effect has another type.
+func (effect *FFEffect) Periodic() (FFPeriodicEffect, error) {
+	if effect.Type != FF_PERIODIC {
+		return FFPeriodicEffect{}, fmt.Errorf("FFEffect.Periodic: %w: type %#x", ErrEffectMismatch, effect.Type)
+	}
+
+	return getUnion[FFPeriodicEffect](&effect.U), nil
+}
+
+// Ramp decodes the union of an [FF_RAMP] effect. It returns
+// [ErrEffectMismatch] if the effect has another type.
+func (effect *FFEffect) Ramp() (FFRampEffect, error) {
+	if effect.Type != FF_RAMP {
+		return FFRampEffect{}, fmt.Errorf("FFEffect.Ramp: %w: type %#x", ErrEffectMismatch, effect.Type)
+	}
+
+	return getUnion[FFRampEffect](&effect.U), nil
+}
+
+// Condition decodes the horizontal and vertical conditions in the union
+// of an [FF_SPRING], [FF_FRICTION], [FF_DAMPER], or [FF_INERTIA] effect.
+// It returns [ErrEffectMismatch] if the effect has another type.
+func (effect *FFEffect) Condition() ([2]FFConditionEffect, error) {
+	if !slices.Contains(FFConditionEffect{}.ffTypes(), effect.Type) {
+		return [2]FFConditionEffect{}, fmt.Errorf("FFEffect.Condition: %w: type %#x", ErrEffectMismatch, effect.Type)
+	}
+
+	return getUnion[[2]FFConditionEffect](&effect.U), nil
+}
+
+// EffectCapacity returns how many force-feedback effects the device can
+// hold at once. It issues the [EVIOCGEFFECTS] ioctl.
+func (dev *Device) EffectCapacity() (int, error) {
+	var (
+		capacity int32
+		err      error
+	)
+
+	err = deviceIoctl(dev, EVIOCGEFFECTS(), &capacity)
+	if err != nil {
+		return 0, fmt.Errorf("Device.EffectCapacity: %w", err)
+	}
+
+	return int(capacity), nil
+}
+
+// UploadEffect encodes data into the union of effect and uploads the
+// effect to the device with the [EVIOCSFF] ioctl, returning the effect
+// ID assigned by the kernel.
+//
+// Set effect.Id to -1 to upload a new effect, or to the ID of an
+// uploaded effect to update it. If effect.Type is zero, it is inferred
+// from data; condition effects need an explicit [FF_SPRING],
+// [FF_FRICTION], [FF_DAMPER], or [FF_INERTIA] type. A condition is
+// applied to both axes.
+func (dev *Device) UploadEffect(effect FFEffect, data FFEffectData) (int16, error) {
+	var (
+		types []uint16
+		err   error
+	)
+
+	types = data.ffTypes()
+
+	if effect.Type == 0 && len(types) == 1 {
+		effect.Type = types[0]
+	}
+
+	if !slices.Contains(types, effect.Type) {
+		return 0, fmt.Errorf(
+			"Device.UploadEffect: %w: type %#x",
+			ErrEffectMismatch,
+			effect.Type,
+		)
+	}
+
+	data.putUnion(&effect.U)
+
+	err = deviceIoctl(dev, EVIOCSFF(), &effect)
+	runtime.KeepAlive(data)
+
+	if err != nil {
+		return 0, fmt.Errorf("Device.UploadEffect: %w", err)
+	}
+
+	return effect.Id, nil
+}
+
+// EraseEffect removes an uploaded effect from the device with the
+// [EVIOCRMFF] ioctl, freeing its slot.
+func (dev *Device) EraseEffect(id int16) error {
+	var err error
+
+	err = dev.ioctlValue(EVIOCRMFF(), uintptr(id))
+	if err != nil {
+		return fmt.Errorf("Device.EraseEffect: %w", err)
+	}
+
+	return nil
+}
+
+// PlayEffect starts playing an uploaded effect by writing an [EV_FF]
+// event. The effect is repeated count times.
+func (dev *Device) PlayEffect(id int16, count int32) error {
+	var err error
+
+	err = dev.WriteEvent(Event{Type: EV_FF, Code: uint16(id), Value: count})
+	if err != nil {
+		return fmt.Errorf("Device.PlayEffect: %w", err)
+	}
+
+	return nil
+}
+
+// StopEffect stops playing an uploaded effect by writing an [EV_FF]
+// event with a zero value. The effect stays uploaded.
+func (dev *Device) StopEffect(id int16) error {
+	var err error
+
+	err = dev.WriteEvent(Event{Type: EV_FF, Code: uint16(id), Value: 0})
+	if err != nil {
+		return fmt.Errorf("Device.StopEffect: %w", err)
+	}
+
+	return nil
+}
+
+// Rumble vibrates a gamepad's heavy and light motors at the given
+// magnitudes for duration, which must be positive and is clamped to the
+// 65535 ms limit of [FFReplay]. It plays an [FF_RUMBLE] effect once and
+// returns immediately.
+//
+// The first call uploads the effect and later calls update and restart
+// it, so overlapping calls replace each other instead of filling the
+// device's effect slots. The effect is erased when the device is closed.
+func (dev *Device) Rumble(strong, weak uint16, duration time.Duration) error {
+	var (
+		effect FFEffect
+		id     int16
+		err    error
+	)
+
+	if duration <= 0 {
+		return fmt.Errorf("Device.Rumble: %w %s", ErrInvalidDuration, duration)
+	}
+
+	dev.rumbleMutex.Lock()
+	defer dev.rumbleMutex.Unlock()
+
+	effect = FFEffect{
+		Id:     -1,
+		Replay: FFReplay{Length: ffMilliseconds(duration)},
+	}
+
+	if dev.hasRumble {
+		effect.Id = dev.rumble
+	}
+
+	id, err = dev.UploadEffect(effect, FFRumbleEffect{StrongMagnitude: strong, WeakMagnitude: weak})
+	if err != nil && dev.hasRumble {
+		// The effect may have been erased with EraseEffect.
+		effect.Id = -1
+		id, err = dev.UploadEffect(effect, FFRumbleEffect{StrongMagnitude: strong, WeakMagnitude: weak})
+	}
+
+	if err != nil {
+		dev.hasRumble = false
+
+		return fmt.Errorf("Device.Rumble: %w", err)
+	}
+
+	dev.rumble = id
+	dev.hasRumble = true
+
+	err = dev.PlayEffect(id, 1)
+	if err != nil {
+		return fmt.Errorf("Device.Rumble: %w", err)
+	}
+
+	return nil
+}
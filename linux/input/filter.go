@@ -0,0 +1,115 @@
+//go:build linux
+
+package input
+
+import (
+	"slices"
+	"time"
+)
+
+// Filter transforms input events one frame at a time. A frame is the
+// sequence of events up to and including an [EV_SYN] [SYN_REPORT] event.
+// Filters may drop, alter, or insert events, and may keep state across
+// frames. The returned slice may share storage with frame.
+type Filter interface {
+	Filter(frame []Event) []Event
+}
+
+// Flusher is implemented by filters that hold events back until later
+// events show that they are final, such as [Debounce]. When no events
+// arrive, a reader calls Flush once the delay returned by FlushAfter has
+// passed since the last frame, so that held-back events are not delayed
+// indefinitely.
+type Flusher interface {
+	// FlushAfter returns how long after the last frame Flush should be
+	// called, or false if no events are held back.
+	FlushAfter() (time.Duration, bool)
+
+	// Flush returns the events held back for at least the delay
+	// returned by FlushAfter, as whole frames.
+	Flush() []Event
+}
+
+// Pipeline is a [Filter] that runs each of its filters in order, feeding
+// the output of one into the next.
+type Pipeline []Filter
+
+var (
+	_ Filter  = Pipeline(nil)
+	_ Flusher = Pipeline(nil)
+)
+
+// Filter runs frame through every filter of the pipeline.
+func (pipeline Pipeline) Filter(frame []Event) []Event {
+	var filter Filter
+
+	for _, filter = range pipeline {
+		frame = filter.Filter(frame)
+	}
+
+	return frame
+}
+
+// FlushAfter implements [Flusher], returning the shortest delay of the
+// filters of the pipeline that hold events back.
+func (pipeline Pipeline) FlushAfter() (time.Duration, bool) {
+	var (
+		filter  Filter
+		flusher Flusher
+		delay   time.Duration
+		after   time.Duration
+		ok      bool
+		found   bool
+	)
+
+	for _, filter = range pipeline {
+		flusher, ok = filter.(Flusher)
+		if !ok {
+			continue
+		}
+
+		delay, ok = flusher.FlushAfter()
+		if ok && (!found || delay < after) {
+			after = delay
+			found = true
+		}
+	}
+
+	return after, found
+}
+
+// Flush implements [Flusher]. It flushes the filters whose delay has
+// passed and runs the flushed events through the filters after them.
+func (pipeline Pipeline) Flush() []Event {
+	var (
+		after   time.Duration
+		filter  Filter
+		flusher Flusher
+		delay   time.Duration
+		events  []Event
+		ok      bool
+	)
+
+	after, ok = pipeline.FlushAfter()
+	if !ok {
+		return nil
+	}
+
+	for _, filter = range pipeline {
+		if len(events) != 0 {
+			events = filter.Filter(events)
+		}
+
+		flusher, ok = filter.(Flusher)
+		if !ok {
+			continue
+		}
+
+		delay, ok = flusher.FlushAfter()
+		if ok && delay <= after {
+			events = append(slices.Clip(events), flusher.Flush()...)
+		}
+	}
+
+	return events
+}
@@ -0,0 +1,290 @@
+//go:build linux
+
+package input_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+type filterTest struct {
+	name   string
+	frames [][]input.Event
+	flush  bool
+	want   []input.Event
+}
+
+// at returns an event stamped ms milliseconds after the epoch.
+func at(ms int, eventType, code uint16, value int32) input.Event {
+	return input.Event{
+		Sec:   uint64(ms / 1000),
+		Usec:  uint64(ms % 1000 * 1000),
+		Type:  eventType,
+		Code:  code,
+		Value: value,
+	}
+}
+
+// key returns an [input.EV_KEY] event stamped ms milliseconds after the
+// epoch.
+func key(ms int, code uint16, value int32) input.Event {
+	return at(ms, input.EV_KEY, code, value)
+}
+
+// abs returns an [input.EV_ABS] event stamped ms milliseconds after the
+// epoch.
+func abs(ms int, code uint16, value int32) input.Event {
+	return at(ms, input.EV_ABS, code, value)
+}
+
+// syn returns a [input.SYN_REPORT] stamped ms milliseconds after the
+// epoch.
+func syn(ms int) input.Event {
+	return at(ms, input.EV_SYN, input.SYN_REPORT, 0)
+}
+
+// frame returns events followed by a [input.SYN_REPORT] stamped like
+// the last of them.
+func frame(events ...input.Event) []input.Event {
+	var last input.Event
+
+	last = events[len(events)-1]
+
+	return append(events, input.Event{
+		Sec:  last.Sec,
+		Usec: last.Usec,
+		Type: input.EV_SYN,
+		Code: input.SYN_REPORT,
+	})
+}
+
+// filterFrames runs each frame through filter, then flushes it if flush
+// is set, and returns everything the filter emitted.
+func filterFrames(filter input.Filter, frames [][]input.Event, flush bool) []input.Event {
+	var (
+		out     []input.Event
+		events  []input.Event
+		flusher input.Flusher
+		ok      bool
+	)
+
+	for _, events = range frames {
+		out = append(out, filter.Filter(slices.Clone(events))...)
+	}
+
+	flusher, ok = filter.(input.Flusher)
+	if flush && ok {
+		out = append(out, flusher.Flush()...)
+	}
+
+	return out
+}
+
+// checkEvents reports the difference between got and want.
+func checkEvents(t *testing.T, got, want []input.Event) {
+	t.Helper()
+
+	if !slices.Equal(got, want) {
+		t.Errorf("got  %v\nwant %v", got, want)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	var (
+		tests []filterTest
+		test  filterTest
+	)
+
+	tests = []filterTest{
+		{
+			name: "bounce dropped",
+			frames: [][]input.Event{
+				frame(key(0, input.BTN_LEFT, 1)),
+				frame(key(10, input.BTN_LEFT, 0)),
+				frame(key(15, input.BTN_LEFT, 1)),
+			},
+			want: []input.Event{
+				key(0, input.BTN_LEFT, 1), syn(0),
+				syn(10),
+				syn(15),
+			},
+		},
+		{
+			name: "release after window",
+			frames: [][]input.Event{
+				frame(key(0, input.BTN_LEFT, 1)),
+				frame(key(10, input.BTN_LEFT, 0)),
+				frame(key(40, input.KEY_A, 1)),
+			},
+			want: []input.Event{
+				key(0, input.BTN_LEFT, 1), syn(0),
+				syn(10),
+				key(10, input.BTN_LEFT, 0), syn(10),
+				key(40, input.KEY_A, 1), syn(40),
+			},
+		},
+		{
+			name: "press after window",
+			frames: [][]input.Event{
+				frame(key(10, input.BTN_LEFT, 0)),
+				frame(key(30, input.BTN_LEFT, 1)),
+			},
+			want: []input.Event{
+				syn(10),
+				key(10, input.BTN_LEFT, 0), syn(10),
+				key(30, input.BTN_LEFT, 1), syn(30),
+			},
+		},
+		{
+			name: "flushed on timeout",
+			frames: [][]input.Event{
+				frame(key(10, input.BTN_LEFT, 0)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(10),
+				key(10, input.BTN_LEFT, 0), syn(10),
+			},
+		},
+		{
+			name: "switch",
+			frames: [][]input.Event{
+				frame(at(0, input.EV_SW, input.SW_LID, 0)),
+				frame(at(5, input.EV_SW, input.SW_LID, 1)),
+			},
+			want: []input.Event{syn(0), syn(5)},
+		},
+		{
+			name: "other codes pass",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_A, 0)),
+				frame(key(1, input.KEY_A, 1)),
+			},
+			want: []input.Event{
+				key(0, input.KEY_A, 0), syn(0),
+				key(1, input.KEY_A, 1), syn(1),
+			},
+		},
+		{
+			name: "earlier timestamp",
+			frames: [][]input.Event{
+				frame(key(100, input.BTN_LEFT, 0)),
+				frame(key(50, input.KEY_A, 1)),
+				frame(key(105, input.BTN_LEFT, 1)),
+			},
+			want: []input.Event{
+				syn(100),
+				key(50, input.KEY_A, 1), syn(50),
+				syn(105),
+			},
+		},
+		{
+			name: "SYN_DROPPED passes",
+			frames: [][]input.Event{
+				frame(key(0, input.BTN_LEFT, 0)),
+				{at(5, input.EV_SYN, input.SYN_DROPPED, 0)},
+				frame(key(10, input.BTN_LEFT, 1)),
+			},
+			want: []input.Event{
+				syn(0),
+				at(5, input.EV_SYN, input.SYN_DROPPED, 0),
+				syn(10),
+			},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkEvents(t, filterFrames(&input.Debounce{
+				Window:   20 * time.Millisecond,
+				Keys:     []mylib.InputCode{input.BTN_LEFT},
+				Switches: []mylib.InputCode{input.SW_LID},
+			}, test.frames, test.flush), test.want)
+		})
+	}
+}
+
+func TestDebounceFlushAfter(t *testing.T) {
+	var (
+		filter *input.Debounce
+		after  time.Duration
+		ok     bool
+	)
+
+	filter = &input.Debounce{Window: 20 * time.Millisecond, Keys: []mylib.InputCode{input.BTN_LEFT}}
+
+	_, ok = filter.FlushAfter()
+	if ok {
+		t.Error("FlushAfter reported held-back events before any")
+	}
+
+	filter.Filter(frame(key(0, input.BTN_LEFT, 0)))
+
+	after, ok = filter.FlushAfter()
+	if !ok || after != 20*time.Millisecond {
+		t.Errorf("FlushAfter = %v, %t, want 20ms, true", after, ok)
+	}
+
+	filter.Flush()
+
+	_, ok = filter.FlushAfter()
+	if ok {
+		t.Error("FlushAfter reported held-back events after Flush")
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	var (
+		tests []filterTest
+		test  filterTest
+	)
+
+	tests = []filterTest{
+		{
+			name: "filters in order",
+			frames: [][]input.Event{
+				frame(key(0, input.BTN_LEFT, 1)),
+				frame(key(10, input.BTN_LEFT, 0)),
+				frame(key(15, input.BTN_LEFT, 1)),
+			},
+			want: []input.Event{
+				key(0, input.BTN_RIGHT, 1), syn(0),
+				syn(10),
+				syn(15),
+			},
+		},
+		{
+			name: "flushed events run through later filters",
+			frames: [][]input.Event{
+				frame(key(10, input.BTN_LEFT, 0)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(10),
+				key(10, input.BTN_RIGHT, 0), syn(10),
+			},
+		},
+		{
+			name:  "nothing to flush",
+			flush: true,
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkEvents(t, filterFrames(input.Pipeline{
+				&input.Debounce{
+					Window: 20 * time.Millisecond,
+					Keys:   []mylib.InputCode{input.BTN_LEFT},
+				},
+				input.Mapping{
+					Keys: map[mylib.InputCode]mylib.InputCode{input.BTN_LEFT: input.BTN_RIGHT},
+				}.Filter(),
+			}, test.frames, test.flush), test.want)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+//go:build linux
+
+package input
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/andrieee44/mylib"
+)
+
+// Predicate reports whether a device, described by its sysfs metadata,
+// should be selected by [Find].
+type Predicate func(info *DeviceInfo) bool
+
+// Find opens every event device in /dev/input whose sysfs metadata, as
+// read by [SysInfo], satisfies all of the predicates. Devices that do not
+// match are never opened, so Find works without permission to open them.
+// Use [System.Match] to select devices without opening any.
+func Find(predicates ...Predicate) ([]*Device, error) {
+	var (
+		devices []*Device
+		device  *Device
+		paths   []string
+		path    string
+		err     error
+	)
+
+	paths, err = NewSystem(nil).Match(predicates...)
+	if err != nil {
+		return nil, fmt.Errorf("input.Find: %w", err)
+	}
+
+	for _, path = range paths {
+		device, err = NewDevice(path)
+		if err != nil {
+			closeDevices(devices)
+
+			return nil, fmt.Errorf("input.Find: %w", err)
+		}
+
+		devices = append(devices, device)
+	}
+
+	return devices, nil
+}
+
+// ByName returns a [Predicate] matching devices whose name matches re.
+func ByName(re *regexp.Regexp) Predicate {
+	return func(info *DeviceInfo) bool {
+		return re.MatchString(info.Name)
+	}
+}
+
+// ByVendorProduct returns a [Predicate] matching devices with the given
+// vendor and product IDs.
+func ByVendorProduct(vendor, product uint16) Predicate {
+	return func(info *DeviceInfo) bool {
+		return info.ID.Vendor == vendor && info.ID.Product == product
+	}
+}
+
+// ByCapability returns a [Predicate] matching devices that support code
+// within eventType, for example ByCapability(EV_KEY, KEY_A) for
+// keyboards.
+func ByCapability(eventType mylib.InputEvent, code mylib.InputCode) Predicate {
+	return func(info *DeviceInfo) bool {
+		return slices.Contains(info.Codes[eventType], code)
+	}
+}
+
+func closeDevices(devices []*Device) {
+	var device *Device
+
+	for _, device = range devices {
+		_ = device.Close()
+	}
+}
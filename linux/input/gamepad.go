@@ -0,0 +1,670 @@
+//go:build linux
+
+package input
+
+import (
+	"bufio"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/andrieee44/mylib"
+)
+
+// ErrInvalidMapping is returned when a gamepad mapping string is
+// malformed.
+var ErrInvalidMapping error = errors.New("invalid gamepad mapping")
+
+// GamepadButton is a button of the standard gamepad layout, named after
+// its position on an Xbox-style controller.
+type GamepadButton int
+
+const (
+	// GamepadA is the bottom face button.
+	GamepadA GamepadButton = iota
+
+	// GamepadB is the right face button.
+	GamepadB
+
+	// GamepadX is the left face button.
+	GamepadX
+
+	// GamepadY is the top face button.
+	GamepadY
+
+	// GamepadBack is the left center button, such as Back, Select, or
+	// Share.
+	GamepadBack
+
+	// GamepadGuide is the center button, such as the Xbox or PS button.
+	GamepadGuide
+
+	// GamepadStart is the right center button, such as Start or Options.
+	GamepadStart
+
+	// GamepadLeftStick is the click of the left stick.
+	GamepadLeftStick
+
+	// GamepadRightStick is the click of the right stick.
+	GamepadRightStick
+
+	// GamepadLeftShoulder is the left bumper.
+	GamepadLeftShoulder
+
+	// GamepadRightShoulder is the right bumper.
+	GamepadRightShoulder
+
+	// GamepadDpadUp is the up direction of the directional pad.
+	GamepadDpadUp
+
+	// GamepadDpadDown is the down direction of the directional pad.
+	GamepadDpadDown
+
+	// GamepadDpadLeft is the left direction of the directional pad.
+	GamepadDpadLeft
+
+	// GamepadDpadRight is the right direction of the directional pad.
+	GamepadDpadRight
+
+	// GamepadMisc1 is an extra button, such as the Xbox Series share
+	// button or the PS5 microphone button.
+	GamepadMisc1
+
+	// GamepadPaddle1 is the upper right back paddle.
+	GamepadPaddle1
+
+	// GamepadPaddle2 is the upper left back paddle.
+	GamepadPaddle2
+
+	// GamepadPaddle3 is the lower right back paddle.
+	GamepadPaddle3
+
+	// GamepadPaddle4 is the lower left back paddle.
+	GamepadPaddle4
+
+	// GamepadTouchpad is the click of a touchpad, such as that of a PS4
+	// or PS5 controller.
+	GamepadTouchpad
+)
+
+// GamepadAxis is an axis of the standard gamepad layout.
+type GamepadAxis int
+
+const (
+	// GamepadLeftX is the horizontal axis of the left stick, negative to
+	// the left.
+	GamepadLeftX GamepadAxis = iota
+
+	// GamepadLeftY is the vertical axis of the left stick, negative up.
+	GamepadLeftY
+
+	// GamepadRightX is the horizontal axis of the right stick, negative
+	// to the left.
+	GamepadRightX
+
+	// GamepadRightY is the vertical axis of the right stick, negative up.
+	GamepadRightY
+
+	// GamepadLeftTrigger is the left trigger, from 0 when released to 1
+	// when fully pressed.
+	GamepadLeftTrigger
+
+	// GamepadRightTrigger is the right trigger, from 0 when released to 1
+	// when fully pressed.
+	GamepadRightTrigger
+)
+
+var gamepadButtonNames map[string]GamepadButton = map[string]GamepadButton{
+	"a":             GamepadA,
+	"b":             GamepadB,
+	"x":             GamepadX,
+	"y":             GamepadY,
+	"back":          GamepadBack,
+	"guide":         GamepadGuide,
+	"start":         GamepadStart,
+	"leftstick":     GamepadLeftStick,
+	"rightstick":    GamepadRightStick,
+	"leftshoulder":  GamepadLeftShoulder,
+	"rightshoulder": GamepadRightShoulder,
+	"dpup":          GamepadDpadUp,
+	"dpdown":        GamepadDpadDown,
+	"dpleft":        GamepadDpadLeft,
+	"dpright":       GamepadDpadRight,
+	"misc1":         GamepadMisc1,
+	"paddle1":       GamepadPaddle1,
+	"paddle2":       GamepadPaddle2,
+	"paddle3":       GamepadPaddle3,
+	"paddle4":       GamepadPaddle4,
+	"touchpad":      GamepadTouchpad,
+}
+
+var gamepadAxisNames map[string]GamepadAxis = map[string]GamepadAxis{
+	"leftx":        GamepadLeftX,
+	"lefty":        GamepadLeftY,
+	"rightx":       GamepadRightX,
+	"righty":       GamepadRightY,
+	"lefttrigger":  GamepadLeftTrigger,
+	"righttrigger": GamepadRightTrigger,
+}
+
+// GamepadMapping maps the buttons, axes, and hats of a controller, as
+// SDL numbers them, to the standard gamepad layout. It is parsed from an
+// SDL_GameControllerDB mapping string, such as
+//
+//	030000005e0400008e02000014010000,Xbox 360 Controller,a:b0,b:b1,x:b2,y:b3,leftx:a0,lefty:a1,dpup:h0.1,...
+type GamepadMapping struct {
+	// GUID is the SDL joystick GUID the mapping applies to.
+	GUID string
+
+	// Name is the name of the controller.
+	Name string
+
+	// Platform is the platform the mapping is for, such as "Linux", or
+	// empty if it does not say.
+	Platform string
+
+	buttons map[GamepadButton][]gamepadBinding
+	axes    map[GamepadAxis][]gamepadBinding
+}
+
+// gamepadBinding is one source element of a mapping.
+type gamepadBinding struct {
+	// kind is 'b' for a button, 'a' for an axis, or 'h' for a hat.
+	kind byte
+
+	// index is the SDL index of the button, axis, or hat.
+	index int
+
+	// hatMask is the direction of the hat: 1 up, 2 right, 4 down, and 8
+	// left.
+	hatMask int
+
+	// inputSign restricts an axis to its positive or negative half when
+	// 1 or -1.
+	inputSign int
+
+	// invert flips the axis.
+	invert bool
+
+	// outputSign maps the binding to the positive or negative half of an
+	// output axis when 1 or -1.
+	outputSign int
+}
+
+// GamepadDB is a set of gamepad mappings indexed by GUID, such as the
+// community-maintained gamecontrollerdb.txt.
+type GamepadDB struct {
+	mappings map[string]*GamepadMapping
+}
+
+// GamepadGUID returns the SDL joystick GUID of a device with id, as used
+// to look up its mapping.
+func GamepadGUID(id ID) string {
+	return fmt.Sprintf(
+		"%02x%02x0000%02x%02x0000%02x%02x0000%02x%02x0000",
+		id.Bustype&0xff, id.Bustype>>8,
+		id.Vendor&0xff, id.Vendor>>8,
+		id.Product&0xff, id.Product>>8,
+		id.Version&0xff, id.Version>>8,
+	)
+}
+
+// ParseGamepadMapping parses an SDL_GameControllerDB mapping string.
+// Unknown elements and hints are ignored, as SDL ignores them.
+func ParseGamepadMapping(line string) (*GamepadMapping, error) {
+	var (
+		mapping *GamepadMapping
+		fields  []string
+		field   string
+		key     string
+		value   string
+		button  GamepadButton
+		axis    GamepadAxis
+		binding gamepadBinding
+		sign    int
+		ok      bool
+		isAxis  bool
+		err     error
+	)
+
+	fields = strings.Split(strings.TrimSpace(line), ",")
+	if len(fields) < 2 || !validGUID(fields[0]) {
+		return nil, fmt.Errorf("input.ParseGamepadMapping: %w: %q", ErrInvalidMapping, line)
+	}
+
+	mapping = &GamepadMapping{
+		GUID:    strings.ToLower(fields[0]),
+		Name:    fields[1],
+		buttons: make(map[GamepadButton][]gamepadBinding),
+		axes:    make(map[GamepadAxis][]gamepadBinding),
+	}
+
+	for _, field = range fields[2:] {
+		key, value, ok = strings.Cut(field, ":")
+		if !ok {
+			continue
+		}
+
+		if key == "platform" {
+			mapping.Platform = value
+
+			continue
+		}
+
+		sign, key = cutSign(key)
+
+		button, ok = gamepadButtonNames[key]
+		axis, isAxis = gamepadAxisNames[key]
+
+		if !ok && !isAxis {
+			continue
+		}
+
+		binding, err = parseGamepadBinding(value)
+		if err != nil {
+			return nil, fmt.Errorf("input.ParseGamepadMapping: %s: %w", field, err)
+		}
+
+		binding.outputSign = sign
+
+		if ok {
+			mapping.buttons[button] = append(mapping.buttons[button], binding)
+		} else {
+			mapping.axes[axis] = append(mapping.axes[axis], binding)
+		}
+	}
+
+	return mapping, nil
+}
+
+// LoadGamepadDB reads mapping strings, one per line, such as the
+// contents of gamecontrollerdb.txt. Empty lines and lines starting with
+// # are skipped, as are mappings for platforms other than Linux. A later
+// mapping for the same GUID replaces an earlier one.
+func LoadGamepadDB(r io.Reader) (*GamepadDB, error) {
+	var (
+		db      *GamepadDB
+		scanner *bufio.Scanner
+		line    string
+		mapping *GamepadMapping
+		err     error
+	)
+
+	db = &GamepadDB{mappings: make(map[string]*GamepadMapping)}
+	scanner = bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line = strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		mapping, err = ParseGamepadMapping(line)
+		if err != nil {
+			return nil, fmt.Errorf("input.LoadGamepadDB: %w", err)
+		}
+
+		if mapping.Platform != "" && mapping.Platform != "Linux" {
+			continue
+		}
+
+		db.Add(mapping)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("input.LoadGamepadDB: %w", err)
+	}
+
+	return db, nil
+}
+
+// Add adds mapping to db, replacing any mapping for the same GUID.
+func (db *GamepadDB) Add(mapping *GamepadMapping) {
+	db.mappings[guidKey(mapping.GUID)] = mapping
+}
+
+// Lookup returns the mapping for a device with id. Like SDL, it falls
+// back to a mapping for any version of the device, stored with a zero
+// version, when there is none for its exact version.
+func (db *GamepadDB) Lookup(id ID) (*GamepadMapping, bool) {
+	var (
+		mapping *GamepadMapping
+		ok      bool
+	)
+
+	mapping, ok = db.mappings[guidKey(GamepadGUID(id))]
+	if ok {
+		return mapping, true
+	}
+
+	id.Version = 0
+	mapping, ok = db.mappings[guidKey(GamepadGUID(id))]
+
+	return mapping, ok
+}
+
+// trigger reports whether the axis at index is mapped as a whole to a
+// trigger, so that its dead zone belongs at its minimum.
+func (mapping *GamepadMapping) trigger(index int) bool {
+	var (
+		axis    GamepadAxis
+		binding gamepadBinding
+	)
+
+	for _, axis = range []GamepadAxis{GamepadLeftTrigger, GamepadRightTrigger} {
+		for _, binding = range mapping.axes[axis] {
+			if binding.kind == 'a' && binding.index == index && binding.inputSign == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Gamepad tracks the state of a controller through a [GamepadMapping], so
+// that games can read its buttons and sticks by their place on the
+// standard layout, whatever BTN_* and ABS_* codes the driver uses. Feed
+// it every event of the device with [Gamepad.Update].
+type Gamepad struct {
+	mapping *GamepadMapping
+	buttons map[mylib.InputCode]int
+	axes    map[mylib.InputCode]int
+	hats    map[mylib.InputCode]int
+	state   []*Axis
+	pressed map[int]bool
+	hatX    map[int]int32
+	hatY    map[int]int32
+}
+
+// NewGamepad returns a Gamepad for dev using mapping. It numbers the
+// buttons, axes, and hats of dev the way SDL does on Linux, so that the
+// mapping's indices refer to the same elements.
+func NewGamepad(dev *Device, mapping *GamepadMapping) (*Gamepad, error) {
+	var (
+		pad   *Gamepad
+		keys  *Bitmask
+		abs   *Bitmask
+		code  mylib.InputCode
+		info  AbsInfo
+		index int
+		err   error
+	)
+
+	keys, err = dev.CodeMask(EV_KEY)
+	if err == nil {
+		abs, err = dev.CodeMask(EV_ABS)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("input.NewGamepad: %w", err)
+	}
+
+	pad = &Gamepad{
+		mapping: mapping,
+		buttons: make(map[mylib.InputCode]int),
+		axes:    make(map[mylib.InputCode]int),
+		hats:    make(map[mylib.InputCode]int),
+		pressed: make(map[int]bool),
+		hatX:    make(map[int]int32),
+		hatY:    make(map[int]int32),
+	}
+
+	for code = BTN_JOYSTICK; code < KEY_MAX; code++ {
+		if keys.Test(code) {
+			pad.buttons[code] = len(pad.buttons)
+		}
+	}
+
+	for code = range BTN_JOYSTICK {
+		if keys.Test(code) {
+			pad.buttons[code] = len(pad.buttons)
+		}
+	}
+
+	for code = range ABS_MAX {
+		if code >= ABS_HAT0X && code <= ABS_HAT3Y || !abs.Test(code) {
+			continue
+		}
+
+		info, err = dev.AbsInfo(code)
+		if err != nil {
+			return nil, fmt.Errorf("input.NewGamepad: %w", err)
+		}
+
+		pad.axes[code] = len(pad.state)
+		pad.state = append(pad.state, NewAxis(info, mapping.trigger(len(pad.state))))
+	}
+
+	for code = ABS_HAT0X; code <= ABS_HAT3Y; code += 2 {
+		if !abs.Test(code) && !abs.Test(code+1) {
+			continue
+		}
+
+		index = len(pad.hats) / 2
+		pad.hats[code] = index
+		pad.hats[code+1] = index
+	}
+
+	return pad, nil
+}
+
+// Update applies ev to the state of the gamepad.
+func (pad *Gamepad) Update(ev Event) {
+	var (
+		code  mylib.InputCode
+		index int
+		ok    bool
+	)
+
+	code = mylib.InputCode(ev.Code)
+
+	switch ev.Type {
+	case EV_KEY:
+		index, ok = pad.buttons[code]
+		if ok {
+			pad.pressed[index] = ev.Value != 0
+		}
+	case EV_ABS:
+		index, ok = pad.hats[code]
+		if ok && (code-ABS_HAT0X)%2 == 0 {
+			pad.hatX[index] = ev.Value
+		} else if ok {
+			pad.hatY[index] = ev.Value
+		}
+
+		index, ok = pad.axes[code]
+		if ok {
+			pad.state[index].Update(ev.Value)
+		}
+	}
+}
+
+// Button reports whether button is pressed. A button mapped to an axis
+// is pressed once the axis is past the middle of its mapped range.
+func (pad *Gamepad) Button(button GamepadButton) bool {
+	var binding gamepadBinding
+
+	for _, binding = range pad.mapping.buttons[button] {
+		if pad.unitValue(binding) > 0.5 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Axis returns the position of axis, from -1 to 1 for the sticks and
+// from 0 to 1 for the triggers.
+func (pad *Gamepad) Axis(axis GamepadAxis) float64 {
+	var (
+		binding gamepadBinding
+		value   float64
+		result  float64
+	)
+
+	for _, binding = range pad.mapping.axes[axis] {
+		switch {
+		case binding.outputSign != 0:
+			value = pad.unitValue(binding) * float64(binding.outputSign)
+		case axis == GamepadLeftTrigger || axis == GamepadRightTrigger:
+			value = pad.unitValue(binding)
+		default:
+			value = pad.bindingValue(binding)
+		}
+
+		if math.Abs(value) > math.Abs(result) {
+			result = value
+		}
+	}
+
+	return result
+}
+
+// unitValue returns the value of binding from 0 to 1, scaling full axes
+// into that range.
+func (pad *Gamepad) unitValue(binding gamepadBinding) float64 {
+	if binding.kind == 'a' && binding.inputSign == 0 &&
+		(binding.index >= len(pad.state) || !pad.state[binding.index].trigger) {
+		return (pad.bindingValue(binding) + 1) / 2
+	}
+
+	return pad.bindingValue(binding)
+}
+
+// bindingValue returns the value of binding: 0 or 1 for buttons and
+// hats, 0 to 1 for half axes, and -1 to 1 for full axes.
+func (pad *Gamepad) bindingValue(binding gamepadBinding) float64 {
+	var value float64
+
+	switch binding.kind {
+	case 'b':
+		if pad.pressed[binding.index] {
+			return 1
+		}
+	case 'h':
+		if hatMask(pad.hatX[binding.index], pad.hatY[binding.index])&binding.hatMask != 0 {
+			return 1
+		}
+	case 'a':
+		if binding.index >= len(pad.state) {
+			return 0
+		}
+
+		value = pad.state[binding.index].Value()
+		if binding.invert {
+			value = -value
+		}
+
+		if binding.inputSign != 0 {
+			value = max(value*float64(binding.inputSign), 0)
+		}
+
+		return value
+	}
+
+	return 0
+}
+
+func parseGamepadBinding(value string) (gamepadBinding, error) {
+	var (
+		binding gamepadBinding
+		hat     string
+		mask    string
+		ok      bool
+		err     error
+	)
+
+	binding.inputSign, value = cutSign(value)
+	value, binding.invert = strings.CutSuffix(value, "~")
+
+	if value == "" {
+		return gamepadBinding{}, ErrInvalidMapping
+	}
+
+	binding.kind = value[0]
+
+	switch binding.kind {
+	case 'a', 'b':
+		binding.index, err = strconv.Atoi(value[1:])
+	case 'h':
+		hat, mask, ok = strings.Cut(value[1:], ".")
+		if !ok {
+			return gamepadBinding{}, ErrInvalidMapping
+		}
+
+		binding.index, err = strconv.Atoi(hat)
+		if err == nil {
+			binding.hatMask, err = strconv.Atoi(mask)
+		}
+	default:
+		return gamepadBinding{}, ErrInvalidMapping
+	}
+
+	if err != nil {
+		return gamepadBinding{}, fmt.Errorf("%w: %w", ErrInvalidMapping, err)
+	}
+
+	return binding, nil
+}
+
+// cutSign removes a leading + or - from s, returning 1 or -1 for it, or
+// 0 if there is none.
+func cutSign(s string) (int, string) {
+	switch {
+	case strings.HasPrefix(s, "+"):
+		return 1, s[1:]
+	case strings.HasPrefix(s, "-"):
+		return -1, s[1:]
+	default:
+		return 0, s
+	}
+}
+
+// guidKey returns guid with the CRC of the device name, which newer SDL
+// versions store in bytes 2 and 3, cleared, since mappings in the
+// database may be written with or without it.
+func guidKey(guid string) string {
+	guid = strings.ToLower(guid)
+	if len(guid) != 32 {
+		return guid
+	}
+
+	return guid[:4] + "0000" + guid[8:]
+}
+
+func validGUID(guid string) bool {
+	var err error
+
+	_, err = hex.DecodeString(guid)
+
+	return len(guid) == 32 && err == nil
+}
+
+// hatMask returns the SDL hat direction mask of a hat at x and y.
+func hatMask(x, y int32) int {
+	var mask int
+
+	if y < 0 {
+		mask |= 1
+	}
+
+	if x > 0 {
+		mask |= 2
+	}
+
+	if y > 0 {
+		mask |= 4
+	}
+
+	if x < 0 {
+		mask |= 8
+	}
+
+	return mask
+}
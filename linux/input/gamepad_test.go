@@ -0,0 +1,319 @@
+//go:build linux
+
+package input
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andrieee44/mylib"
+)
+
+type parseMappingTest struct {
+	name string
+	line string
+	want *GamepadMapping
+}
+
+type gamepadTest struct {
+	name    string
+	events  []Event
+	pressed []GamepadButton
+	axes    map[GamepadAxis]float64
+}
+
+const testGUID string = "030000005e0400008e02000014010000"
+
+func TestParseGamepadMapping(t *testing.T) {
+	var (
+		tests   []parseMappingTest
+		test    parseMappingTest
+		mapping *GamepadMapping
+		err     error
+	)
+
+	tests = []parseMappingTest{
+		{
+			name: "elements",
+			line: testGUID + ",Xbox 360 Controller," +
+				"a:b0,dpup:h0.1,leftx:a0,lefty:a1~,lefttrigger:a2,platform:Linux,",
+			want: &GamepadMapping{
+				GUID:     testGUID,
+				Name:     "Xbox 360 Controller",
+				Platform: "Linux",
+				buttons: map[GamepadButton][]gamepadBinding{
+					GamepadA:      {{kind: 'b'}},
+					GamepadDpadUp: {{kind: 'h', hatMask: 1}},
+				},
+				axes: map[GamepadAxis][]gamepadBinding{
+					GamepadLeftX:       {{kind: 'a'}},
+					GamepadLeftY:       {{kind: 'a', index: 1, invert: true}},
+					GamepadLeftTrigger: {{kind: 'a', index: 2}},
+				},
+			},
+		},
+		{
+			name: "half axes",
+			line: testGUID + ",Pad,dpleft:-a6,dpright:+a6,-leftx:h0.8,+leftx:h0.2,righttrigger:+a5~",
+			want: &GamepadMapping{
+				GUID: testGUID,
+				Name: "Pad",
+				buttons: map[GamepadButton][]gamepadBinding{
+					GamepadDpadLeft:  {{kind: 'a', index: 6, inputSign: -1}},
+					GamepadDpadRight: {{kind: 'a', index: 6, inputSign: 1}},
+				},
+				axes: map[GamepadAxis][]gamepadBinding{
+					GamepadLeftX: {
+						{kind: 'h', hatMask: 8, outputSign: -1},
+						{kind: 'h', hatMask: 2, outputSign: 1},
+					},
+					GamepadRightTrigger: {{kind: 'a', index: 5, inputSign: 1, invert: true}},
+				},
+			},
+		},
+		{
+			name: "unknown elements and hints",
+			line: " " + strings.ToUpper(testGUID) + ",Pad,crc:ab12,paddle9:b4,hint:!SDL_FOO:=1,b:b1 \n",
+			want: &GamepadMapping{
+				GUID:    testGUID,
+				Name:    "Pad",
+				buttons: map[GamepadButton][]gamepadBinding{GamepadB: {{kind: 'b', index: 1}}},
+				axes:    map[GamepadAxis][]gamepadBinding{},
+			},
+		},
+		{name: "GUID only", line: testGUID},
+		{name: "short GUID", line: "030000005e04,Pad,a:b0"},
+		{name: "GUID not hex", line: strings.Repeat("g", 32) + ",Pad,a:b0"},
+		{name: "empty binding", line: testGUID + ",Pad,a:"},
+		{name: "unknown binding kind", line: testGUID + ",Pad,a:x0"},
+		{name: "missing index", line: testGUID + ",Pad,a:b"},
+		{name: "hat without a mask", line: testGUID + ",Pad,dpup:h0"},
+		{name: "malformed hat mask", line: testGUID + ",Pad,dpup:h0.up"},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mapping, err = ParseGamepadMapping(test.line)
+
+			if test.want == nil {
+				if !errors.Is(err, ErrInvalidMapping) {
+					t.Errorf("ParseGamepadMapping = %+v, %v, want ErrInvalidMapping", mapping, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(mapping, test.want) {
+				t.Errorf("ParseGamepadMapping = %+v, want %+v", mapping, test.want)
+			}
+		})
+	}
+}
+
+func TestGamepadGUID(t *testing.T) {
+	var guid string
+
+	guid = GamepadGUID(ID{Bustype: BUS_USB, Vendor: 0x045e, Product: 0x028e, Version: 0x0114})
+	if guid != testGUID {
+		t.Errorf("GamepadGUID = %s, want %s", guid, testGUID)
+	}
+}
+
+func TestGamepadDB(t *testing.T) {
+	var (
+		db      *GamepadDB
+		id      ID
+		mapping *GamepadMapping
+		ok      bool
+		err     error
+	)
+
+	db, err = LoadGamepadDB(strings.NewReader(strings.Join([]string{
+		"# Game Controller DB",
+		"",
+		testGUID + ",Old Name,a:b0,platform:Linux,",
+		testGUID + ",Xbox 360 Controller,a:b0,platform:Linux,",
+		testGUID + ",Windows Name,a:b0,platform:Windows,",
+		"03000000c82d00000000000000000000,Any Version,a:b0,",
+		"0300abcd4c0500006802000011810000,With CRC,a:b0,platform:Linux,",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id = ID{Bustype: BUS_USB, Vendor: 0x045e, Product: 0x028e, Version: 0x0114}
+
+	mapping, ok = db.Lookup(id)
+	if !ok || mapping.Name != "Xbox 360 Controller" {
+		t.Errorf("Lookup(%+v) = %+v, %t, want the last Linux mapping", id, mapping, ok)
+	}
+
+	// A zero version in the database matches any version.
+	id = ID{Bustype: BUS_USB, Vendor: 0x2dc8, Version: 0x0111}
+
+	mapping, ok = db.Lookup(id)
+	if !ok || mapping.Name != "Any Version" {
+		t.Errorf("Lookup(%+v) = %+v, %t, want the versionless mapping", id, mapping, ok)
+	}
+
+	// The name CRC in newer GUIDs is ignored.
+	id = ID{Bustype: BUS_USB, Vendor: 0x054c, Product: 0x0268, Version: 0x8111}
+
+	mapping, ok = db.Lookup(id)
+	if !ok || mapping.Name != "With CRC" {
+		t.Errorf("Lookup(%+v) = %+v, %t, want the mapping with a CRC", id, mapping, ok)
+	}
+
+	id = ID{Bustype: BUS_USB, Vendor: 0x045e, Product: 0x02ea}
+
+	mapping, ok = db.Lookup(id)
+	if ok {
+		t.Errorf("Lookup(%+v) = %+v, want no mapping", id, mapping)
+	}
+
+	_, err = LoadGamepadDB(strings.NewReader(testGUID + ",Pad,a:b0\nnot a mapping\n"))
+	if !errors.Is(err, ErrInvalidMapping) {
+		t.Errorf("LoadGamepadDB = %v, want ErrInvalidMapping", err)
+	}
+}
+
+// testGamepad returns a Gamepad using mapping for a device with two
+// buttons, a stick, a trigger, a third stick axis, and a hat, numbered
+// as [NewGamepad] numbers them.
+func testGamepad(mapping *GamepadMapping) *Gamepad {
+	var stick, trigger AbsInfo
+
+	stick = AbsInfo{Minimum: -100, Maximum: 100}
+	trigger = AbsInfo{Maximum: 100}
+
+	return &Gamepad{
+		mapping: mapping,
+		buttons: map[mylib.InputCode]int{BTN_SOUTH: 0, BTN_EAST: 1},
+		axes:    map[mylib.InputCode]int{ABS_X: 0, ABS_Y: 1, ABS_Z: 2, ABS_RX: 3},
+		hats:    map[mylib.InputCode]int{ABS_HAT0X: 0, ABS_HAT0Y: 0},
+		state: []*Axis{
+			NewAxis(stick, mapping.trigger(0)),
+			NewAxis(stick, mapping.trigger(1)),
+			NewAxis(trigger, mapping.trigger(2)),
+			NewAxis(stick, mapping.trigger(3)),
+		},
+		pressed: make(map[int]bool),
+		hatX:    make(map[int]int32),
+		hatY:    make(map[int]int32),
+	}
+}
+
+func TestGamepad(t *testing.T) {
+	var (
+		mapping *GamepadMapping
+		tests   []gamepadTest
+		test    gamepadTest
+		pad     *Gamepad
+		ev      Event
+		button  GamepadButton
+		axis    GamepadAxis
+		want    float64
+		err     error
+	)
+
+	mapping, err = ParseGamepadMapping(testGUID + ",Test Pad," +
+		"a:b0,righttrigger:b1,leftx:a0,lefty:a1~,lefttrigger:a2,dpleft:-a3,dpright:+a3," +
+		"dpup:h0.1,dpdown:h0.4,-rightx:h0.8,+rightx:h0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests = []gamepadTest{
+		{
+			name: "idle",
+			axes: map[GamepadAxis]float64{GamepadLeftX: 0, GamepadLeftTrigger: 0, GamepadRightTrigger: 0},
+		},
+		{
+			name: "buttons",
+			events: []Event{
+				{Type: EV_KEY, Code: BTN_SOUTH, Value: 1},
+				{Type: EV_KEY, Code: BTN_EAST, Value: 1},
+				{Type: EV_KEY, Code: BTN_NORTH, Value: 1},
+			},
+			pressed: []GamepadButton{GamepadA},
+			axes:    map[GamepadAxis]float64{GamepadRightTrigger: 1},
+		},
+		{
+			name: "released",
+			events: []Event{
+				{Type: EV_KEY, Code: BTN_SOUTH, Value: 1},
+				{Type: EV_KEY, Code: BTN_SOUTH, Value: 0},
+			},
+		},
+		{
+			name: "hat",
+			events: []Event{
+				{Type: EV_ABS, Code: ABS_HAT0Y, Value: -1},
+				{Type: EV_ABS, Code: ABS_HAT0X, Value: -1},
+			},
+			pressed: []GamepadButton{GamepadDpadUp},
+			axes:    map[GamepadAxis]float64{GamepadRightX: -1},
+		},
+		{
+			name: "hat moved",
+			events: []Event{
+				{Type: EV_ABS, Code: ABS_HAT0Y, Value: -1},
+				{Type: EV_ABS, Code: ABS_HAT0Y, Value: 1},
+				{Type: EV_ABS, Code: ABS_HAT0X, Value: 1},
+			},
+			pressed: []GamepadButton{GamepadDpadDown},
+			axes:    map[GamepadAxis]float64{GamepadRightX: 1},
+		},
+		{
+			name: "sticks",
+			events: []Event{
+				{Type: EV_ABS, Code: ABS_X, Value: -50},
+				{Type: EV_ABS, Code: ABS_Y, Value: -100},
+			},
+			axes: map[GamepadAxis]float64{GamepadLeftX: -0.5, GamepadLeftY: 1},
+		},
+		{
+			name:   "trigger",
+			events: []Event{{Type: EV_ABS, Code: ABS_Z, Value: 50}},
+			axes:   map[GamepadAxis]float64{GamepadLeftTrigger: 0.5},
+		},
+		{
+			name:    "half axis past the middle",
+			events:  []Event{{Type: EV_ABS, Code: ABS_RX, Value: -60}},
+			pressed: []GamepadButton{GamepadDpadLeft},
+		},
+		{
+			name:   "half axis short of the middle",
+			events: []Event{{Type: EV_ABS, Code: ABS_RX, Value: 40}},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pad = testGamepad(mapping)
+
+			for _, ev = range test.events {
+				pad.Update(ev)
+			}
+
+			for button = range GamepadTouchpad + 1 {
+				if pad.Button(button) != slices.Contains(test.pressed, button) {
+					t.Errorf("Button(%d) = %t", button, pad.Button(button))
+				}
+			}
+
+			for axis, want = range test.axes {
+				if pad.Axis(axis) != want {
+					t.Errorf("Axis(%d) = %g, want %g", axis, pad.Axis(axis), want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+//go:build ignore
+
+// gen_names generates names.go, the tables mapping event types, event
+// codes, and input properties to their names. It reads the constants
+// transcribed from input-event-codes.h and input.h in eventCodes.go and
+// uapi.go, skipping *_MAX and *_CNT markers. The value-to-name tables
+// skip aliases such as BTN_A, and when several constants share a value,
+// the last one wins, so that range markers such as BTN_MISC give way to
+// the specific BTN_0. The name-to-value tables keep every name.
+//
+// The backends of other systems number their events like Linux but
+// declare only a few constants. For them, gen_names is run from their
+// package directory with flags:
+//
+//	go run ../../../linux/input/gen_names.go -src ../../../linux/input \
+//		-tag windows -types EV_SYN,EV_KEY,EV_REL,EV_ABS
+//
+// which writes only the value-to-name tables of the listed event types,
+// keyed by numeric value.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+type constant struct {
+	name  string
+	value int64
+	alias bool
+}
+
+var prefixes []struct{ prefix, eventType string } = []struct{ prefix, eventType string }{
+	{"SYN_", "EV_SYN"},
+	{"KEY_", "EV_KEY"},
+	{"BTN_", "EV_KEY"},
+	{"REL_", "EV_REL"},
+	{"ABS_", "EV_ABS"},
+	{"MSC_", "EV_MSC"},
+	{"SW_", "EV_SW"},
+	{"LED_", "EV_LED"},
+	{"SND_", "EV_SND"},
+	{"REP_", "EV_REP"},
+	{"FF_STATUS_", "EV_FF_STATUS"},
+	{"FF_", "EV_FF"},
+}
+
+func main() {
+	var (
+		srcDir, tag   *string
+		types         *string
+		constants     []constant
+		c             constant
+		eventTypes    []string
+		eventType     string
+		events, props []constant
+		allEvents     []constant
+		allCodes      []constant
+		codes         map[string][]constant
+		buf           bytes.Buffer
+	)
+
+	srcDir = flag.String("src", ".", "directory holding eventCodes.go and uapi.go")
+	tag = flag.String("tag", "linux", "build tag of the generated file")
+	types = flag.String("types", "", "comma-separated event types to name in a backend table")
+	flag.Parse()
+
+	constants = parse(
+		filepath.Join(*srcDir, "eventCodes.go"),
+		filepath.Join(*srcDir, "uapi.go"),
+	)
+	codes = make(map[string][]constant)
+
+	for _, c = range constants {
+		switch {
+		case strings.HasPrefix(c.name, "INPUT_PROP_"):
+			props = add(props, c)
+		case strings.HasPrefix(c.name, "EV_"):
+			if c.name == "EV_VERSION" {
+				continue
+			}
+
+			allEvents = append(allEvents, c)
+			if !c.alias {
+				events = add(events, c)
+			}
+		default:
+			eventType = typeOf(c.name)
+			if eventType == "" {
+				continue
+			}
+
+			allCodes = append(allCodes, c)
+			if c.alias {
+				continue
+			}
+
+			if codes[eventType] == nil {
+				eventTypes = append(eventTypes, eventType)
+			}
+
+			codes[eventType] = add(codes[eventType], c)
+		}
+	}
+
+	buf.WriteString("// Code generated by gen_names.go; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "//go:build %s\n\npackage input\n\n", *tag)
+	buf.WriteString("import \"github.com/andrieee44/mylib\"\n\n")
+
+	if *types != "" {
+		writeBackend(&buf, strings.Split(*types, ","), events, codes)
+		writeFile(buf.Bytes())
+
+		return
+	}
+
+	buf.WriteString("var eventNames map[mylib.InputEvent]string = map[mylib.InputEvent]string{\n")
+	writeEntries(&buf, events)
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("var codeNames map[mylib.InputEvent]map[mylib.InputCode]string = ")
+	buf.WriteString("map[mylib.InputEvent]map[mylib.InputCode]string{\n")
+
+	for _, eventType = range eventTypes {
+		fmt.Fprintf(&buf, "%s: {\n", eventType)
+		writeEntries(&buf, codes[eventType])
+		buf.WriteString("},\n")
+	}
+
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("var propertyNames map[Property]string = map[Property]string{\n")
+	writeEntries(&buf, props)
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("var eventValues map[string]mylib.InputEvent = map[string]mylib.InputEvent{\n")
+
+	for _, c = range allEvents {
+		fmt.Fprintf(&buf, "%q: %s,\n", c.name, c.name)
+	}
+
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("var codeValues map[string]eventCode = map[string]eventCode{\n")
+
+	for _, c = range allCodes {
+		fmt.Fprintf(&buf, "%q: {%s, %s},\n", c.name, typeOf(c.name), c.name)
+	}
+
+	buf.WriteString("}\n")
+
+	writeFile(buf.Bytes())
+}
+
+// writeBackend writes the value-to-name tables of a backend naming
+// eventTypes. Codes are keyed by value, since the backend lacks most of
+// the constants.
+func writeBackend(
+	buf *bytes.Buffer,
+	eventTypes []string,
+	events []constant,
+	codes map[string][]constant,
+) {
+	var (
+		c         constant
+		eventType string
+	)
+
+	buf.WriteString("var eventNames map[mylib.InputEvent]string = map[mylib.InputEvent]string{\n")
+
+	for _, c = range events {
+		if slices.Contains(eventTypes, c.name) {
+			fmt.Fprintf(buf, "%s: %q,\n", c.name, c.name)
+		}
+	}
+
+	buf.WriteString("}\n\n")
+
+	buf.WriteString("var codeNames map[mylib.InputEvent]map[mylib.InputCode]string = ")
+	buf.WriteString("map[mylib.InputEvent]map[mylib.InputCode]string{\n")
+
+	for _, eventType = range eventTypes {
+		fmt.Fprintf(buf, "%s: {\n", eventType)
+
+		for _, c = range codes[eventType] {
+			fmt.Fprintf(buf, "%#x: %q,\n", c.value, c.name)
+		}
+
+		buf.WriteString("},\n")
+	}
+
+	buf.WriteString("}\n")
+}
+
+func writeFile(data []byte) {
+	var (
+		src []byte
+		err error
+	)
+
+	src, err = format.Source(data)
+	exitIf(err)
+
+	err = os.WriteFile("names.go", src, 0o644)
+	exitIf(err)
+}
+
+func parse(paths ...string) []constant {
+	var (
+		fset      *token.FileSet
+		file      *ast.File
+		decl      ast.Decl
+		genDecl   *ast.GenDecl
+		ok        bool
+		spec      ast.Spec
+		valueSpec *ast.ValueSpec
+		lit       *ast.BasicLit
+		ident     *ast.Ident
+		value     int64
+		values    map[string]int64
+		constants []constant
+		path      string
+		err       error
+	)
+
+	fset = token.NewFileSet()
+	values = make(map[string]int64)
+
+	for _, path = range paths {
+		file, err = parser.ParseFile(fset, path, nil, 0)
+		exitIf(err)
+
+		for _, decl = range file.Decls {
+			genDecl, ok = decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+
+			for _, spec = range genDecl.Specs {
+				valueSpec = spec.(*ast.ValueSpec)
+				if len(valueSpec.Values) != 1 || skip(valueSpec.Names[0].Name) {
+					continue
+				}
+
+				ident, ok = valueSpec.Values[0].(*ast.Ident)
+				if ok {
+					value, ok = values[ident.Name]
+					if !ok {
+						continue
+					}
+
+					values[valueSpec.Names[0].Name] = value
+					constants = append(constants, constant{
+						name:  valueSpec.Names[0].Name,
+						value: value,
+						alias: true,
+					})
+
+					continue
+				}
+
+				lit, ok = valueSpec.Values[0].(*ast.BasicLit)
+				if !ok || lit.Kind != token.INT {
+					continue
+				}
+
+				value, err = strconv.ParseInt(lit.Value, 0, 64)
+				exitIf(err)
+
+				values[valueSpec.Names[0].Name] = value
+				constants = append(constants, constant{
+					name:  valueSpec.Names[0].Name,
+					value: value,
+				})
+			}
+		}
+	}
+
+	return constants
+}
+
+func skip(name string) bool {
+	return strings.HasSuffix(name, "_MAX") || strings.HasSuffix(name, "_CNT")
+}
+
+func typeOf(name string) string {
+	var p struct{ prefix, eventType string }
+
+	for _, p = range prefixes {
+		if strings.HasPrefix(name, p.prefix) {
+			return p.eventType
+		}
+	}
+
+	return ""
+}
+
+func add(constants []constant, c constant) []constant {
+	var i int
+
+	for i = range constants {
+		if constants[i].value == c.value {
+			constants[i] = c
+
+			return constants
+		}
+	}
+
+	return append(constants, c)
+}
+
+func writeEntries(buf *bytes.Buffer, constants []constant) {
+	var c constant
+
+	for _, c = range constants {
+		fmt.Fprintf(buf, "%s: %q,\n", c.name, c.name)
+	}
+}
+
+func exitIf(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gen_names:", err)
+		os.Exit(1)
+	}
+}
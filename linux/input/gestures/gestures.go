@@ -0,0 +1,45 @@
+//go:build linux
+
+// Package gestures forwards to
+// [github.com/andrieee44/mylib/x/linux/input/gestures], where the
+// experimental touchpad gesture recognizer moved, so that existing
+// imports keep building.
+//
+// Deprecated: Import github.com/andrieee44/mylib/x/linux/input/gestures
+// instead. This package will be removed in a future release.
+package gestures
+
+import "github.com/andrieee44/mylib/x/linux/input/gestures"
+
+type (
+	// Config is [gestures.Config].
+	Config = gestures.Config
+
+	// Gesture is [gestures.Gesture].
+	Gesture = gestures.Gesture
+
+	// Kind is [gestures.Kind].
+	Kind = gestures.Kind
+
+	// Recognizer is [gestures.Recognizer].
+	Recognizer = gestures.Recognizer
+)
+
+// The kinds of [gestures.Kind].
+const (
+	Tap       = gestures.Tap
+	DoubleTap = gestures.DoubleTap
+	Scroll    = gestures.Scroll
+	Pinch     = gestures.Pinch
+	Swipe     = gestures.Swipe
+)
+
+// DefaultConfig calls [gestures.DefaultConfig].
+func DefaultConfig() Config {
+	return gestures.DefaultConfig()
+}
+
+// NewRecognizer calls [gestures.NewRecognizer].
+func NewRecognizer(slots int, config Config, onGesture func(Gesture)) *Recognizer {
+	return gestures.NewRecognizer(slots, config, onGesture)
+}
@@ -0,0 +1,101 @@
+//go:build linux
+
+package input
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// physInterface matches the interface suffix that USB and HID drivers
+// append to the phys path of each event node of a device, such as the
+// "/input1" of "usb-0000:00:14.0-3/input1".
+var physInterface *regexp.Regexp = regexp.MustCompile(`/input[0-9]+$`)
+
+// DeviceGroup is a set of event nodes belonging to one physical device,
+// such as a keyboard exposing separate nodes for its main keys, its
+// consumer-control keys, and its system-control keys.
+type DeviceGroup struct {
+	// Nodes holds the paths of the event device nodes, such as
+	// "/dev/input/event3", in lexical order.
+	Nodes []string
+
+	// Infos holds the metadata of each node in Nodes.
+	Infos []*DeviceInfo
+}
+
+// Group groups the event devices using NewSystem(nil).
+func Group() ([]DeviceGroup, error) {
+	return NewSystem(nil).Group()
+}
+
+// Group returns the event device nodes grouped by the physical device
+// they belong to, in the order of their first nodes. Nodes are grouped
+// when they share their bus, vendor, and product, their unique
+// identifier, and their phys path up to the interface suffix. Nodes
+// whose driver sets neither a phys path nor a unique identifier are each
+// placed in a group of their own.
+func (sys *System) Group() ([]DeviceGroup, error) {
+	var (
+		nodes   []string
+		node    string
+		info    *DeviceInfo
+		groups  []DeviceGroup
+		indices map[string]int
+		key     string
+		index   int
+		ok      bool
+		err     error
+	)
+
+	nodes, err = sys.EventNodes()
+	if err != nil {
+		return nil, fmt.Errorf("System.Group: %w", err)
+	}
+
+	indices = make(map[string]int)
+
+	for _, node = range nodes {
+		info, err = sys.SysInfo(node)
+		if err != nil {
+			return nil, fmt.Errorf("System.Group: %w", err)
+		}
+
+		key = groupKey(info)
+		if key == "" {
+			key = node
+		}
+
+		index, ok = indices[key]
+		if !ok {
+			index = len(groups)
+			indices[key] = index
+			groups = append(groups, DeviceGroup{})
+		}
+
+		groups[index].Nodes = append(groups[index].Nodes, node)
+		groups[index].Infos = append(groups[index].Infos, info)
+	}
+
+	return groups, nil
+}
+
+// groupKey returns the key identifying the physical device of info, or
+// an empty string if there is nothing to identify it by. The unique
+// identifier is kept alongside the phys path because Bluetooth drivers
+// set phys to the address of the adapter, which all of its devices
+// share.
+func groupKey(info *DeviceInfo) string {
+	if info.Phys == "" && info.Uniq == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(
+		"%04x:%04x:%04x %s %s",
+		info.ID.Bustype,
+		info.ID.Vendor,
+		info.ID.Product,
+		physInterface.ReplaceAllString(info.Phys, ""),
+		info.Uniq,
+	)
+}
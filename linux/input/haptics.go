@@ -0,0 +1,123 @@
+//go:build linux
+
+package input
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/andrieee44/mylib"
+)
+
+var _ mylib.HapticEffects = (*Device)(nil)
+
+// UploadHaptic implements [mylib.HapticEffects] by translating effect
+// into an [FF_RUMBLE], [FF_CONSTANT], or [FF_PERIODIC] effect and
+// uploading it with [Device.UploadEffect]. It returns
+// [mylib.ErrUnsupportedEffect] if the device does not report the effect
+// type, or the waveform, in its [EV_FF] codes.
+func (dev *Device) UploadHaptic(effect mylib.HapticEffect) (int, error) {
+	var (
+		ffEffect FFEffect
+		data     FFEffectData
+		codes    *Bitmask
+		id       int16
+		err      error
+	)
+
+	ffEffect = FFEffect{
+		Id: -1,
+		Replay: FFReplay{
+			Length: ffMilliseconds(effect.Duration),
+			Delay:  ffMilliseconds(effect.Delay),
+		},
+	}
+
+	codes, err = dev.CodeMask(EV_FF)
+	if err != nil {
+		return 0, fmt.Errorf("Device.UploadHaptic: %w", err)
+	}
+
+	switch effect.Kind {
+	case mylib.HapticRumble:
+		ffEffect.Type = FF_RUMBLE
+		data = FFRumbleEffect{StrongMagnitude: effect.Strong, WeakMagnitude: effect.Weak}
+	case mylib.HapticConstant:
+		ffEffect.Type = FF_CONSTANT
+		data = FFConstantEffect{Level: effect.Level}
+	case mylib.HapticSine, mylib.HapticSquare, mylib.HapticTriangle:
+		ffEffect.Type = FF_PERIODIC
+		data = FFPeriodicEffect{
+			Waveform:  hapticWaveforms[effect.Kind],
+			Period:    ffMilliseconds(effect.Period),
+			Magnitude: effect.Level,
+		}
+
+		if !codes.Test(mylib.InputCode(hapticWaveforms[effect.Kind])) {
+			return 0, fmt.Errorf("Device.UploadHaptic: %w: waveform %d", mylib.ErrUnsupportedEffect, effect.Kind)
+		}
+	default:
+		return 0, fmt.Errorf("Device.UploadHaptic: %w: kind %d", mylib.ErrUnsupportedEffect, effect.Kind)
+	}
+
+	if !codes.Test(mylib.InputCode(ffEffect.Type)) {
+		return 0, fmt.Errorf("Device.UploadHaptic: %w: type %#x", mylib.ErrUnsupportedEffect, ffEffect.Type)
+	}
+
+	id, err = dev.UploadEffect(ffEffect, data)
+	if err != nil {
+		return 0, fmt.Errorf("Device.UploadHaptic: %w", err)
+	}
+
+	return int(id), nil
+}
+
+// PlayHaptic implements [mylib.HapticEffects] with [Device.PlayEffect].
+func (dev *Device) PlayHaptic(id, count int) error {
+	var err error
+
+	err = dev.PlayEffect(int16(id), int32(count))
+	if err != nil {
+		return fmt.Errorf("Device.PlayHaptic: %w", err)
+	}
+
+	return nil
+}
+
+// StopHaptic implements [mylib.HapticEffects] with [Device.StopEffect].
+func (dev *Device) StopHaptic(id int) error {
+	var err error
+
+	err = dev.StopEffect(int16(id))
+	if err != nil {
+		return fmt.Errorf("Device.StopHaptic: %w", err)
+	}
+
+	return nil
+}
+
+// EraseHaptic implements [mylib.HapticEffects] with [Device.EraseEffect].
+func (dev *Device) EraseHaptic(id int) error {
+	var err error
+
+	err = dev.EraseEffect(int16(id))
+	if err != nil {
+		return fmt.Errorf("Device.EraseHaptic: %w", err)
+	}
+
+	return nil
+}
+
+// hapticWaveforms maps the periodic haptic kinds to their waveforms.
+var hapticWaveforms map[mylib.HapticKind]uint16 = map[mylib.HapticKind]uint16{
+	mylib.HapticSine:     FF_SINE,
+	mylib.HapticSquare:   FF_SQUARE,
+	mylib.HapticTriangle: FF_TRIANGLE,
+}
+
+// ffMilliseconds converts duration to the milliseconds of [FFReplay],
+// clamping it to 0 through 65535.
+func ffMilliseconds(duration time.Duration) uint16 {
+	return uint16(min(max(duration.Milliseconds(), 0), math.MaxUint16))
+}
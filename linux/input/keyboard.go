@@ -0,0 +1,445 @@
+//go:build linux
+
+package input
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/andrieee44/mylib"
+)
+
+// ErrInvalidKeymap is returned by [LoadKeymap] for malformed keymap
+// lines.
+var ErrInvalidKeymap error = errors.New("invalid keymap")
+
+// Modifiers is a set of held modifier keys and active lock keys.
+type Modifiers uint
+
+const (
+	// ModShift is set while either Shift key is held.
+	ModShift Modifiers = 1 << iota
+
+	// ModCtrl is set while either Ctrl key is held.
+	ModCtrl
+
+	// ModAlt is set while the left Alt key is held.
+	ModAlt
+
+	// ModAltGr is set while the right Alt key, AltGr, is held.
+	ModAltGr
+
+	// ModMeta is set while either Meta key is held.
+	ModMeta
+
+	// ModCapsLock is set while Caps Lock is active.
+	ModCapsLock
+
+	// ModNumLock is set while Num Lock is active.
+	ModNumLock
+)
+
+// Keymap maps key codes to the runes they produce on each of four
+// levels: plain, with Shift, with AltGr, and with Shift and AltGr. A zero
+// rune means the key produces no text on that level.
+type Keymap map[mylib.InputCode][4]rune
+
+// KeyEvent is a key press translated by a [Keyboard].
+type KeyEvent struct {
+	// Code is the key code of the pressed key.
+	Code mylib.InputCode
+
+	// Rune is the text the key produces, or zero if it produces none.
+	// With Ctrl held, letters produce the matching control character,
+	// such as 0x03 for Ctrl+C.
+	Rune rune
+
+	// Keysym is the X11 keysym name of keys that produce no printable
+	// text, such as "Return", "Left", or "F1", and empty otherwise.
+	Keysym string
+
+	// Modifiers holds the modifiers active when the key was pressed.
+	Modifiers Modifiers
+
+	// Repeat is true for autorepeated presses.
+	Repeat bool
+}
+
+// Keyboard translates the key events of a keyboard into text, tracking
+// modifier and lock state and applying a [Keymap], so that terminal and
+// kiosk applications can read text directly from evdev.
+type Keyboard struct {
+	keymap Keymap
+	mods   Modifiers
+	held   map[mylib.InputCode]bool
+}
+
+var modifierKeys map[mylib.InputCode]Modifiers = map[mylib.InputCode]Modifiers{
+	KEY_LEFTSHIFT:  ModShift,
+	KEY_RIGHTSHIFT: ModShift,
+	KEY_LEFTCTRL:   ModCtrl,
+	KEY_RIGHTCTRL:  ModCtrl,
+	KEY_LEFTALT:    ModAlt,
+	KEY_RIGHTALT:   ModAltGr,
+	KEY_LEFTMETA:   ModMeta,
+	KEY_RIGHTMETA:  ModMeta,
+}
+
+var keysyms map[mylib.InputCode]string = map[mylib.InputCode]string{
+	KEY_ESC:        "Escape",
+	KEY_BACKSPACE:  "BackSpace",
+	KEY_TAB:        "Tab",
+	KEY_ENTER:      "Return",
+	KEY_KPENTER:    "KP_Enter",
+	KEY_DELETE:     "Delete",
+	KEY_INSERT:     "Insert",
+	KEY_HOME:       "Home",
+	KEY_END:        "End",
+	KEY_PAGEUP:     "Prior",
+	KEY_PAGEDOWN:   "Next",
+	KEY_LEFT:       "Left",
+	KEY_RIGHT:      "Right",
+	KEY_UP:         "Up",
+	KEY_DOWN:       "Down",
+	KEY_F1:         "F1",
+	KEY_F2:         "F2",
+	KEY_F3:         "F3",
+	KEY_F4:         "F4",
+	KEY_F5:         "F5",
+	KEY_F6:         "F6",
+	KEY_F7:         "F7",
+	KEY_F8:         "F8",
+	KEY_F9:         "F9",
+	KEY_F10:        "F10",
+	KEY_F11:        "F11",
+	KEY_F12:        "F12",
+	KEY_SYSRQ:      "Print",
+	KEY_PAUSE:      "Pause",
+	KEY_COMPOSE:    "Menu",
+	KEY_CAPSLOCK:   "Caps_Lock",
+	KEY_NUMLOCK:    "Num_Lock",
+	KEY_SCROLLLOCK: "Scroll_Lock",
+}
+
+// Keypad keys produce digits with Num Lock active and navigate without.
+var keypadNavigation map[mylib.InputCode]mylib.InputCode = map[mylib.InputCode]mylib.InputCode{
+	KEY_KP0:   KEY_INSERT,
+	KEY_KP1:   KEY_END,
+	KEY_KP2:   KEY_DOWN,
+	KEY_KP3:   KEY_PAGEDOWN,
+	KEY_KP4:   KEY_LEFT,
+	KEY_KP6:   KEY_RIGHT,
+	KEY_KP7:   KEY_HOME,
+	KEY_KP8:   KEY_UP,
+	KEY_KP9:   KEY_PAGEUP,
+	KEY_KPDOT: KEY_DELETE,
+}
+
+// USKeymap returns the US QWERTY keymap.
+func USKeymap() Keymap {
+	var (
+		keymap  Keymap
+		codes   []mylib.InputCode
+		plain   string
+		shifted string
+		i       int
+		r       rune
+		s       rune
+	)
+
+	codes = []mylib.InputCode{
+		KEY_GRAVE, KEY_1, KEY_2, KEY_3, KEY_4, KEY_5, KEY_6, KEY_7, KEY_8,
+		KEY_9, KEY_0, KEY_MINUS, KEY_EQUAL,
+		KEY_Q, KEY_W, KEY_E, KEY_R, KEY_T, KEY_Y, KEY_U, KEY_I, KEY_O,
+		KEY_P, KEY_LEFTBRACE, KEY_RIGHTBRACE, KEY_BACKSLASH,
+		KEY_A, KEY_S, KEY_D, KEY_F, KEY_G, KEY_H, KEY_J, KEY_K, KEY_L,
+		KEY_SEMICOLON, KEY_APOSTROPHE,
+		KEY_Z, KEY_X, KEY_C, KEY_V, KEY_B, KEY_N, KEY_M, KEY_COMMA,
+		KEY_DOT, KEY_SLASH, KEY_SPACE,
+		KEY_KP0, KEY_KP1, KEY_KP2, KEY_KP3, KEY_KP4, KEY_KP5, KEY_KP6,
+		KEY_KP7, KEY_KP8, KEY_KP9, KEY_KPDOT, KEY_KPSLASH, KEY_KPASTERISK,
+		KEY_KPMINUS, KEY_KPPLUS,
+	}
+	plain = "`1234567890-=qwertyuiop[]\\asdfghjkl;'zxcvbnm,./ 0123456789./*-+"
+	shifted = "~!@#$%^&*()_+QWERTYUIOP{}|ASDFGHJKL:\"ZXCVBNM<>? 0123456789./*-+"
+
+	keymap = make(Keymap, len(codes))
+	for i, r = range []rune(plain) {
+		s = []rune(shifted)[i]
+		keymap[codes[i]] = [4]rune{r, s, r, s}
+	}
+
+	return keymap
+}
+
+// LoadKeymap reads a keymap from r. Each line holds a key name, such as
+// KEY_A, or a decimal key code, followed by up to four runes for the
+// plain, Shift, AltGr, and Shift+AltGr levels. A rune is written as
+// itself, or as U+XXXX for spaces and invisible characters, and "-"
+// leaves a level empty. Missing AltGr levels repeat the first two.
+// Empty lines and lines starting with '#' are ignored.
+func LoadKeymap(r io.Reader) (Keymap, error) {
+	var (
+		keymap  Keymap
+		scanner *bufio.Scanner
+		line    string
+		lineNum int
+		fields  []string
+		code    mylib.InputCode
+		levels  [4]rune
+		i       int
+		err     error
+	)
+
+	keymap = make(Keymap)
+	scanner = bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		lineNum++
+		line = strings.TrimSpace(scanner.Text())
+
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		fields = strings.Fields(line)
+		if len(fields) < 2 || len(fields) > 5 {
+			return nil, fmt.Errorf("input.LoadKeymap: %w: line %d", ErrInvalidKeymap, lineNum)
+		}
+
+		code, err = keymapCode(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("input.LoadKeymap: line %d: %w", lineNum, err)
+		}
+
+		levels = [4]rune{}
+		for i = range fields[1:] {
+			levels[i], err = keymapRune(fields[1+i])
+			if err != nil {
+				return nil, fmt.Errorf("input.LoadKeymap: line %d: %w", lineNum, err)
+			}
+		}
+
+		if len(fields) <= 3 {
+			levels[2], levels[3] = levels[0], levels[1]
+		}
+
+		keymap[code] = levels
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("input.LoadKeymap: %w", err)
+	}
+
+	return keymap, nil
+}
+
+// NewKeyboard returns a Keyboard translating keys with keymap, with no
+// modifiers held and no locks active.
+func NewKeyboard(keymap Keymap) *Keyboard {
+	return &Keyboard{
+		keymap: keymap,
+		held:   make(map[mylib.InputCode]bool),
+	}
+}
+
+// SetModifiers replaces the active modifiers, for example to seed Caps
+// Lock and Num Lock from [Device.LEDState].
+func (kb *Keyboard) SetModifiers(mods Modifiers) {
+	kb.mods = mods
+}
+
+// Modifiers returns the active modifiers.
+func (kb *Keyboard) Modifiers() Modifiers {
+	return kb.mods
+}
+
+// Handle updates the keyboard with a single event. For key presses and
+// autorepeats of keys other than modifiers, it returns the translated
+// KeyEvent and true. For all other events, it returns false.
+func (kb *Keyboard) Handle(ev Event) (KeyEvent, bool) {
+	var (
+		code mylib.InputCode
+		mod  Modifiers
+		ok   bool
+	)
+
+	if ev.Type != EV_KEY {
+		return KeyEvent{}, false
+	}
+
+	code = mylib.InputCode(ev.Code)
+
+	mod, ok = modifierKeys[code]
+	if ok {
+		kb.setHeld(code, ev.Value != 0)
+		kb.mods &^= mod
+
+		if kb.anyHeld(mod) {
+			kb.mods |= mod
+		}
+
+		return KeyEvent{}, false
+	}
+
+	if ev.Value == 0 {
+		return KeyEvent{}, false
+	}
+
+	if ev.Value == 1 {
+		switch code {
+		case KEY_CAPSLOCK:
+			kb.mods ^= ModCapsLock
+		case KEY_NUMLOCK:
+			kb.mods ^= ModNumLock
+		}
+	}
+
+	return kb.translate(code, ev.Value == 2), true
+}
+
+func (kb *Keyboard) translate(code mylib.InputCode, repeat bool) KeyEvent {
+	var (
+		key    KeyEvent
+		nav    mylib.InputCode
+		levels [4]rune
+		level  int
+		ok     bool
+	)
+
+	key = KeyEvent{Code: code, Modifiers: kb.mods, Repeat: repeat}
+
+	nav, ok = keypadNavigation[code]
+	if ok && kb.mods&ModNumLock == 0 {
+		key.Keysym = keysyms[nav]
+
+		return key
+	}
+
+	levels, ok = kb.keymap[code]
+	if !ok {
+		key.Keysym = keysyms[code]
+
+		switch code {
+		case KEY_ENTER, KEY_KPENTER:
+			key.Rune = '\r'
+		case KEY_TAB:
+			key.Rune = '\t'
+		case KEY_BACKSPACE:
+			key.Rune = '\b'
+		case KEY_ESC:
+			key.Rune = 0x1b
+		}
+
+		return key
+	}
+
+	if kb.mods&ModShift != 0 {
+		level = 1
+	}
+
+	// Caps Lock inverts Shift for letters only.
+	if kb.mods&ModCapsLock != 0 && unicode.IsLetter(levels[0]) {
+		level ^= 1
+	}
+
+	if kb.mods&ModAltGr != 0 {
+		level += 2
+	}
+
+	key.Rune = levels[level]
+
+	if kb.mods&ModCtrl != 0 && key.Rune < utf8.RuneSelf {
+		switch {
+		case key.Rune >= 'a' && key.Rune <= 'z':
+			key.Rune -= 'a' - 1
+		case key.Rune >= '@' && key.Rune <= '_':
+			key.Rune -= '@'
+		}
+	}
+
+	return key
+}
+
+func (kb *Keyboard) setHeld(code mylib.InputCode, held bool) {
+	if held {
+		kb.held[code] = true
+
+		return
+	}
+
+	delete(kb.held, code)
+}
+
+func (kb *Keyboard) anyHeld(mod Modifiers) bool {
+	var code mylib.InputCode
+
+	for code = range kb.held {
+		if modifierKeys[code] == mod {
+			return true
+		}
+	}
+
+	return false
+}
+
+func keymapCode(field string) (mylib.InputCode, error) {
+	var (
+		eventType mylib.InputEvent
+		code      mylib.InputCode
+		value     uint64
+		err       error
+	)
+
+	value, err = strconv.ParseUint(field, 10, 16)
+	if err == nil {
+		return mylib.InputCode(value), nil
+	}
+
+	eventType, code, err = CodeByName(field)
+	if err != nil {
+		return 0, err
+	}
+
+	if eventType != EV_KEY {
+		return 0, fmt.Errorf("%w: %s is not a key", ErrInvalidKeymap, field)
+	}
+
+	return code, nil
+}
+
+func keymapRune(field string) (rune, error) {
+	var (
+		value uint64
+		r     rune
+		size  int
+		err   error
+	)
+
+	if field == "-" {
+		return 0, nil
+	}
+
+	if strings.HasPrefix(field, "U+") {
+		value, err = strconv.ParseUint(field[2:], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(value)) {
+			return 0, fmt.Errorf("%w: bad rune %s", ErrInvalidKeymap, field)
+		}
+
+		return rune(value), nil
+	}
+
+	r, size = utf8.DecodeRuneInString(field)
+	if r == utf8.RuneError || size != len(field) {
+		return 0, fmt.Errorf("%w: bad rune %s", ErrInvalidKeymap, field)
+	}
+
+	return r, nil
+}
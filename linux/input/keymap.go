@@ -0,0 +1,105 @@
+//go:build linux
+
+package input
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/andrieee44/mylib"
+	"golang.org/x/sys/unix"
+)
+
+// NewKeymapEntry returns a KeymapEntry mapping the 4-byte scancode to
+// keycode, the form most drivers, including HID, accept with
+// [Device.SetKeymapEntry].
+func NewKeymapEntry(scancode uint32, keycode mylib.InputCode) KeymapEntry {
+	var entry KeymapEntry
+
+	entry.Len = 4
+	entry.Keycode = uint32(keycode)
+	binary.NativeEndian.PutUint32(entry.Scancode[:], scancode)
+
+	return entry
+}
+
+// ScancodeValue returns the scancode of entry as an integer. It returns
+// false if the scancode is longer than 8 bytes.
+func (entry KeymapEntry) ScancodeValue() (uint64, bool) {
+	switch entry.Len {
+	case 1:
+		return uint64(entry.Scancode[0]), true
+	case 2:
+		return uint64(binary.NativeEndian.Uint16(entry.Scancode[:])), true
+	case 4:
+		return uint64(binary.NativeEndian.Uint32(entry.Scancode[:])), true
+	case 8:
+		return binary.NativeEndian.Uint64(entry.Scancode[:]), true
+	default:
+		return 0, false
+	}
+}
+
+// KeymapAt returns the keymap entry at index in the device's scancode to
+// keycode table, using [EVIOCGKEYCODE_V2] with [INPUT_KEYMAP_BY_INDEX].
+// The kernel returns [unix.EINVAL] past the end of the table.
+func (dev *Device) KeymapAt(index uint16) (KeymapEntry, error) {
+	var (
+		entry KeymapEntry
+		err   error
+	)
+
+	entry.Flags = INPUT_KEYMAP_BY_INDEX
+	entry.Index = index
+
+	err = deviceIoctl(dev, EVIOCGKEYCODE_V2, &entry)
+	if err != nil {
+		return KeymapEntry{}, fmt.Errorf("Device.KeymapAt: %w", err)
+	}
+
+	return entry, nil
+}
+
+// Keymap returns the device's full scancode to keycode table, in index
+// order.
+func (dev *Device) Keymap() ([]KeymapEntry, error) {
+	var (
+		entries []KeymapEntry
+		entry   KeymapEntry
+		index   uint16
+		err     error
+	)
+
+	for index = 0; ; index++ {
+		entry, err = dev.KeymapAt(index)
+		if errors.Is(err, unix.EINVAL) {
+			return entries, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Device.Keymap: %w", err)
+		}
+
+		entries = append(entries, entry)
+
+		if index == ^uint16(0) {
+			return entries, nil
+		}
+	}
+}
+
+// SetKeymapEntry changes the keycode of a scancode with
+// [EVIOCSKEYCODE_V2]. The entry is looked up by Scancode, or by Index if
+// Flags includes [INPUT_KEYMAP_BY_INDEX]. The change lasts until the
+// driver is reloaded.
+func (dev *Device) SetKeymapEntry(entry KeymapEntry) error {
+	var err error
+
+	err = deviceIoctl(dev, EVIOCSKEYCODE_V2, &entry)
+	if err != nil {
+		return fmt.Errorf("Device.SetKeymapEntry: %w", err)
+	}
+
+	return nil
+}
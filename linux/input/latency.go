@@ -0,0 +1,138 @@
+//go:build linux
+
+package input
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// LatencyStats summarizes how long events took to travel from the
+// kernel, which stamps them, to [Device.ReadEvent].
+type LatencyStats struct {
+	// Count is the number of events the statistics cover.
+	Count int
+
+	// Min is the lowest latency seen.
+	Min time.Duration
+
+	// Max is the highest latency seen.
+	Max time.Duration
+
+	// Mean is the average latency.
+	Mean time.Duration
+
+	// P50 is the median latency.
+	P50 time.Duration
+
+	// P95 is the latency that 95% of the events did not exceed.
+	P95 time.Duration
+
+	// P99 is the latency that 99% of the events did not exceed.
+	P99 time.Duration
+}
+
+// latencyRecorder keeps the latest latency samples of a device in a
+// ring buffer.
+type latencyRecorder struct {
+	mutex   sync.Mutex
+	samples []time.Duration
+	next    int
+	full    bool
+}
+
+// MeasureLatency records, for each event read by [Device.ReadEvent], the
+// time elapsed since the kernel stamped it, keeping the latest samples
+// of them for [Device.Latency]. The latency includes the time the event
+// spent queued, so it grows when the reader falls behind.
+func MeasureLatency(samples int) Option {
+	return func(options *openOptions) {
+		options.latencySamples = max(samples, 1)
+	}
+}
+
+// Latency returns statistics over the latency samples recorded since the
+// device was opened or [Device.ResetLatency] was called. It returns the
+// zero LatencyStats unless the device was opened with [MeasureLatency].
+func (dev *Device) Latency() LatencyStats {
+	if dev.latency == nil {
+		return LatencyStats{}
+	}
+
+	return dev.latency.stats()
+}
+
+// ResetLatency discards the latency samples recorded so far.
+func (dev *Device) ResetLatency() {
+	if dev.latency == nil {
+		return
+	}
+
+	dev.latency.mutex.Lock()
+	defer dev.latency.mutex.Unlock()
+
+	dev.latency.next = 0
+	dev.latency.full = false
+}
+
+func newLatencyRecorder(samples int) *latencyRecorder {
+	return &latencyRecorder{samples: make([]time.Duration, samples)}
+}
+
+func (recorder *latencyRecorder) record(latency time.Duration) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.samples[recorder.next] = latency
+
+	recorder.next++
+	if recorder.next == len(recorder.samples) {
+		recorder.next = 0
+		recorder.full = true
+	}
+}
+
+func (recorder *latencyRecorder) stats() LatencyStats {
+	var (
+		samples []time.Duration
+		sample  time.Duration
+		total   time.Duration
+	)
+
+	recorder.mutex.Lock()
+
+	if recorder.full {
+		samples = slices.Clone(recorder.samples)
+	} else {
+		samples = slices.Clone(recorder.samples[:recorder.next])
+	}
+
+	recorder.mutex.Unlock()
+
+	if len(samples) == 0 {
+		return LatencyStats{}
+	}
+
+	slices.Sort(samples)
+
+	for _, sample = range samples {
+		total += sample
+	}
+
+	return LatencyStats{
+		Count: len(samples),
+		Min:   samples[0],
+		Max:   samples[len(samples)-1],
+		Mean:  total / time.Duration(len(samples)),
+		P50:   percentile(samples, 50),
+		P95:   percentile(samples, 95),
+		P99:   percentile(samples, 99),
+	}
+}
+
+// percentile returns the nearest-rank percentile p of the sorted
+// samples.
+func percentile(samples []time.Duration, p int) time.Duration {
+	return samples[max((len(samples)*p+99)/100-1, 0)]
+}
@@ -0,0 +1,167 @@
+//go:build linux
+
+package input
+
+import (
+	"slices"
+
+	"github.com/andrieee44/mylib"
+)
+
+// Layer is a key code map that is active while its layer key is held or
+// toggled on. Codes missing from Map are transparent: they fall through
+// to the next active layer below, and finally pass through unchanged.
+// Mapping a code to [KEY_RESERVED] swallows the key.
+type Layer struct {
+	// Map translates physical key codes to emitted key codes.
+	Map map[mylib.InputCode]mylib.InputCode
+
+	// Momentary lists keys that activate the layer while held.
+	Momentary []mylib.InputCode
+
+	// Toggle lists keys that switch the layer on and off when pressed.
+	Toggle []mylib.InputCode
+}
+
+// LayerFilter is a [Filter] bringing QMK-style layers to any keyboard.
+// Higher layers take precedence over lower ones. Layer keys are consumed
+// and never emitted.
+//
+// A key is released with the code it was pressed with, even if the
+// active layers change while it is held.
+type LayerFilter struct {
+	layers  []Layer
+	held    []int
+	toggled []bool
+	pressed map[mylib.InputCode]mylib.InputCode
+	out     []Event
+}
+
+var _ Filter = (*LayerFilter)(nil)
+
+// NewLayerFilter returns a LayerFilter with the given layers, all
+// initially inactive.
+func NewLayerFilter(layers ...Layer) *LayerFilter {
+	return &LayerFilter{
+		layers:  layers,
+		held:    make([]int, len(layers)),
+		toggled: make([]bool, len(layers)),
+		pressed: make(map[mylib.InputCode]mylib.InputCode),
+	}
+}
+
+// Active reports whether the layer at index is active.
+func (filter *LayerFilter) Active(index int) bool {
+	return filter.held[index] > 0 || filter.toggled[index]
+}
+
+// Filter translates the key events of frame through the active layers.
+func (filter *LayerFilter) Filter(frame []Event) []Event {
+	var (
+		ev   Event
+		code mylib.InputCode
+		ok   bool
+	)
+
+	filter.out = filter.out[:0]
+
+	for _, ev = range frame {
+		if ev.Type != EV_KEY {
+			filter.out = append(filter.out, ev)
+
+			continue
+		}
+
+		if filter.layerKey(ev) {
+			continue
+		}
+
+		code, ok = filter.translate(ev)
+		if !ok || code == KEY_RESERVED {
+			continue
+		}
+
+		ev.Code = uint16(code)
+		filter.out = append(filter.out, ev)
+	}
+
+	return filter.out
+}
+
+func (filter *LayerFilter) layerKey(ev Event) bool {
+	var (
+		code     mylib.InputCode
+		index    int
+		layer    Layer
+		consumed bool
+	)
+
+	code = mylib.InputCode(ev.Code)
+
+	for index, layer = range filter.layers {
+		if slices.Contains(layer.Momentary, code) {
+			consumed = true
+
+			switch ev.Value {
+			case 1:
+				filter.held[index]++
+			case 0:
+				filter.held[index] = max(filter.held[index]-1, 0)
+			}
+		}
+
+		if slices.Contains(layer.Toggle, code) {
+			consumed = true
+
+			if ev.Value == 1 {
+				filter.toggled[index] = !filter.toggled[index]
+			}
+		}
+	}
+
+	return consumed
+}
+
+func (filter *LayerFilter) translate(ev Event) (mylib.InputCode, bool) {
+	var (
+		physical, code mylib.InputCode
+		ok             bool
+	)
+
+	physical = mylib.InputCode(ev.Code)
+
+	if ev.Value != 1 {
+		code, ok = filter.pressed[physical]
+		if ev.Value == 0 {
+			delete(filter.pressed, physical)
+		}
+
+		return code, ok
+	}
+
+	code = filter.lookup(physical)
+	filter.pressed[physical] = code
+
+	return code, true
+}
+
+func (filter *LayerFilter) lookup(physical mylib.InputCode) mylib.InputCode {
+	var (
+		index int
+		code  mylib.InputCode
+		ok    bool
+	)
+
+	for index = len(filter.layers) - 1; index >= 0; index-- {
+		if !filter.Active(index) {
+			continue
+		}
+
+		code, ok = filter.layers[index].Map[physical]
+		if ok {
+			return code
+		}
+	}
+
+	return physical
+}
@@ -0,0 +1,176 @@
+//go:build linux
+
+package input_test
+
+import (
+	"testing"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+// testLayers returns a navigation layer held with Right Alt below a
+// layer toggled with Scroll Lock.
+func testLayers() []input.Layer {
+	return []input.Layer{
+		{
+			Map: map[mylib.InputCode]mylib.InputCode{
+				input.KEY_H: input.KEY_LEFT,
+				input.KEY_J: input.KEY_DOWN,
+				input.KEY_K: input.KEY_RESERVED,
+			},
+			Momentary: []mylib.InputCode{input.KEY_RIGHTALT},
+		},
+		{
+			Map:    map[mylib.InputCode]mylib.InputCode{input.KEY_H: input.KEY_HOME},
+			Toggle: []mylib.InputCode{input.KEY_SCROLLLOCK},
+		},
+	}
+}
+
+func TestLayerFilter(t *testing.T) {
+	var (
+		tests []filterTest
+		test  filterTest
+	)
+
+	tests = []filterTest{
+		{
+			name: "inactive",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_H, 1)),
+				frame(key(1, input.KEY_H, 0)),
+			},
+			want: []input.Event{
+				key(0, input.KEY_H, 1), syn(0),
+				key(1, input.KEY_H, 0), syn(1),
+			},
+		},
+		{
+			name: "momentary",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_RIGHTALT, 1)),
+				frame(key(1, input.KEY_H, 1)),
+				frame(key(2, input.KEY_H, 2)),
+				frame(key(3, input.KEY_H, 0)),
+				frame(key(4, input.KEY_RIGHTALT, 0)),
+				frame(key(5, input.KEY_H, 1)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(1, input.KEY_LEFT, 1), syn(1),
+				key(2, input.KEY_LEFT, 2), syn(2),
+				key(3, input.KEY_LEFT, 0), syn(3),
+				syn(4),
+				key(5, input.KEY_H, 1), syn(5),
+			},
+		},
+		{
+			name: "released as pressed",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_RIGHTALT, 1)),
+				frame(key(1, input.KEY_H, 1)),
+				frame(key(2, input.KEY_RIGHTALT, 0)),
+				frame(key(3, input.KEY_H, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(1, input.KEY_LEFT, 1), syn(1),
+				syn(2),
+				key(3, input.KEY_LEFT, 0), syn(3),
+			},
+		},
+		{
+			name: "pressed before the layer",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_H, 1)),
+				frame(key(1, input.KEY_RIGHTALT, 1)),
+				frame(key(2, input.KEY_H, 0)),
+			},
+			want: []input.Event{
+				key(0, input.KEY_H, 1), syn(0),
+				syn(1),
+				key(2, input.KEY_H, 0), syn(2),
+			},
+		},
+		{
+			name: "swallowed",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_RIGHTALT, 1)),
+				frame(key(1, input.KEY_K, 1)),
+				frame(key(2, input.KEY_K, 0)),
+			},
+			want: []input.Event{syn(0), syn(1), syn(2)},
+		},
+		{
+			name: "toggle",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_SCROLLLOCK, 1)),
+				frame(key(1, input.KEY_SCROLLLOCK, 0)),
+				frame(key(2, input.KEY_H, 1), key(2, input.KEY_H, 0)),
+				frame(key(3, input.KEY_SCROLLLOCK, 1)),
+				frame(key(4, input.KEY_H, 1)),
+			},
+			want: []input.Event{
+				syn(0),
+				syn(1),
+				key(2, input.KEY_HOME, 1), key(2, input.KEY_HOME, 0), syn(2),
+				syn(3),
+				key(4, input.KEY_H, 1), syn(4),
+			},
+		},
+		{
+			name: "higher layers first, then transparent",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_RIGHTALT, 1)),
+				frame(key(1, input.KEY_SCROLLLOCK, 1)),
+				frame(key(2, input.KEY_H, 1), key(2, input.KEY_J, 1), key(2, input.KEY_L, 1)),
+			},
+			want: []input.Event{
+				syn(0),
+				syn(1),
+				key(2, input.KEY_HOME, 1), key(2, input.KEY_DOWN, 1), key(2, input.KEY_L, 1),
+				syn(2),
+			},
+		},
+		{
+			name: "other events pass",
+			frames: [][]input.Event{
+				frame(at(0, input.EV_MSC, input.MSC_SCAN, 0x70023), key(0, input.KEY_RIGHTALT, 1)),
+				{at(1, input.EV_SYN, input.SYN_DROPPED, 0)},
+			},
+			want: []input.Event{
+				at(0, input.EV_MSC, input.MSC_SCAN, 0x70023), syn(0),
+				at(1, input.EV_SYN, input.SYN_DROPPED, 0),
+			},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkEvents(t, filterFrames(input.NewLayerFilter(testLayers()...), test.frames, false), test.want)
+		})
+	}
+}
+
+func TestLayerFilterActive(t *testing.T) {
+	var filter *input.LayerFilter
+
+	filter = input.NewLayerFilter(testLayers()...)
+
+	// A release without a press, as when the layer key was held before
+	// the filter started, does not leave the layer stuck off.
+	filter.Filter(frame(key(0, input.KEY_RIGHTALT, 0)))
+	filter.Filter(frame(key(1, input.KEY_RIGHTALT, 1)))
+
+	if !filter.Active(0) || filter.Active(1) {
+		t.Errorf("Active = %t, %t, want true, false", filter.Active(0), filter.Active(1))
+	}
+
+	filter.Filter(frame(key(2, input.KEY_RIGHTALT, 0)))
+	filter.Filter(frame(key(3, input.KEY_SCROLLLOCK, 1)))
+
+	if filter.Active(0) || !filter.Active(1) {
+		t.Errorf("Active = %t, %t, want false, true", filter.Active(0), filter.Active(1))
+	}
+}
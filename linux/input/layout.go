@@ -0,0 +1,186 @@
+//go:build linux
+
+package input
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/xdg"
+)
+
+// LayoutNotifier tracks the active keyboard layout by watching for
+// [KEY_KBD_LAYOUT_NEXT] and user-defined group-switch hotkeys, such as
+// Alt+Shift. It is meant for status bars in minimal window managers that
+// have no other way to learn about layout changes.
+//
+// The current layout index is published on a channel and, optionally,
+// written to a file in the XDG state directory. [LayoutNotifier.Layout]
+// and [LayoutNotifier.Changes] may be used while another goroutine runs
+// [LayoutNotifier.Run].
+type LayoutNotifier struct {
+	layouts   int
+	index     atomic.Int32
+	statePath string
+	hotkeys   [][]mylib.InputCode
+	pressed   map[mylib.InputCode]bool
+	changes   chan int
+}
+
+// NewLayoutNotifier returns a LayoutNotifier cycling through the given
+// number of layouts, starting at index 0. Each hotkey is a chord of key
+// codes that advances to the next layout when all of its keys are held.
+// If statePath is non-empty, the current index is written to that path
+// relative to the XDG state directory on every change.
+func NewLayoutNotifier(
+	layouts int,
+	statePath string,
+	hotkeys ...[]mylib.InputCode,
+) *LayoutNotifier {
+	return &LayoutNotifier{
+		layouts:   layouts,
+		statePath: statePath,
+		hotkeys:   hotkeys,
+		pressed:   make(map[mylib.InputCode]bool),
+		changes:   make(chan int, 1),
+	}
+}
+
+// Changes returns a channel receiving the layout index after every
+// change. It only ever holds the latest index, so slow receivers skip
+// intermediate layouts instead of blocking the notifier.
+func (notifier *LayoutNotifier) Changes() <-chan int {
+	return notifier.changes
+}
+
+// Layout returns the current layout index.
+func (notifier *LayoutNotifier) Layout() int {
+	return int(notifier.index.Load())
+}
+
+// Handle updates the layout state with a single event. Events other than
+// key presses and releases are ignored.
+func (notifier *LayoutNotifier) Handle(ev Event) error {
+	var (
+		code   mylib.InputCode
+		hotkey []mylib.InputCode
+	)
+
+	if ev.Type != EV_KEY || ev.Value == 2 {
+		return nil
+	}
+
+	code = mylib.InputCode(ev.Code)
+
+	if ev.Value == 0 {
+		delete(notifier.pressed, code)
+
+		return nil
+	}
+
+	notifier.pressed[code] = true
+
+	if code == KEY_KBD_LAYOUT_NEXT {
+		return notifier.next()
+	}
+
+	for _, hotkey = range notifier.hotkeys {
+		if slices.Contains(hotkey, code) && notifier.held(hotkey) {
+			return notifier.next()
+		}
+	}
+
+	return nil
+}
+
+// Run reads events from dev and handles them until reading fails.
+func (notifier *LayoutNotifier) Run(dev *Device) error {
+	var (
+		ev  Event
+		err error
+	)
+
+	for {
+		ev, err = dev.ReadEvent()
+		if err != nil {
+			return fmt.Errorf("LayoutNotifier.Run: %w", err)
+		}
+
+		err = notifier.Handle(ev)
+		if err != nil {
+			return fmt.Errorf("LayoutNotifier.Run: %w", err)
+		}
+	}
+}
+
+func (notifier *LayoutNotifier) held(hotkey []mylib.InputCode) bool {
+	var code mylib.InputCode
+
+	for _, code = range hotkey {
+		if !notifier.pressed[code] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (notifier *LayoutNotifier) next() error {
+	var (
+		index int
+		err   error
+	)
+
+	index = (int(notifier.index.Load()) + 1) % max(notifier.layouts, 1)
+	notifier.index.Store(int32(index))
+
+	select {
+	case <-notifier.changes:
+	default:
+	}
+
+	notifier.changes <- index
+
+	if notifier.statePath == "" {
+		return nil
+	}
+
+	err = writeState(notifier.statePath, strconv.Itoa(index)+"\n")
+	if err != nil {
+		return fmt.Errorf("LayoutNotifier.next: %w", err)
+	}
+
+	return nil
+}
+
+func writeState(relPath, content string) error {
+	var (
+		file *os.File
+		err  error
+	)
+
+	file, err = xdg.StateFile(relPath)
+	if err != nil {
+		return err
+	}
+
+	err = file.Truncate(0)
+	if err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	_, err = file.WriteString(content)
+	if err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	return file.Close()
+}
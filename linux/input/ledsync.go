@@ -0,0 +1,153 @@
+//go:build linux
+
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/andrieee44/mylib"
+)
+
+// lockLEDs maps the lock keys to the LEDs they toggle.
+var lockLEDs map[mylib.InputCode]mylib.InputCode = map[mylib.InputCode]mylib.InputCode{
+	KEY_CAPSLOCK:   LED_CAPSL,
+	KEY_NUMLOCK:    LED_NUML,
+	KEY_SCROLLLOCK: LED_SCROLLL,
+}
+
+// LEDSync mirrors the caps, num, and scroll lock LEDs across keyboards,
+// so that toggling caps lock on one lights it on all of them. It watches
+// the [EV_LED] events the kernel reports when an LED changes, such as
+// when the display server updates it, and writes the same state to every
+// other keyboard.
+type LEDSync struct {
+	// Toggle makes the lock keys themselves toggle the LEDs, for
+	// consoles and kiosks where nothing else manages them.
+	Toggle bool
+
+	devs []*Device
+	lit  map[mylib.InputCode]bool
+}
+
+// NewLEDSync returns an LEDSync for keyboards. The LEDs currently lit on
+// the first keyboard are copied to the others.
+func NewLEDSync(keyboards ...*Device) (*LEDSync, error) {
+	var (
+		ledSync *LEDSync
+		leds    []mylib.InputCode
+		led     mylib.InputCode
+		err     error
+	)
+
+	ledSync = &LEDSync{
+		devs: keyboards,
+		lit:  make(map[mylib.InputCode]bool),
+	}
+
+	if len(keyboards) == 0 {
+		return ledSync, nil
+	}
+
+	leds, err = keyboards[0].LEDState()
+	if err != nil {
+		return nil, fmt.Errorf("input.NewLEDSync: %w", err)
+	}
+
+	for _, led = range lockLEDs {
+		err = ledSync.set(led, slices.Contains(leds, led))
+		if err != nil {
+			return nil, fmt.Errorf("input.NewLEDSync: %w", err)
+		}
+	}
+
+	return ledSync, nil
+}
+
+// Run mirrors LED changes until ctx is done, in which case it returns
+// ctx.Err(), or until every keyboard has failed, in which case it
+// returns their errors joined. The keyboards should be opened with
+// [NonBlocking]; see [Merge].
+func (ledSync *LEDSync) Run(ctx context.Context) error {
+	var (
+		merged MergedEvent
+		led    mylib.InputCode
+		ok     bool
+		errs   []error
+		err    error
+	)
+
+	for merged = range Merge(ctx, ledSync.devs...) {
+		if merged.Err != nil {
+			errs = append(errs, merged.Err)
+
+			continue
+		}
+
+		switch merged.Event.Type {
+		case EV_LED:
+			led = mylib.InputCode(merged.Event.Code)
+			if led != LED_CAPSL && led != LED_NUML && led != LED_SCROLLL {
+				continue
+			}
+
+			err = ledSync.set(led, merged.Event.Value != 0)
+		case EV_KEY:
+			led, ok = lockLEDs[mylib.InputCode(merged.Event.Code)]
+			if !ledSync.Toggle || !ok || merged.Event.Value != 1 {
+				continue
+			}
+
+			err = ledSync.set(led, !ledSync.lit[led])
+		}
+
+		if err != nil {
+			return fmt.Errorf("LEDSync.Run: %w", err)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return fmt.Errorf("LEDSync.Run: %w", ctx.Err())
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf("LEDSync.Run: %w", err)
+	}
+
+	return nil
+}
+
+// set lights or turns off led on every keyboard if its state changed.
+// The kernel ignores writes that do not change an LED, so the events
+// echoed back by the other keyboards end here.
+func (ledSync *LEDSync) set(led mylib.InputCode, on bool) error {
+	var (
+		lit bool
+		ok  bool
+		dev *Device
+		err error
+	)
+
+	lit, ok = ledSync.lit[led]
+	if ok && lit == on {
+		return nil
+	}
+
+	ledSync.lit[led] = on
+
+	for _, dev = range ledSync.devs {
+		err = dev.SetLED(led, on)
+		if err == nil {
+			err = dev.WriteEvent(Event{Type: EV_SYN, Code: SYN_REPORT})
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
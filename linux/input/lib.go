@@ -4,17 +4,36 @@ package input
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/andrieee44/mylib"
 )
 
+//go:generate go run gen_names.go
+
 // ErrInvalidEventType is returned when an unsupported or unrecognized
 // event type is passed to a Device method.
 var ErrInvalidEventType error = errors.New("invalid event type")
 
-// TestBit returns true if the bit numbered pos is set in b.
+// ErrInvalidEventCode is returned when an event code is out of range for
+// its event type.
+var ErrInvalidEventCode error = errors.New("invalid event code")
+
+// ErrInvalidProperty is returned when decoding an unknown INPUT_PROP_*
+// name.
+var ErrInvalidProperty error = errors.New("invalid input property")
+
+func init() {
+	mylib.RegisterNamer(kernelNamer{})
+}
+
+// TestBit returns true if the bit numbered pos is set in b. A pos
+// beyond the end of b is reported as unset.
+//
+// Deprecated: Use [Bitmask], which does not depend on the byte order of
+// the kernel's bitmaps.
 func TestBit(b []byte, pos uint) bool {
-	return b[pos/8]&(1<<(pos%8)) != 0
+	return pos/8 < uint(len(b)) && b[pos/8]&(1<<(pos%8)) != 0
 }
 
 // MaxCodes returns the highest valid code for the specified eventType.
@@ -47,3 +66,81 @@ func MaxCodes(eventType mylib.InputEvent) (uint, bool) {
 
 	return maxCode, ok
 }
+
+// SetBit sets the bit numbered pos in b.
+//
+// Deprecated: Use [Bitmask.Set].
+func SetBit(b []byte, pos uint) {
+	b[pos/8] |= 1 << (pos % 8)
+}
+
+// TypeName returns the EV_* name of eventType, such as "EV_KEY", or an
+// empty string if eventType is unknown.
+func TypeName(eventType mylib.InputEvent) string {
+	return eventNames[eventType]
+}
+
+// CodeName returns the name of code within eventType, such as "KEY_A"
+// for [EV_KEY] or "REL_X" for [EV_REL], or an empty string if either is
+// unknown. Where several names share a code, the most specific one is
+// returned, for example "BTN_SOUTH" rather than "BTN_GAMEPAD".
+func CodeName(eventType mylib.InputEvent, code mylib.InputCode) string {
+	return codeNames[eventType][code]
+}
+
+// KeyName returns the KEY_* or BTN_* name of code, or an empty string if
+// code is unknown. It is shorthand for CodeName(EV_KEY, code).
+func KeyName(code mylib.InputCode) string {
+	return codeNames[EV_KEY][code]
+}
+
+// TypeByName returns the event type named name, such as "EV_KEY". It
+// returns [ErrInvalidEventType] if name is unknown.
+func TypeByName(name string) (mylib.InputEvent, error) {
+	var (
+		eventType mylib.InputEvent
+		ok        bool
+	)
+
+	eventType, ok = eventValues[name]
+	if !ok {
+		return 0, fmt.Errorf("input.TypeByName: %w %q", ErrInvalidEventType, name)
+	}
+
+	return eventType, nil
+}
+
+// CodeByName returns the event code named name, such as "KEY_LEFTCTRL",
+// along with the event type its prefix belongs to. Aliases such as
+// "BTN_A" and "KEY_SCREENLOCK" are accepted. It returns
+// [ErrInvalidEventCode] if name is unknown.
+func CodeByName(name string) (eventType mylib.InputEvent, code mylib.InputCode, err error) {
+	var (
+		value eventCode
+		ok    bool
+	)
+
+	value, ok = codeValues[name]
+	if !ok {
+		return 0, 0, fmt.Errorf("input.CodeByName: %w %q", ErrInvalidEventCode, name)
+	}
+
+	return value.eventType, value.code, nil
+}
+
+type eventCode struct {
+	eventType mylib.InputEvent
+	code      mylib.InputCode
+}
+
+// kernelNamer is the [mylib.Namer] of the Linux backend, naming events
+// like the kernel headers.
+type kernelNamer struct{}
+
+func (kernelNamer) EventName(eventType mylib.InputEvent) string {
+	return TypeName(eventType)
+}
+
+func (kernelNamer) CodeName(eventType mylib.InputEvent, code mylib.InputCode) string {
+	return CodeName(eventType, code)
+}
@@ -0,0 +1,144 @@
+//go:build linux
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotEventDevice is returned when a link does not resolve to an
+// event device node in /dev/input.
+var ErrNotEventDevice error = errors.New("not an event device")
+
+// NewDeviceByID opens the device linked as name in /dev/input/by-id, such
+// as "usb-Logitech_USB_Receiver-event-kbd". Unlike eventN numbers, these
+// names are stable across reboots and hotplug.
+func NewDeviceByID(name string) (*Device, error) {
+	var (
+		device *Device
+		err    error
+	)
+
+	device, err = newDeviceByLink("/dev/input/by-id", name)
+	if err != nil {
+		return nil, fmt.Errorf("input.NewDeviceByID: %w", err)
+	}
+
+	return device, nil
+}
+
+// NewDeviceByPath opens the device linked as name in /dev/input/by-path,
+// such as "pci-0000:00:14.0-usb-0:2:1.0-event-kbd". These names are
+// stable as long as the device stays on the same port.
+func NewDeviceByPath(name string) (*Device, error) {
+	var (
+		device *Device
+		err    error
+	)
+
+	device, err = newDeviceByLink("/dev/input/by-path", name)
+	if err != nil {
+		return nil, fmt.Errorf("input.NewDeviceByPath: %w", err)
+	}
+
+	return device, nil
+}
+
+// ResolveLink follows the symlink at path, such as
+// "/dev/input/by-id/usb-Logitech_USB_Receiver-event-kbd", and returns the
+// event device node it points to, such as "/dev/input/event3". It returns
+// [ErrNotEventDevice] if the link points elsewhere, for example to a
+// legacy mouse or joystick node. It uses NewSystem(nil).
+func ResolveLink(path string) (string, error) {
+	return NewSystem(nil).ResolveLink(path)
+}
+
+// StableLinks returns the /dev/input/by-id and /dev/input/by-path links
+// that resolve to the event device node at path, such as
+// "/dev/input/event3", using NewSystem(nil). It returns an empty slice if
+// udev created none.
+func StableLinks(path string) ([]string, error) {
+	return NewSystem(nil).StableLinks(path)
+}
+
+// ResolveLink is like [ResolveLink], but follows the link in the tree of
+// sys.
+func (sys *System) ResolveLink(path string) (string, error) {
+	var (
+		target string
+		err    error
+	)
+
+	target, err = sys.resolve(strings.TrimPrefix(filepath.Clean(path), "/"))
+	if err != nil {
+		return "", fmt.Errorf("System.ResolveLink: %w", err)
+	}
+
+	target = "/" + target
+
+	if filepath.Dir(target) != "/dev/input" ||
+		!strings.HasPrefix(filepath.Base(target), "event") {
+		return "", fmt.Errorf("System.ResolveLink: %w: %s", ErrNotEventDevice, target)
+	}
+
+	return target, nil
+}
+
+// StableLinks is like [StableLinks], but searches the tree of sys.
+func (sys *System) StableLinks(path string) ([]string, error) {
+	var (
+		links, matches []string
+		dir, link      string
+		target         string
+		err            error
+	)
+
+	path = filepath.Clean(path)
+
+	for _, dir = range []string{"dev/input/by-id", "dev/input/by-path"} {
+		matches, err = fs.Glob(sys.fsys, dir+"/*")
+		if err != nil {
+			return nil, fmt.Errorf("System.StableLinks: %w", err)
+		}
+
+		for _, link = range matches {
+			target, err = sys.resolve(link)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("System.StableLinks: %w", err)
+			}
+
+			if "/"+target == path {
+				links = append(links, "/"+link)
+			}
+		}
+	}
+
+	return links, nil
+}
+
+func newDeviceByLink(dir, name string) (*Device, error) {
+	var (
+		path string
+		err  error
+	)
+
+	if name != filepath.Base(name) {
+		return nil, fmt.Errorf("%w: %s", os.ErrInvalid, name)
+	}
+
+	path, err = ResolveLink(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+
+	return NewDevice(path)
+}
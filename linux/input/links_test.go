@@ -0,0 +1,151 @@
+//go:build linux
+
+package input_test
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+type resolveLinkTest struct {
+	name     string
+	path     string
+	want     string
+	notEvent bool
+	notExist bool
+}
+
+// requireSymlinks skips the test if [fstest.MapFS] cannot read symbolic
+// links, as before Go 1.25.
+func requireSymlinks(t *testing.T) {
+	var ok bool
+
+	t.Helper()
+
+	_, ok = fs.FS(fstest.MapFS{}).(interface {
+		ReadLink(name string) (string, error)
+	})
+	if !ok {
+		t.Skip("fstest.MapFS cannot read symbolic links")
+	}
+}
+
+// symlink returns a MapFS entry for a symbolic link to target.
+func symlink(target string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(target), Mode: fs.ModeSymlink | 0o777}
+}
+
+// linkTree returns a /dev/input with udev's stable links.
+func linkTree() fstest.MapFS {
+	return fstest.MapFS{
+		"dev/input/event3":                   {Mode: fs.ModeDevice},
+		"dev/input/event4":                   {Mode: fs.ModeDevice},
+		"dev/input/mouse0":                   {Mode: fs.ModeDevice},
+		"dev/input/by-id/usb-Kbd-event-kbd":  symlink("../event3"),
+		"dev/input/by-id/usb-Kbd-mouse":      symlink("../mouse0"),
+		"dev/input/by-id/usb-Gone-event-kbd": symlink("../event9"),
+		"dev/input/by-id/usb-Abs-event-kbd":  symlink("/dev/input/event3"),
+		"dev/input/by-id/usb-Hop-event-kbd":  symlink("usb-Kbd-event-kbd"),
+		"dev/input/by-id/loop":               symlink("loop"),
+		"dev/input/by-path/pci-0-event-kbd":  symlink("../event3"),
+		"dev/input/by-path/pci-1-event-kbd":  symlink("../event4"),
+	}
+}
+
+func TestSystemResolveLink(t *testing.T) {
+	var (
+		sys   *input.System
+		tests []resolveLinkTest
+		test  resolveLinkTest
+		node  string
+		err   error
+	)
+
+	requireSymlinks(t)
+
+	sys = input.NewSystem(linkTree())
+	tests = []resolveLinkTest{
+		{name: "relative", path: "/dev/input/by-id/usb-Kbd-event-kbd", want: "/dev/input/event3"},
+		{name: "absolute", path: "/dev/input/by-id/usb-Abs-event-kbd", want: "/dev/input/event3"},
+		{name: "chained", path: "/dev/input/by-id/usb-Hop-event-kbd", want: "/dev/input/event3"},
+		{name: "by-path", path: "/dev/input/by-path/pci-1-event-kbd", want: "/dev/input/event4"},
+		{name: "unclean", path: "/dev/input/by-id/../by-id//usb-Kbd-event-kbd", want: "/dev/input/event3"},
+		{name: "node", path: "/dev/input/event4", want: "/dev/input/event4"},
+		{name: "legacy node", path: "/dev/input/by-id/usb-Kbd-mouse", notEvent: true},
+		{name: "dangling", path: "/dev/input/by-id/usb-Gone-event-kbd", notExist: true},
+		{name: "missing", path: "/dev/input/by-id/usb-None-event-kbd", notExist: true},
+		{name: "loop", path: "/dev/input/by-id/loop"},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node, err = sys.ResolveLink(test.path)
+			if test.want != "" {
+				if err != nil || node != test.want {
+					t.Errorf("ResolveLink = %q, %v, want %q", node, err, test.want)
+				}
+
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("ResolveLink = %q, want an error", node)
+			}
+
+			if errors.Is(err, input.ErrNotEventDevice) != test.notEvent {
+				t.Errorf("ResolveLink = %v, want ErrNotEventDevice %t", err, test.notEvent)
+			}
+
+			if errors.Is(err, fs.ErrNotExist) != test.notExist {
+				t.Errorf("ResolveLink = %v, want fs.ErrNotExist %t", err, test.notExist)
+			}
+		})
+	}
+}
+
+func TestSystemStableLinks(t *testing.T) {
+	var (
+		fsys  fstest.MapFS
+		sys   *input.System
+		links []string
+		want  []string
+		err   error
+	)
+
+	requireSymlinks(t)
+
+	fsys = linkTree()
+	delete(fsys, "dev/input/by-id/loop")
+	sys = input.NewSystem(fsys)
+
+	// The dangling link is skipped.
+	links, err = sys.StableLinks("/dev/input/event3")
+	if err != nil {
+		t.Fatalf("StableLinks: %v", err)
+	}
+
+	want = []string{
+		"/dev/input/by-id/usb-Abs-event-kbd",
+		"/dev/input/by-id/usb-Hop-event-kbd",
+		"/dev/input/by-id/usb-Kbd-event-kbd",
+		"/dev/input/by-path/pci-0-event-kbd",
+	}
+	if !slices.Equal(links, want) {
+		t.Errorf("StableLinks = %q, want %q", links, want)
+	}
+
+	links, err = sys.StableLinks("/dev/input/event5")
+	if err != nil || len(links) != 0 {
+		t.Errorf("StableLinks = %q, %v, want none", links, err)
+	}
+
+	_, err = input.NewSystem(linkTree()).StableLinks("/dev/input/event3")
+	if err == nil {
+		t.Error("StableLinks succeeded with a link loop")
+	}
+}
@@ -0,0 +1,145 @@
+//go:build linux
+
+package input
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DeviceRef identifies the device a merged event came from.
+type DeviceRef struct {
+	// Index is the position of the device in the arguments to [Merge].
+	Index int
+
+	// Device is the device itself.
+	Device *Device
+}
+
+// MergedEvent is an event read by [Merge], tagged with its device.
+type MergedEvent struct {
+	// Ref is the device the event came from.
+	Ref DeviceRef
+
+	// Event is the event, or the zero Event if Err is set.
+	Event Event
+
+	// Err is set when reading the device failed, for example because it
+	// was unplugged. No more events follow from that device.
+	Err error
+}
+
+// Merge reads events from every device concurrently and delivers them on
+// a single channel, so that applications treating all keyboards or all
+// gamepads as one input source do not have to multiplex them. The events
+// of each device arrive in order, and the frames of different devices
+// are never interleaved. The channel is closed once ctx is done or every
+// device has failed.
+//
+// Reads from devices opened with [NonBlocking] stop as soon as ctx is
+// done, and the devices may be read again once the channel is closed. A
+// blocking device is read until its next event arrives, which is then
+// discarded.
+func Merge(ctx context.Context, devs ...*Device) <-chan MergedEvent {
+	var (
+		events chan MergedEvent
+		mutex  *sync.Mutex
+		group  sync.WaitGroup
+		index  int
+		dev    *Device
+	)
+
+	events = make(chan MergedEvent)
+	mutex = &sync.Mutex{}
+
+	for index, dev = range devs {
+		group.Add(1)
+
+		go func(ref DeviceRef) {
+			defer group.Done()
+			mergeDevice(ctx, ref, events, mutex)
+		}(DeviceRef{Index: index, Device: dev})
+	}
+
+	go func() {
+		group.Wait()
+		close(events)
+	}()
+
+	return events
+}
+
+// mergeDevice reads the device of ref a frame at a time and sends each
+// frame to events while holding mutex, so that frames stay whole.
+func mergeDevice(ctx context.Context, ref DeviceRef, events chan<- MergedEvent, mutex *sync.Mutex) {
+	var (
+		stop        func() bool
+		interrupted chan struct{}
+		frame       []MergedEvent
+		ev          Event
+		err         error
+	)
+
+	if ref.Device.nonblock {
+		interrupted = make(chan struct{})
+		stop = context.AfterFunc(ctx, func() {
+			_ = ref.Device.file.SetReadDeadline(time.Now())
+			close(interrupted)
+		})
+
+		// Clear the deadline that interrupted the read, so that the
+		// device stays usable after Merge.
+		defer func() {
+			if !stop() {
+				<-interrupted
+				_ = ref.Device.file.SetReadDeadline(time.Time{})
+			}
+		}()
+	}
+
+	for {
+		if ref.Device.nonblock {
+			ev, err = waitEvent(ref.Device, ref.Device.ReadEvent)
+		} else {
+			ev, err = ref.Device.ReadEvent()
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		if err != nil {
+			frame = append(frame, MergedEvent{Ref: ref, Err: err})
+		} else {
+			frame = append(frame, MergedEvent{Ref: ref, Event: ev})
+		}
+
+		if err == nil && (ev.Type != EV_SYN || ev.Code != SYN_REPORT) {
+			continue
+		}
+
+		if !sendFrame(ctx, frame, events, mutex) || err != nil {
+			return
+		}
+
+		frame = frame[:0]
+	}
+}
+
+func sendFrame(ctx context.Context, frame []MergedEvent, events chan<- MergedEvent, mutex *sync.Mutex) bool {
+	var merged MergedEvent
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for _, merged = range frame {
+		select {
+		case events <- merged:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
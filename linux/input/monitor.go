@@ -0,0 +1,218 @@
+//go:build linux
+
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/internal/inotify"
+	"golang.org/x/sys/unix"
+)
+
+// Monitor watches /dev/input with inotify for event devices being
+// attached and detached. It implements [mylib.DeviceMonitor], the
+// backend of a [mylib.DeviceManager].
+type Monitor struct {
+	opts []Option
+
+	errMutex sync.Mutex
+	err      error
+}
+
+var _ mylib.DeviceMonitor = (*Monitor)(nil)
+
+// NewMonitor returns a Monitor opening the devices it reports with
+// opts, such as [NonBlocking] so that their reads can be interrupted.
+func NewMonitor(opts ...Option) *Monitor {
+	return &Monitor{opts: opts}
+}
+
+// Watch implements [mylib.DeviceMonitor]. The reported devices are
+// classified with [Classify], so that they can be type-asserted to the
+// device class interfaces of the mylib package, and are keyed by the
+// path of their node, such as "/dev/input/event3". [AsDevice] returns
+// the [Device] of a reported device. Nodes that cannot be opened are
+// skipped until their permissions change, which covers the moment
+// between the kernel creating a node and udev granting access to it.
+// If watching fails before ctx is done, the channel is closed and
+// [Monitor.Err] returns the error.
+func (monitor *Monitor) Watch(ctx context.Context) (<-chan mylib.DeviceChange, error) {
+	const mask = unix.IN_CREATE | unix.IN_ATTRIB | unix.IN_DELETE |
+		unix.IN_MOVED_TO | unix.IN_MOVED_FROM
+
+	var (
+		watcher *inotify.Watcher
+		changes chan mylib.DeviceChange
+		err     error
+	)
+
+	watcher, err = inotify.New()
+	if err != nil {
+		return nil, fmt.Errorf("Monitor.Watch: %w", err)
+	}
+
+	// The watch is added before listing the directory, so that no node
+	// created in between is missed.
+	_, err = watcher.Add("/dev/input", mask)
+	if err != nil {
+		_ = watcher.Close()
+
+		return nil, fmt.Errorf("Monitor.Watch: %w", err)
+	}
+
+	monitor.setErr(nil)
+	changes = make(chan mylib.DeviceChange)
+
+	go func() {
+		var runErr error
+
+		defer close(changes)
+
+		runErr = monitor.run(ctx, watcher, changes)
+		runErr = errors.Join(runErr, watcher.Close())
+		if runErr != nil {
+			monitor.setErr(fmt.Errorf("Monitor.Watch: %w", runErr))
+		}
+	}()
+
+	return changes, nil
+}
+
+// Err returns the error that ended the channel of the latest
+// [Monitor.Watch], or nil if it ended because ctx was done or has not
+// ended yet.
+func (monitor *Monitor) Err() error {
+	monitor.errMutex.Lock()
+	defer monitor.errMutex.Unlock()
+
+	return monitor.err
+}
+
+func (monitor *Monitor) setErr(err error) {
+	monitor.errMutex.Lock()
+	defer monitor.errMutex.Unlock()
+
+	monitor.err = err
+}
+
+// run reports the devices present and then those attached and detached
+// until ctx is done or reading the events of watcher fails.
+func (monitor *Monitor) run(
+	ctx context.Context,
+	watcher *inotify.Watcher,
+	changes chan<- mylib.DeviceChange,
+) error {
+	var (
+		devices map[string]mylib.InputDevice
+		paths   []string
+		path    string
+		err     error
+	)
+
+	devices = make(map[string]mylib.InputDevice)
+
+	paths, err = NewSystem(nil).EventNodes()
+	if err != nil {
+		return err
+	}
+
+	for _, path = range paths {
+		if !monitor.add(ctx, devices, path, changes) {
+			return nil
+		}
+	}
+
+	return watcher.Run(ctx, func(ev inotify.Event) bool {
+		var eventPath string
+
+		if !strings.HasPrefix(ev.Name, "event") {
+			return true
+		}
+
+		eventPath = filepath.Join("/dev/input", ev.Name)
+
+		if ev.Mask&(unix.IN_DELETE|unix.IN_MOVED_FROM) != 0 {
+			return monitor.remove(ctx, devices, eventPath, changes)
+		}
+
+		return monitor.add(ctx, devices, eventPath, changes)
+	})
+}
+
+// add opens the device at path and reports it, unless it is already
+// open. It returns false if ctx is done.
+func (monitor *Monitor) add(
+	ctx context.Context,
+	devices map[string]mylib.InputDevice,
+	path string,
+	changes chan<- mylib.DeviceChange,
+) bool {
+	var (
+		dev      *Device
+		inputDev mylib.InputDevice
+		ok       bool
+		err      error
+	)
+
+	_, ok = devices[path]
+	if ok {
+		return true
+	}
+
+	dev, err = NewDevice(path, monitor.opts...)
+	if err != nil {
+		return true
+	}
+
+	inputDev, err = Classify(dev)
+	if err != nil {
+		_ = dev.Close()
+
+		return true
+	}
+
+	select {
+	case changes <- mylib.DeviceChange{Key: path, Device: inputDev, Added: true}:
+		devices[path] = inputDev
+
+		return true
+	case <-ctx.Done():
+		_ = dev.Close()
+
+		return false
+	}
+}
+
+// remove reports the device at path as detached if it was reported as
+// attached. It returns false if ctx is done.
+func (monitor *Monitor) remove(
+	ctx context.Context,
+	devices map[string]mylib.InputDevice,
+	path string,
+	changes chan<- mylib.DeviceChange,
+) bool {
+	var (
+		dev mylib.InputDevice
+		ok  bool
+	)
+
+	dev, ok = devices[path]
+	if !ok {
+		return true
+	}
+
+	delete(devices, path)
+
+	select {
+	case changes <- mylib.DeviceChange{Key: path, Device: dev}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
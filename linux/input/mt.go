@@ -0,0 +1,191 @@
+//go:build linux
+
+package input
+
+import "github.com/andrieee44/mylib"
+
+// ContactPhase tells whether a [ContactChange] starts, moves, or ends a
+// touch contact.
+type ContactPhase int
+
+const (
+	// ContactBegin is reported when a finger or tool touches down.
+	ContactBegin ContactPhase = iota
+
+	// ContactUpdate is reported when a contact moves or changes pressure
+	// or size.
+	ContactUpdate
+
+	// ContactEnd is reported when a contact lifts off. The contact holds
+	// its last values.
+	ContactEnd
+)
+
+// Contact is a single touch contact tracked in a multi-touch slot.
+type Contact struct {
+	// Slot is the multi-touch slot holding the contact.
+	Slot int
+
+	// TrackingID identifies the contact for as long as it lasts.
+	TrackingID int32
+
+	// X and Y are the contact position, from ABS_MT_POSITION_X and
+	// ABS_MT_POSITION_Y.
+	X, Y int32
+
+	// Pressure is the contact pressure, from ABS_MT_PRESSURE.
+	Pressure int32
+
+	// TouchMajor and TouchMinor are the lengths of the major and minor
+	// axes of the contact area, from ABS_MT_TOUCH_MAJOR and
+	// ABS_MT_TOUCH_MINOR.
+	TouchMajor, TouchMinor int32
+}
+
+// ContactChange describes a contact starting, moving, or ending.
+type ContactChange struct {
+	// Phase tells what happened to the contact.
+	Phase ContactPhase
+
+	// Contact holds the contact's values after the change.
+	Contact Contact
+}
+
+// MTTracker follows the contacts of a multi-touch protocol B device,
+// such as a touchscreen or touchpad, from its ABS_MT_* events. Changes
+// are collected per frame and reported when the frame ends with
+// [SYN_REPORT]. Feed it events from a [SyncReader] so that contacts stay
+// consistent across [SYN_DROPPED].
+type MTTracker struct {
+	slot     int
+	current  []Contact
+	pending  []Contact
+	dirty    []bool
+	onChange func(ContactChange)
+}
+
+// NewMTTracker returns an MTTracker for a device with the given number of
+// slots, usually the maximum of [ABS_MT_SLOT] plus one. If onChange is
+// non-nil, it is called for every contact that began, moved, or ended in
+// a frame, in slot order.
+func NewMTTracker(slots int, onChange func(ContactChange)) *MTTracker {
+	var (
+		tracker *MTTracker
+		i       int
+	)
+
+	tracker = &MTTracker{
+		current:  make([]Contact, slots),
+		pending:  make([]Contact, slots),
+		dirty:    make([]bool, slots),
+		onChange: onChange,
+	}
+
+	for i = range slots {
+		tracker.current[i] = Contact{Slot: i, TrackingID: -1}
+		tracker.pending[i] = tracker.current[i]
+	}
+
+	return tracker
+}
+
+// Handle updates the tracker with a single event. Events other than
+// ABS_MT_* axes and [SYN_REPORT] are ignored, as are events for slots out
+// of range.
+func (tracker *MTTracker) Handle(ev Event) {
+	var contact *Contact
+
+	if ev.Type == EV_SYN && ev.Code == SYN_REPORT {
+		tracker.commit()
+
+		return
+	}
+
+	if ev.Type != EV_ABS {
+		return
+	}
+
+	if ev.Code == ABS_MT_SLOT {
+		tracker.slot = int(ev.Value)
+
+		return
+	}
+
+	if tracker.slot < 0 || tracker.slot >= len(tracker.pending) {
+		return
+	}
+
+	contact = &tracker.pending[tracker.slot]
+
+	switch mylib.InputCode(ev.Code) {
+	case ABS_MT_TRACKING_ID:
+		contact.TrackingID = ev.Value
+	case ABS_MT_POSITION_X:
+		contact.X = ev.Value
+	case ABS_MT_POSITION_Y:
+		contact.Y = ev.Value
+	case ABS_MT_PRESSURE:
+		contact.Pressure = ev.Value
+	case ABS_MT_TOUCH_MAJOR:
+		contact.TouchMajor = ev.Value
+	case ABS_MT_TOUCH_MINOR:
+		contact.TouchMinor = ev.Value
+	default:
+		return
+	}
+
+	tracker.dirty[tracker.slot] = true
+}
+
+// Contacts returns the active contacts as of the last frame, in slot
+// order.
+func (tracker *MTTracker) Contacts() []Contact {
+	var (
+		contacts []Contact
+		contact  Contact
+	)
+
+	for _, contact = range tracker.current {
+		if contact.TrackingID != -1 {
+			contacts = append(contacts, contact)
+		}
+	}
+
+	return contacts
+}
+
+func (tracker *MTTracker) commit() {
+	var (
+		slot          int
+		before, after Contact
+	)
+
+	for slot = range tracker.pending {
+		if !tracker.dirty[slot] {
+			continue
+		}
+
+		before, after = tracker.current[slot], tracker.pending[slot]
+		tracker.current[slot] = after
+		tracker.dirty[slot] = false
+
+		switch {
+		case before.TrackingID == -1 && after.TrackingID == -1:
+		case before.TrackingID == -1:
+			tracker.notify(ContactBegin, after)
+		case after.TrackingID == -1:
+			tracker.notify(ContactEnd, before)
+		case before.TrackingID != after.TrackingID:
+			tracker.notify(ContactEnd, before)
+			tracker.notify(ContactBegin, after)
+		case before != after:
+			tracker.notify(ContactUpdate, after)
+		}
+	}
+}
+
+func (tracker *MTTracker) notify(phase ContactPhase, contact Contact) {
+	if tracker.onChange != nil {
+		tracker.onChange(ContactChange{Phase: phase, Contact: contact})
+	}
+}
@@ -0,0 +1,144 @@
+//go:build linux
+
+package input_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+type mtTrackerTest struct {
+	name     string
+	events   []input.Event
+	want     []input.ContactChange
+	contacts []input.Contact
+}
+
+func TestMTTracker(t *testing.T) {
+	var (
+		tests   []mtTrackerTest
+		test    mtTrackerTest
+		changes []input.ContactChange
+		tracker *input.MTTracker
+		ev      input.Event
+	)
+
+	tests = []mtTrackerTest{
+		{
+			name: "begin, move, and end",
+			events: slices.Concat(
+				frame(
+					abs(0, input.ABS_MT_TRACKING_ID, 7),
+					abs(0, input.ABS_MT_POSITION_X, 100),
+					abs(0, input.ABS_MT_POSITION_Y, 200),
+				),
+				frame(abs(1, input.ABS_MT_POSITION_X, 110), abs(1, input.ABS_MT_PRESSURE, 30)),
+				frame(abs(2, input.ABS_MT_TRACKING_ID, -1)),
+			),
+			want: []input.ContactChange{
+				{Phase: input.ContactBegin, Contact: input.Contact{TrackingID: 7, X: 100, Y: 200}},
+				{
+					Phase:   input.ContactUpdate,
+					Contact: input.Contact{TrackingID: 7, X: 110, Y: 200, Pressure: 30},
+				},
+				{
+					Phase:   input.ContactEnd,
+					Contact: input.Contact{TrackingID: 7, X: 110, Y: 200, Pressure: 30},
+				},
+			},
+		},
+		{
+			name: "slot order",
+			events: frame(
+				abs(0, input.ABS_MT_SLOT, 1),
+				abs(0, input.ABS_MT_TRACKING_ID, 8),
+				abs(0, input.ABS_MT_SLOT, 0),
+				abs(0, input.ABS_MT_TRACKING_ID, 9),
+				abs(0, input.ABS_MT_TOUCH_MAJOR, 5),
+				abs(0, input.ABS_MT_TOUCH_MINOR, 3),
+			),
+			want: []input.ContactChange{
+				{
+					Phase:   input.ContactBegin,
+					Contact: input.Contact{TrackingID: 9, TouchMajor: 5, TouchMinor: 3},
+				},
+				{Phase: input.ContactBegin, Contact: input.Contact{Slot: 1, TrackingID: 8}},
+			},
+			contacts: []input.Contact{
+				{TrackingID: 9, TouchMajor: 5, TouchMinor: 3},
+				{Slot: 1, TrackingID: 8},
+			},
+		},
+		{
+			name: "tracking ID replaced",
+			events: slices.Concat(
+				frame(abs(0, input.ABS_MT_TRACKING_ID, 1), abs(0, input.ABS_MT_POSITION_X, 5)),
+				frame(abs(1, input.ABS_MT_TRACKING_ID, 2)),
+			),
+			want: []input.ContactChange{
+				{Phase: input.ContactBegin, Contact: input.Contact{TrackingID: 1, X: 5}},
+				{Phase: input.ContactEnd, Contact: input.Contact{TrackingID: 1, X: 5}},
+				{Phase: input.ContactBegin, Contact: input.Contact{TrackingID: 2, X: 5}},
+			},
+			contacts: []input.Contact{{TrackingID: 2, X: 5}},
+		},
+		{
+			name: "unchanged values",
+			events: slices.Concat(
+				frame(abs(0, input.ABS_MT_TRACKING_ID, 1), abs(0, input.ABS_MT_POSITION_X, 5)),
+				frame(abs(1, input.ABS_MT_POSITION_X, 5)),
+			),
+			want: []input.ContactChange{
+				{Phase: input.ContactBegin, Contact: input.Contact{TrackingID: 1, X: 5}},
+			},
+			contacts: []input.Contact{{TrackingID: 1, X: 5}},
+		},
+		{
+			name: "slots out of range",
+			events: frame(
+				abs(0, input.ABS_MT_SLOT, 2),
+				abs(0, input.ABS_MT_TRACKING_ID, 1),
+				abs(0, input.ABS_MT_SLOT, -1),
+				abs(0, input.ABS_MT_TRACKING_ID, 2),
+			),
+		},
+		{
+			name: "incomplete frame",
+			events: []input.Event{
+				abs(0, input.ABS_MT_TRACKING_ID, 1),
+				at(0, input.EV_SYN, input.SYN_DROPPED, 0),
+				key(0, input.BTN_TOUCH, 1),
+			},
+		},
+		{
+			name: "lifted within a frame",
+			events: frame(
+				abs(0, input.ABS_MT_TRACKING_ID, 1),
+				abs(0, input.ABS_MT_TRACKING_ID, -1),
+			),
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes = nil
+			tracker = input.NewMTTracker(2, func(change input.ContactChange) {
+				changes = append(changes, change)
+			})
+
+			for _, ev = range test.events {
+				tracker.Handle(ev)
+			}
+
+			if !slices.Equal(changes, test.want) {
+				t.Errorf("changes = %+v, want %+v", changes, test.want)
+			}
+
+			if !slices.Equal(tracker.Contacts(), test.contacts) {
+				t.Errorf("Contacts = %+v, want %+v", tracker.Contacts(), test.contacts)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package input
+
+import "os"
+
+// Option configures how [NewDevice] opens a device.
+type Option func(*openOptions)
+
+type openOptions struct {
+	flags    int
+	nonblock bool
+	grab     bool
+
+	latencySamples int
+}
+
+// ReadOnly opens the device for reading only. Write access is only needed
+// for force feedback, LEDs, sounds, and [Device.WriteEvent], and
+// read-only opens succeed for more users, for example through a udev ACL
+// granting only read access.
+func ReadOnly() Option {
+	return func(options *openOptions) {
+		options.flags = os.O_RDONLY
+	}
+}
+
+// NonBlocking opens the device with O_NONBLOCK, so that
+// [Device.ReadEvent] returns immediately when no event is queued.
+func NonBlocking() Option {
+	return func(options *openOptions) {
+		options.nonblock = true
+	}
+}
+
+// GrabOnOpen grabs the device with [Device.Grab] right after opening it,
+// so that no event reaches other readers in between.
+func GrabOnOpen() Option {
+	return func(options *openOptions) {
+		options.grab = true
+	}
+}
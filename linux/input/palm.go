@@ -0,0 +1,300 @@
+//go:build linux
+
+package input
+
+import "slices"
+
+// PalmFilter is a [Filter] implementing basic palm rejection for
+// multitouch protocol B touchpads, for setups that run without libinput.
+//
+// A contact is treated as a palm for its whole lifetime if it begins
+// inside an edge zone, or from the moment its [ABS_MT_TOUCH_MAJOR]
+// exceeds MaxTouchMajor. Events of palm contacts are dropped; a contact
+// that turns into a palm after it was reported is ended with a synthetic
+// [ABS_MT_TRACKING_ID] of -1. While every active contact is a palm, the
+// single-touch emulation axes [ABS_X], [ABS_Y], and [ABS_PRESSURE] are
+// dropped as well.
+//
+// [BTN_TOUCH] and the finger count tools, [BTN_TOOL_FINGER] through
+// [BTN_TOOL_QUINTTAP], are recomputed without the palms, so that a palm
+// resting beside a finger reports one finger and a palm alone reports
+// no touch at all.
+//
+// The thresholds are in device units and are device specific, so a
+// PalmFilter is configured per device, typically from the ranges of its
+// absolute axes.
+type PalmFilter struct {
+	// MaxTouchMajor is the largest touch major axis of a finger.
+	// Zero disables size-based rejection.
+	MaxTouchMajor int32
+
+	// LeftEdge is the X coordinate left of which new contacts are palms.
+	// Zero disables the left edge zone.
+	LeftEdge int32
+
+	// RightEdge is the X coordinate right of which new contacts are
+	// palms. Zero disables the right edge zone.
+	RightEdge int32
+
+	slot     int32
+	contacts map[int32]*palmContact
+	out      []Event
+
+	// fingers and touch are the finger count and touch state reported by
+	// the device; tool and touched are those reported by the filter.
+	fingers int
+	touch   bool
+	tool    uint16
+	touched bool
+}
+
+// fingerTools are the BTN_TOOL_* codes reporting one through five
+// fingers.
+var fingerTools []uint16 = []uint16{
+	BTN_TOOL_FINGER,
+	BTN_TOOL_DOUBLETAP,
+	BTN_TOOL_TRIPLETAP,
+	BTN_TOOL_QUADTAP,
+	BTN_TOOL_QUINTTAP,
+}
+
+type palmContact struct {
+	active, began, palm, reported bool
+	x, major                      int32
+}
+
+var _ Filter = (*PalmFilter)(nil)
+
+// Filter drops the events of palm contacts from frame.
+func (filter *PalmFilter) Filter(frame []Event) []Event {
+	var (
+		ev              Event
+		slot, startSlot int32
+		contact         *palmContact
+		allPalms        bool
+	)
+
+	if filter.contacts == nil {
+		filter.contacts = make(map[int32]*palmContact)
+	}
+
+	startSlot = filter.slot
+	filter.scan(frame)
+	allPalms = filter.allPalms()
+	filter.out = filter.out[:0]
+
+	for slot, contact = range filter.contacts {
+		if !contact.palm || !contact.reported {
+			continue
+		}
+
+		filter.out = append(
+			filter.out,
+			Event{Type: EV_ABS, Code: ABS_MT_SLOT, Value: slot},
+			Event{Type: EV_ABS, Code: ABS_MT_TRACKING_ID, Value: -1},
+		)
+		contact.reported = false
+	}
+
+	if len(filter.out) != 0 {
+		filter.out = append(
+			filter.out,
+			Event{Type: EV_ABS, Code: ABS_MT_SLOT, Value: startSlot},
+		)
+	}
+
+	slot = startSlot
+
+	for _, ev = range frame {
+		switch {
+		case ev.Type == EV_KEY && (ev.Code == BTN_TOUCH || slices.Contains(fingerTools, ev.Code)):
+			continue
+		case ev.Type == EV_SYN && ev.Code == SYN_REPORT:
+			filter.buttons(ev)
+		}
+
+		if ev.Type != EV_ABS {
+			filter.out = append(filter.out, ev)
+
+			continue
+		}
+
+		switch {
+		case ev.Code == ABS_MT_SLOT:
+			slot = ev.Value
+		case ev.Code > ABS_MT_SLOT && ev.Code <= ABS_MT_TOOL_Y:
+			contact = filter.contacts[slot]
+			if contact != nil && contact.palm {
+				continue
+			}
+
+			if contact != nil && ev.Code == ABS_MT_TRACKING_ID {
+				contact.reported = ev.Value >= 0
+			}
+		case allPalms && (ev.Code == ABS_X || ev.Code == ABS_Y ||
+			ev.Code == ABS_PRESSURE):
+			continue
+		}
+
+		filter.out = append(filter.out, ev)
+	}
+
+	for slot, contact = range filter.contacts {
+		if !contact.active {
+			delete(filter.contacts, slot)
+		}
+	}
+
+	return filter.out
+}
+
+func (filter *PalmFilter) scan(frame []Event) {
+	var (
+		ev      Event
+		contact *palmContact
+	)
+
+	for _, ev = range frame {
+		if ev.Type == EV_KEY {
+			filter.deviceButton(ev)
+		}
+
+		if ev.Type != EV_ABS {
+			continue
+		}
+
+		if ev.Code == ABS_MT_SLOT {
+			filter.slot = ev.Value
+
+			continue
+		}
+
+		contact = filter.contacts[filter.slot]
+		if contact == nil {
+			contact = &palmContact{}
+			filter.contacts[filter.slot] = contact
+		}
+
+		switch ev.Code {
+		case ABS_MT_TRACKING_ID:
+			if ev.Value < 0 {
+				contact.active = false
+
+				continue
+			}
+
+			*contact = palmContact{active: true, began: true}
+		case ABS_MT_POSITION_X:
+			contact.x = ev.Value
+		case ABS_MT_TOUCH_MAJOR:
+			contact.major = ev.Value
+		}
+	}
+
+	for _, contact = range filter.contacts {
+		if contact.began && filter.inEdge(contact.x) {
+			contact.palm = true
+		}
+
+		if filter.MaxTouchMajor != 0 && contact.major > filter.MaxTouchMajor {
+			contact.palm = true
+		}
+
+		contact.began = false
+	}
+}
+
+// deviceButton records the touch state and finger count reported by the
+// device.
+func (filter *PalmFilter) deviceButton(ev Event) {
+	var fingers int
+
+	if ev.Code == BTN_TOUCH {
+		filter.touch = ev.Value != 0
+
+		return
+	}
+
+	fingers = slices.Index(fingerTools, ev.Code) + 1
+
+	switch {
+	case fingers == 0:
+	case ev.Value != 0:
+		filter.fingers = fingers
+	case filter.fingers == fingers:
+		filter.fingers = 0
+	}
+}
+
+// buttons appends the [BTN_TOUCH] and finger count changes that follow
+// from ignoring the palms, stamped like syn.
+func (filter *PalmFilter) buttons(syn Event) {
+	var (
+		contact *palmContact
+		fingers int
+		tool    uint16
+		touched bool
+		value   int32
+	)
+
+	fingers = filter.fingers
+
+	for _, contact = range filter.contacts {
+		if contact.active && contact.palm {
+			fingers--
+		}
+	}
+
+	if fingers > 0 {
+		tool = fingerTools[min(fingers, len(fingerTools))-1]
+	}
+
+	touched = filter.touch && fingers > 0
+
+	if tool != filter.tool {
+		if filter.tool != 0 {
+			filter.out = append(filter.out, Event{Sec: syn.Sec, Usec: syn.Usec, Type: EV_KEY, Code: filter.tool, Value: 0})
+		}
+
+		if tool != 0 {
+			filter.out = append(filter.out, Event{Sec: syn.Sec, Usec: syn.Usec, Type: EV_KEY, Code: tool, Value: 1})
+		}
+
+		filter.tool = tool
+	}
+
+	if touched != filter.touched {
+		if touched {
+			value = 1
+		}
+
+		filter.out = append(filter.out, Event{Sec: syn.Sec, Usec: syn.Usec, Type: EV_KEY, Code: BTN_TOUCH, Value: value})
+		filter.touched = touched
+	}
+}
+
+func (filter *PalmFilter) inEdge(x int32) bool {
+	return filter.LeftEdge != 0 && x < filter.LeftEdge ||
+		filter.RightEdge != 0 && x > filter.RightEdge
+}
+
+func (filter *PalmFilter) allPalms() bool {
+	var (
+		contact *palmContact
+		palms   bool
+	)
+
+	for _, contact = range filter.contacts {
+		if !contact.active {
+			continue
+		}
+
+		if !contact.palm {
+			return false
+		}
+
+		palms = true
+	}
+
+	return palms
+}
@@ -0,0 +1,133 @@
+//go:build linux
+
+package input_test
+
+import (
+	"testing"
+
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+// touch returns the events of a contact touching down in the current
+// slot at x, as the device reports them with a finger count of fingers.
+func touch(id, x int32, fingers uint16) []input.Event {
+	return []input.Event{
+		abs(0, input.ABS_MT_TRACKING_ID, id),
+		abs(0, input.ABS_MT_POSITION_X, x),
+		key(0, input.BTN_TOUCH, 1),
+		key(0, fingers, 1),
+		abs(0, input.ABS_X, x),
+	}
+}
+
+func TestPalmFilter(t *testing.T) {
+	var (
+		tests []filterTest
+		test  filterTest
+	)
+
+	tests = []filterTest{
+		{
+			name: "finger",
+			frames: [][]input.Event{
+				frame(touch(1, 500, input.BTN_TOOL_FINGER)...),
+				frame(
+					abs(0, input.ABS_MT_TRACKING_ID, -1),
+					key(0, input.BTN_TOUCH, 0),
+					key(0, input.BTN_TOOL_FINGER, 0),
+				),
+			},
+			want: []input.Event{
+				abs(0, input.ABS_MT_TRACKING_ID, 1),
+				abs(0, input.ABS_MT_POSITION_X, 500),
+				abs(0, input.ABS_X, 500),
+				key(0, input.BTN_TOOL_FINGER, 1),
+				key(0, input.BTN_TOUCH, 1),
+				syn(0),
+				abs(0, input.ABS_MT_TRACKING_ID, -1),
+				key(0, input.BTN_TOOL_FINGER, 0),
+				key(0, input.BTN_TOUCH, 0),
+				syn(0),
+			},
+		},
+		{
+			name: "edge palm",
+			frames: [][]input.Event{
+				frame(touch(1, 10, input.BTN_TOOL_FINGER)...),
+				frame(abs(0, input.ABS_MT_POSITION_X, 500), abs(0, input.ABS_X, 500)),
+			},
+			want: []input.Event{syn(0), syn(0)},
+		},
+		{
+			name: "right edge palm",
+			frames: [][]input.Event{
+				frame(touch(1, 990, input.BTN_TOOL_FINGER)...),
+			},
+			want: []input.Event{syn(0)},
+		},
+		{
+			name: "grows into a palm",
+			frames: [][]input.Event{
+				frame(touch(1, 500, input.BTN_TOOL_FINGER)...),
+				frame(abs(0, input.ABS_MT_TOUCH_MAJOR, 200), abs(0, input.ABS_X, 510)),
+			},
+			want: []input.Event{
+				abs(0, input.ABS_MT_TRACKING_ID, 1),
+				abs(0, input.ABS_MT_POSITION_X, 500),
+				abs(0, input.ABS_X, 500),
+				key(0, input.BTN_TOOL_FINGER, 1),
+				key(0, input.BTN_TOUCH, 1),
+				syn(0),
+				abs(0, input.ABS_MT_SLOT, 0),
+				abs(0, input.ABS_MT_TRACKING_ID, -1),
+				abs(0, input.ABS_MT_SLOT, 0),
+				key(0, input.BTN_TOOL_FINGER, 0),
+				key(0, input.BTN_TOUCH, 0),
+				syn(0),
+			},
+		},
+		{
+			name: "palm beside a finger",
+			frames: [][]input.Event{
+				frame(append(
+					touch(1, 500, input.BTN_TOOL_DOUBLETAP),
+					abs(0, input.ABS_MT_SLOT, 1),
+					abs(0, input.ABS_MT_TRACKING_ID, 2),
+					abs(0, input.ABS_MT_POSITION_X, 10),
+				)...),
+				frame(
+					abs(0, input.ABS_MT_TRACKING_ID, -1),
+					key(0, input.BTN_TOOL_DOUBLETAP, 0),
+					key(0, input.BTN_TOOL_FINGER, 1),
+				),
+			},
+			want: []input.Event{
+				abs(0, input.ABS_MT_TRACKING_ID, 1),
+				abs(0, input.ABS_MT_POSITION_X, 500),
+				abs(0, input.ABS_X, 500),
+				abs(0, input.ABS_MT_SLOT, 1),
+				key(0, input.BTN_TOOL_FINGER, 1),
+				key(0, input.BTN_TOUCH, 1),
+				syn(0),
+				syn(0),
+			},
+		},
+		{
+			name: "SYN_DROPPED passes",
+			frames: [][]input.Event{
+				{at(0, input.EV_SYN, input.SYN_DROPPED, 0)},
+			},
+			want: []input.Event{at(0, input.EV_SYN, input.SYN_DROPPED, 0)},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkEvents(t, filterFrames(&input.PalmFilter{
+				MaxTouchMajor: 100,
+				LeftEdge:      50,
+				RightEdge:     950,
+			}, test.frames, false), test.want)
+		})
+	}
+}
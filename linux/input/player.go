@@ -0,0 +1,70 @@
+//go:build linux
+
+package input
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Player replays recorded events into a [VirtualDevice], keeping the
+// delays between them, for automated UI and driver testing.
+type Player struct {
+	dev   *VirtualDevice
+	speed float64
+}
+
+// NewPlayer returns a Player that writes to dev. The recorded delays are
+// divided by speed, so 2 plays twice as fast; a speed of zero or less
+// plays in real time.
+func NewPlayer(dev *VirtualDevice, speed float64) *Player {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	return &Player{dev: dev, speed: speed}
+}
+
+// Play writes events to the device, waiting between them as long as
+// their timestamps say. Each delay is measured from the start of
+// playback rather than from the previous event, so time spent writing
+// does not accumulate as drift. It returns ctx.Err() if ctx is done
+// before the last event is written.
+func (player *Player) Play(ctx context.Context, events []Event) error {
+	var (
+		start time.Time
+		base  time.Duration
+		timer *time.Timer
+		ev    Event
+		err   error
+	)
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	start = time.Now()
+	base = eventTime(events[0])
+	timer = time.NewTimer(0)
+	defer timer.Stop()
+
+	for _, ev = range events {
+		timer.Reset(time.Until(start.Add(
+			time.Duration(float64(eventTime(ev)-base) / player.speed),
+		)))
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return fmt.Errorf("Player.Play: %w", ctx.Err())
+		}
+
+		err = player.dev.WriteEvent(ev)
+		if err != nil {
+			return fmt.Errorf("Player.Play: %w", err)
+		}
+	}
+
+	return nil
+}
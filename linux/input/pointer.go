@@ -0,0 +1,301 @@
+//go:build linux
+
+package input
+
+import (
+	"math"
+	"time"
+)
+
+// Rotation is a clockwise rotation by a multiple of 90 degrees.
+type Rotation int
+
+const (
+	// Rotate0 leaves motion unchanged.
+	Rotate0 Rotation = iota
+
+	// Rotate90 rotates motion by 90 degrees clockwise.
+	Rotate90
+
+	// Rotate180 rotates motion by 180 degrees.
+	Rotate180
+
+	// Rotate270 rotates motion by 270 degrees clockwise.
+	Rotate270
+)
+
+// AccelCurve returns the gain applied to relative pointer motion moving
+// at speed, in device units per millisecond.
+type AccelCurve func(speed float64) float64
+
+// ConstantAccel returns an AccelCurve scaling all motion by gain, like
+// libinput's flat profile.
+func ConstantAccel(gain float64) AccelCurve {
+	return func(float64) float64 {
+		return gain
+	}
+}
+
+// LinearAccel returns an AccelCurve that leaves motion slower than
+// threshold unchanged and raises the gain by slope for every unit of
+// speed above it, up to maxGain.
+func LinearAccel(threshold, slope, maxGain float64) AccelCurve {
+	return func(speed float64) float64 {
+		return min(1+max(speed-threshold, 0)*slope, max(maxGain, 1))
+	}
+}
+
+// PointerTransform is a [Filter] that reorients and accelerates pointer
+// motion, for rotated touchscreens and for compositors or kiosks that
+// run without libinput.
+//
+// Relative motion, [REL_X] and [REL_Y], is swapped, inverted, rotated,
+// and then accelerated, carrying rounding remainders over to later
+// frames. Absolute positions, [ABS_X] and [ABS_Y] as well as
+// [ABS_MT_POSITION_X] and [ABS_MT_POSITION_Y], are reoriented in the same
+// way within the ranges given by X and Y; they are left alone if those
+// are unset.
+type PointerTransform struct {
+	// SwapXY exchanges the horizontal and vertical axes.
+	SwapXY bool
+
+	// InvertX and InvertY mirror the axes, after SwapXY.
+	InvertX, InvertY bool
+
+	// Rotation rotates motion clockwise, after the swap and inversion.
+	Rotation Rotation
+
+	// Accel gives the gain for relative motion. Nil leaves its speed
+	// unchanged.
+	Accel AccelCurve
+
+	// X and Y are the parameters of the horizontal and vertical absolute
+	// axes, such as those returned by [Device.AbsInfo] for ABS_X and
+	// ABS_Y, which the multi-touch axes are expected to share.
+	X, Y AbsInfo
+
+	remX, remY float64
+	last       time.Duration
+	abs        pointerPosition
+	slots      map[int32]*pointerPosition
+	slot       int32
+	out        []Event
+}
+
+// pointerPosition is the last absolute position of a pointer or contact,
+// in device units.
+type pointerPosition struct {
+	x, y  int32
+	dirty bool
+}
+
+var _ Filter = (*PointerTransform)(nil)
+
+// Filter transforms the pointer motion in frame.
+func (filter *PointerTransform) Filter(frame []Event) []Event {
+	var (
+		ev      Event
+		dx, dy  float64
+		motion  int
+		hasRel  bool
+		absMode bool
+	)
+
+	if filter.slots == nil {
+		filter.slots = make(map[int32]*pointerPosition)
+	}
+
+	absMode = filter.X.Maximum > filter.X.Minimum && filter.Y.Maximum > filter.Y.Minimum &&
+		(filter.SwapXY || filter.InvertX || filter.InvertY || filter.Rotation != Rotate0)
+	filter.out = filter.out[:0]
+
+	for _, ev = range frame {
+		switch {
+		case ev.Type == EV_REL && (ev.Code == REL_X || ev.Code == REL_Y):
+			if !hasRel {
+				motion = len(filter.out)
+				hasRel = true
+			}
+
+			if ev.Code == REL_X {
+				dx += float64(ev.Value)
+			} else {
+				dy += float64(ev.Value)
+			}
+
+			continue
+		case absMode && ev.Type == EV_ABS:
+			if filter.absolute(ev) {
+				continue
+			}
+		case ev.Type == EV_SYN && ev.Code == SYN_REPORT:
+			filter.flushSlot()
+			filter.flush(&filter.abs, ABS_X, ABS_Y)
+
+			if hasRel {
+				filter.relative(motion, ev, dx, dy)
+			}
+		}
+
+		filter.out = append(filter.out, ev)
+	}
+
+	return filter.out
+}
+
+// absolute records an absolute position event, reporting whether it was
+// consumed. Positions are emitted transformed by flush.
+func (filter *PointerTransform) absolute(ev Event) bool {
+	var position *pointerPosition
+
+	switch ev.Code {
+	case ABS_X, ABS_Y:
+		position = &filter.abs
+	case ABS_MT_POSITION_X, ABS_MT_POSITION_Y:
+		position = filter.slots[filter.slot]
+		if position == nil {
+			position = &pointerPosition{}
+			filter.slots[filter.slot] = position
+		}
+	case ABS_MT_SLOT:
+		filter.flushSlot()
+		filter.slot = ev.Value
+
+		return false
+	default:
+		return false
+	}
+
+	if ev.Code == ABS_X || ev.Code == ABS_MT_POSITION_X {
+		position.x = ev.Value
+	} else {
+		position.y = ev.Value
+	}
+
+	position.dirty = true
+
+	return true
+}
+
+// flushSlot flushes the position of the current multi-touch slot.
+func (filter *PointerTransform) flushSlot() {
+	var (
+		position *pointerPosition
+		ok       bool
+	)
+
+	position, ok = filter.slots[filter.slot]
+	if ok {
+		filter.flush(position, ABS_MT_POSITION_X, ABS_MT_POSITION_Y)
+	}
+}
+
+// flush emits the transformed position if it changed.
+func (filter *PointerTransform) flush(position *pointerPosition, codeX, codeY uint16) {
+	var u, v float64
+
+	if !position.dirty {
+		return
+	}
+
+	position.dirty = false
+
+	u, v = filter.orient(
+		unitPosition(position.x, filter.X),
+		unitPosition(position.y, filter.Y),
+		true,
+	)
+
+	filter.out = append(
+		filter.out,
+		Event{Type: EV_ABS, Code: codeX, Value: devicePosition(u, filter.X)},
+		Event{Type: EV_ABS, Code: codeY, Value: devicePosition(v, filter.Y)},
+	)
+}
+
+// relative inserts the transformed relative motion of the frame at index
+// motion of the output, stamped like the frame's SYN_REPORT, syn.
+func (filter *PointerTransform) relative(motion int, syn Event, dx, dy float64) {
+	var (
+		now   time.Duration
+		dt    time.Duration
+		gain  float64
+		x, y  float64
+		moved []Event
+	)
+
+	dx, dy = filter.orient(dx, dy, false)
+
+	if filter.Accel != nil {
+		now = eventTime(syn)
+		dt = min(max(now-filter.last, time.Millisecond), 100*time.Millisecond)
+		filter.last = now
+
+		gain = filter.Accel(math.Hypot(dx, dy) / (float64(dt) / float64(time.Millisecond)))
+		dx *= gain
+		dy *= gain
+	}
+
+	dx += filter.remX
+	dy += filter.remY
+	x = math.Round(dx)
+	y = math.Round(dy)
+	filter.remX = dx - x
+	filter.remY = dy - y
+
+	if x != 0 {
+		moved = append(moved, Event{Sec: syn.Sec, Usec: syn.Usec, Type: EV_REL, Code: REL_X, Value: int32(x)})
+	}
+
+	if y != 0 {
+		moved = append(moved, Event{Sec: syn.Sec, Usec: syn.Usec, Type: EV_REL, Code: REL_Y, Value: int32(y)})
+	}
+
+	filter.out = append(filter.out[:motion], append(moved, filter.out[motion:]...)...)
+}
+
+// orient applies the swap, inversions, and rotation to x and y, which
+// are positions in the unit square if absolute is set and a motion
+// vector otherwise.
+func (filter *PointerTransform) orient(x, y float64, absolute bool) (float64, float64) {
+	var flip func(float64) float64
+
+	flip = func(value float64) float64 {
+		if absolute {
+			return 1 - value
+		}
+
+		return -value
+	}
+
+	if filter.SwapXY {
+		x, y = y, x
+	}
+
+	if filter.InvertX {
+		x = flip(x)
+	}
+
+	if filter.InvertY {
+		y = flip(y)
+	}
+
+	switch filter.Rotation {
+	case Rotate90:
+		x, y = flip(y), x
+	case Rotate180:
+		x, y = flip(x), flip(y)
+	case Rotate270:
+		x, y = y, flip(x)
+	}
+
+	return x, y
+}
+
+func unitPosition(value int32, info AbsInfo) float64 {
+	return (float64(value) - float64(info.Minimum)) / (float64(info.Maximum) - float64(info.Minimum))
+}
+
+func devicePosition(unit float64, info AbsInfo) int32 {
+	return int32(math.Round(float64(info.Minimum) + unit*(float64(info.Maximum)-float64(info.Minimum))))
+}
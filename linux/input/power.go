@@ -0,0 +1,239 @@
+//go:build linux
+
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/andrieee44/mylib"
+)
+
+// ErrNoPowerDevices is returned by [NewPowerWatcher] when no event device
+// reports power buttons or lid and tablet-mode switches.
+var ErrNoPowerDevices error = errors.New("no power button or switch devices")
+
+// PowerKind is the source of a [PowerEvent].
+type PowerKind int
+
+const (
+	// PowerButton is the power button, [KEY_POWER].
+	PowerButton PowerKind = iota
+
+	// SleepButton is the sleep or suspend button, [KEY_SLEEP] or
+	// [KEY_SUSPEND].
+	SleepButton
+
+	// Lid is the lid switch, [SW_LID].
+	Lid
+
+	// TabletMode is the tablet-mode switch of convertibles,
+	// [SW_TABLET_MODE].
+	TabletMode
+)
+
+// powerKinds maps the codes watched by a [PowerWatcher] to their kinds.
+var powerKinds map[mylib.InputEvent]map[mylib.InputCode]PowerKind = map[mylib.InputEvent]map[mylib.InputCode]PowerKind{
+	EV_KEY: {
+		KEY_POWER:   PowerButton,
+		KEY_SLEEP:   SleepButton,
+		KEY_SUSPEND: SleepButton,
+	},
+	EV_SW: {
+		SW_LID:         Lid,
+		SW_TABLET_MODE: TabletMode,
+	},
+}
+
+// String returns the name of the kind, such as "lid".
+func (kind PowerKind) String() string {
+	switch kind {
+	case PowerButton:
+		return "power button"
+	case SleepButton:
+		return "sleep button"
+	case Lid:
+		return "lid"
+	case TabletMode:
+		return "tablet mode"
+	default:
+		return fmt.Sprintf("PowerKind(%d)", int(kind))
+	}
+}
+
+// PowerEvent is a button press or switch change reported by a
+// [PowerWatcher].
+type PowerEvent struct {
+	// Kind is the button or switch.
+	Kind PowerKind
+
+	// On is set when a button is pressed, the lid is closed, or the
+	// device entered tablet mode, and cleared when a button is released
+	// or a switch is opened.
+	On bool
+
+	// Event is the underlying event.
+	Event Event
+}
+
+// PowerWatcher watches the ACPI buttons and switches, such as the power
+// button and the lid, for daemons that react to them without handling
+// evdev devices themselves.
+type PowerWatcher struct {
+	devs []*Device
+}
+
+// NewPowerWatcher opens every event device reporting a power or sleep
+// button, a lid switch, or a tablet-mode switch, such as the "Power
+// Button" and "Lid Switch" devices of the ACPI button driver, as well
+// as keyboards with power keys. It returns [ErrNoPowerDevices] if there
+// are none.
+func NewPowerWatcher() (*PowerWatcher, error) {
+	var (
+		watcher *PowerWatcher
+		paths   []string
+		path    string
+		dev     *Device
+		err     error
+	)
+
+	paths, err = NewSystem(nil).Match(isPowerDevice)
+	if err != nil {
+		return nil, fmt.Errorf("input.NewPowerWatcher: %w", err)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("input.NewPowerWatcher: %w", ErrNoPowerDevices)
+	}
+
+	watcher = &PowerWatcher{}
+
+	for _, path = range paths {
+		dev, err = NewDevice(path, NonBlocking())
+		if err != nil {
+			closeDevices(watcher.devs)
+
+			return nil, fmt.Errorf("input.NewPowerWatcher: %w", err)
+		}
+
+		watcher.devs = append(watcher.devs, dev)
+	}
+
+	return watcher, nil
+}
+
+// Devices returns the devices being watched.
+func (watcher *PowerWatcher) Devices() []*Device {
+	return watcher.devs
+}
+
+// Switches returns the current state of the lid and tablet-mode
+// switches, as [PowerEvent] values with a zero Event.
+func (watcher *PowerWatcher) Switches() ([]PowerEvent, error) {
+	var (
+		events   []PowerEvent
+		dev      *Device
+		switches []Switch
+		sw       Switch
+		kind     PowerKind
+		ok       bool
+		err      error
+	)
+
+	for _, dev = range watcher.devs {
+		switches, err = dev.Switches()
+		if err != nil {
+			return nil, fmt.Errorf("PowerWatcher.Switches: %w", err)
+		}
+
+		for _, sw = range switches {
+			kind, ok = powerKinds[EV_SW][sw.Code]
+			if ok {
+				events = append(events, PowerEvent{Kind: kind, On: sw.On})
+			}
+		}
+	}
+
+	return events, nil
+}
+
+// Watch delivers the button presses and switch changes of the watched
+// devices until ctx is done or every device has failed, after which the
+// channel is closed. Key repeats are not reported.
+func (watcher *PowerWatcher) Watch(ctx context.Context) <-chan PowerEvent {
+	var events chan PowerEvent
+
+	events = make(chan PowerEvent)
+
+	go func() {
+		var (
+			merged MergedEvent
+			kind   PowerKind
+			ok     bool
+		)
+
+		defer close(events)
+
+		for merged = range Merge(ctx, watcher.devs...) {
+			if merged.Err != nil || merged.Event.Type == EV_KEY && merged.Event.Value == 2 {
+				continue
+			}
+
+			kind, ok = powerKinds[mylib.InputEvent(merged.Event.Type)][mylib.InputCode(merged.Event.Code)]
+			if !ok {
+				continue
+			}
+
+			select {
+			case events <- PowerEvent{Kind: kind, On: merged.Event.Value != 0, Event: merged.Event}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// Close closes the watched devices.
+func (watcher *PowerWatcher) Close() error {
+	var (
+		dev  *Device
+		errs []error
+		err  error
+	)
+
+	for _, dev = range watcher.devs {
+		errs = append(errs, dev.Close())
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return fmt.Errorf("PowerWatcher.Close: %w", err)
+	}
+
+	return nil
+}
+
+// isPowerDevice is a [Predicate] matching devices that report any of
+// the codes watched by a [PowerWatcher].
+func isPowerDevice(info *DeviceInfo) bool {
+	var (
+		eventType mylib.InputEvent
+		kinds     map[mylib.InputCode]PowerKind
+		code      mylib.InputCode
+		ok        bool
+	)
+
+	for eventType, kinds = range powerKinds {
+		for _, code = range info.Codes[eventType] {
+			_, ok = kinds[code]
+			if ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,384 @@
+//go:build linux
+
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/andrieee44/mylib"
+)
+
+// ErrInvalidRecording is returned when a recording cannot be parsed.
+var ErrInvalidRecording error = errors.New("invalid recording")
+
+// Recording is a captured stream of events together with a description
+// of the device that produced them, ready to be replayed with a
+// [Player].
+type Recording struct {
+	// Config describes the recorded device, for creating a virtual copy
+	// of it with [NewVirtualDevice].
+	Config VirtualConfig
+
+	// Events holds the recorded events in order, with their original
+	// timestamps.
+	Events []Event
+}
+
+// ReadRecording parses a recording in either of two formats, detected
+// from its first line:
+//
+//   - the text format written by evemu-record, with the device
+//     description in its N:, I:, P:, B:, and A: lines and the events in
+//     its E: lines.
+//   - one JSON object per line, as written by [Event.MarshalJSON]. Such
+//     recordings carry no device description, so Config is derived from
+//     the events: its Codes list every type and code that occurs, and
+//     its absolute axes have a zero range.
+func ReadRecording(r io.Reader) (*Recording, error) {
+	var (
+		reader *bufio.Reader
+		first  []byte
+		rec    *Recording
+		err    error
+	)
+
+	reader = bufio.NewReader(r)
+
+	first, err = reader.Peek(1)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("input.ReadRecording: %w", err)
+	}
+
+	if bytes.Equal(first, []byte("{")) {
+		rec, err = readJSONRecording(reader)
+	} else {
+		rec, err = readEvemuRecording(reader)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("input.ReadRecording: %w", err)
+	}
+
+	return rec, nil
+}
+
+func readJSONRecording(r io.Reader) (*Recording, error) {
+	var (
+		decoder   *json.Decoder
+		rec       *Recording
+		seen      map[mylib.InputEvent]map[mylib.InputCode]bool
+		ev        Event
+		eventType mylib.InputEvent
+		codes     map[mylib.InputCode]bool
+		code      mylib.InputCode
+		err       error
+	)
+
+	decoder = json.NewDecoder(r)
+	rec = &Recording{Config: VirtualConfig{Name: "recording"}}
+	seen = make(map[mylib.InputEvent]map[mylib.InputCode]bool)
+
+	for {
+		err = decoder.Decode(&ev)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		rec.Events = append(rec.Events, ev)
+
+		eventType = mylib.InputEvent(ev.Type)
+		if seen[eventType] == nil {
+			seen[eventType] = make(map[mylib.InputCode]bool)
+		}
+
+		seen[eventType][mylib.InputCode(ev.Code)] = true
+	}
+
+	rec.Config.Codes = make(map[mylib.InputEvent][]mylib.InputCode, len(seen))
+
+	for eventType, codes = range seen {
+		for code = range codes {
+			rec.Config.Codes[eventType] = append(rec.Config.Codes[eventType], code)
+		}
+
+		slices.Sort(rec.Config.Codes[eventType])
+	}
+
+	return rec, nil
+}
+
+func readEvemuRecording(r io.Reader) (*Recording, error) {
+	var (
+		scanner *bufio.Scanner
+		rec     *Recording
+		bitmaps map[mylib.InputEvent][]byte
+		props   []byte
+		line    int
+		prefix  string
+		rest    string
+		found   bool
+		fields  []string
+		code    mylib.InputCode
+		err     error
+	)
+
+	scanner = bufio.NewScanner(r)
+	rec = &Recording{Config: VirtualConfig{Abs: make(map[mylib.InputCode]AbsInfo)}}
+	bitmaps = make(map[mylib.InputEvent][]byte)
+
+	for scanner.Scan() {
+		line++
+
+		prefix, rest, found = strings.Cut(scanner.Text(), ":")
+		if !found || strings.HasPrefix(prefix, "#") {
+			continue
+		}
+
+		// Newer evemu versions annotate lines with trailing comments.
+		rest, _, _ = strings.Cut(rest, "#")
+		rest = strings.TrimSpace(rest)
+		fields = strings.Fields(rest)
+
+		switch prefix {
+		case "N":
+			rec.Config.Name = rest
+		case "I":
+			err = parseEvemuID(fields, &rec.Config.ID)
+		case "P":
+			props, err = appendHexBytes(props, fields)
+		case "B":
+			err = parseEvemuBitmap(fields, bitmaps)
+		case "A":
+			err = parseEvemuAbs(fields, rec.Config.Abs)
+		case "E":
+			err = parseEvemuEvent(fields, rec)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	rec.Config.Codes = evemuCodes(bitmaps)
+
+	for code = range bitmaskFromBytes(props).Iterate() {
+		rec.Config.Properties = append(rec.Config.Properties, Property(code))
+	}
+
+	return rec, nil
+}
+
+func parseEvemuID(fields []string, id *ID) error {
+	var (
+		values []uint64
+		err    error
+	)
+
+	values, err = parseUints(fields, 16, 16)
+	if err != nil || len(values) != 4 {
+		return fmt.Errorf("%w: bad I: line", ErrInvalidRecording)
+	}
+
+	*id = ID{
+		Bustype: uint16(values[0]),
+		Vendor:  uint16(values[1]),
+		Product: uint16(values[2]),
+		Version: uint16(values[3]),
+	}
+
+	return nil
+}
+
+func parseEvemuBitmap(fields []string, bitmaps map[mylib.InputEvent][]byte) error {
+	var (
+		eventType uint64
+		err       error
+	)
+
+	if len(fields) == 0 {
+		return fmt.Errorf("%w: bad B: line", ErrInvalidRecording)
+	}
+
+	eventType, err = strconv.ParseUint(fields[0], 16, 8)
+	if err != nil {
+		return fmt.Errorf("%w: bad B: line", ErrInvalidRecording)
+	}
+
+	bitmaps[mylib.InputEvent(eventType)], err = appendHexBytes(
+		bitmaps[mylib.InputEvent(eventType)],
+		fields[1:],
+	)
+
+	return err
+}
+
+func parseEvemuAbs(fields []string, abs map[mylib.InputCode]AbsInfo) error {
+	var (
+		code   uint64
+		values []int32
+		value  int64
+		field  string
+		info   AbsInfo
+		err    error
+	)
+
+	if len(fields) < 5 {
+		return fmt.Errorf("%w: bad A: line", ErrInvalidRecording)
+	}
+
+	code, err = strconv.ParseUint(fields[0], 16, 16)
+	if err != nil || code > ABS_MAX {
+		return fmt.Errorf("%w: bad A: line", ErrInvalidRecording)
+	}
+
+	for _, field = range fields[1:] {
+		value, err = strconv.ParseInt(field, 10, 32)
+		if err != nil {
+			return fmt.Errorf("%w: bad A: line", ErrInvalidRecording)
+		}
+
+		values = append(values, int32(value))
+	}
+
+	info = AbsInfo{Minimum: values[0], Maximum: values[1], Fuzz: values[2], Flat: values[3]}
+	if len(values) > 4 {
+		info.Resolution = values[4]
+	}
+
+	abs[mylib.InputCode(code)] = info
+
+	return nil
+}
+
+func parseEvemuEvent(fields []string, rec *Recording) error {
+	var (
+		sec, usec string
+		found     bool
+		ids       []uint64
+		times     []uint64
+		value     int64
+		err       error
+	)
+
+	if len(fields) != 4 {
+		return fmt.Errorf("%w: bad E: line", ErrInvalidRecording)
+	}
+
+	sec, usec, found = strings.Cut(fields[0], ".")
+	if !found {
+		return fmt.Errorf("%w: bad E: line", ErrInvalidRecording)
+	}
+
+	times, err = parseUints([]string{sec, usec}, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad E: line", ErrInvalidRecording)
+	}
+
+	ids, err = parseUints(fields[1:3], 16, 16)
+	if err != nil {
+		return fmt.Errorf("%w: bad E: line", ErrInvalidRecording)
+	}
+
+	value, err = strconv.ParseInt(fields[3], 10, 32)
+	if err != nil {
+		return fmt.Errorf("%w: bad E: line", ErrInvalidRecording)
+	}
+
+	rec.Events = append(rec.Events, Event{
+		Sec:   times[0],
+		Usec:  times[1],
+		Type:  uint16(ids[0]),
+		Code:  uint16(ids[1]),
+		Value: int32(value),
+	})
+
+	return nil
+}
+
+// evemuCodes converts the B: bitmaps of an evemu recording to the codes
+// of each event type. The EV_SYN bitmap lists the supported event
+// types, so types without codes of their own, such as EV_REP, are kept.
+func evemuCodes(bitmaps map[mylib.InputEvent][]byte) map[mylib.InputEvent][]mylib.InputCode {
+	var (
+		codes map[mylib.InputEvent][]mylib.InputCode
+		ev    mylib.InputEvent
+		code  mylib.InputCode
+		ok    bool
+	)
+
+	codes = make(map[mylib.InputEvent][]mylib.InputCode)
+
+	for code = range bitmaskFromBytes(bitmaps[EV_SYN]).Iterate() {
+		ev = mylib.InputEvent(code)
+		if ev == EV_SYN {
+			continue
+		}
+
+		_, ok = MaxCodes(ev)
+		if !ok {
+			continue
+		}
+
+		codes[ev] = bitmaskFromBytes(bitmaps[ev]).Codes()
+	}
+
+	return codes
+}
+
+func appendHexBytes(buf []byte, fields []string) ([]byte, error) {
+	var (
+		values []uint64
+		value  uint64
+		err    error
+	)
+
+	values, err = parseUints(fields, 16, 8)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad bitmap byte", ErrInvalidRecording)
+	}
+
+	for _, value = range values {
+		buf = append(buf, byte(value))
+	}
+
+	return buf, nil
+}
+
+func parseUints(fields []string, base, bitSize int) ([]uint64, error) {
+	var (
+		values []uint64
+		value  uint64
+		field  string
+		err    error
+	)
+
+	values = make([]uint64, 0, len(fields))
+
+	for _, field = range fields {
+		value, err = strconv.ParseUint(field, base, bitSize)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,59 @@
+//go:build linux
+
+package input
+
+// RedactFilter is a [Filter] that hides what was typed while keeping
+// timing and modifiers, so diagnostic logs and recordings can be shared
+// without leaking passwords.
+//
+// Key events in the printable range, such as letters, digits,
+// punctuation, space, and the keypad, have their code replaced with
+// [KEY_UNKNOWN]. Modifiers, function keys, and navigation keys pass
+// through unchanged. [MSC_SCAN] events are dropped, since the raw
+// scancode would reveal the key.
+type RedactFilter struct {
+	out []Event
+}
+
+var _ Filter = (*RedactFilter)(nil)
+
+// Filter redacts the printable key events of frame.
+func (filter *RedactFilter) Filter(frame []Event) []Event {
+	var ev Event
+
+	filter.out = filter.out[:0]
+
+	for _, ev = range frame {
+		if ev.Type == EV_MSC && ev.Code == MSC_SCAN {
+			continue
+		}
+
+		if ev.Type == EV_KEY && printableKey(ev.Code) {
+			ev.Code = KEY_UNKNOWN
+		}
+
+		filter.out = append(filter.out, ev)
+	}
+
+	return filter.out
+}
+
+func printableKey(code uint16) bool {
+	switch {
+	case code >= KEY_1 && code <= KEY_EQUAL,
+		code >= KEY_Q && code <= KEY_RIGHTBRACE,
+		code >= KEY_A && code <= KEY_GRAVE,
+		code >= KEY_BACKSLASH && code <= KEY_SLASH,
+		code >= KEY_KP7 && code <= KEY_KPDOT:
+		return true
+	}
+
+	switch code {
+	case KEY_KPASTERISK, KEY_SPACE, KEY_102ND, KEY_RO, KEY_KPSLASH,
+		KEY_KPEQUAL, KEY_KPPLUSMINUS, KEY_KPCOMMA, KEY_YEN,
+		KEY_KPLEFTPAREN, KEY_KPRIGHTPAREN:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,257 @@
+//go:build linux
+
+package input
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/andrieee44/mylib"
+)
+
+// Mapping is a remapping table for a [Remapper].
+type Mapping struct {
+	// Keys translates physical key codes to other key codes before
+	// anything else is applied, such as KEY_CAPSLOCK to KEY_LEFTCTRL.
+	// Mapping a code to [KEY_RESERVED] swallows the key.
+	Keys map[mylib.InputCode]mylib.InputCode
+
+	// Layers are applied to the translated keys. See [LayerFilter].
+	Layers []Layer
+
+	// Chords are recognized on the keys emitted by the layers. See
+	// [ChordFilter].
+	Chords []Chord
+
+	// ChordTimeout is how close together the keys of a chord must be
+	// pressed.
+	ChordTimeout time.Duration
+}
+
+// Filter returns a [Filter] applying the mapping: Keys, then Layers,
+// then Chords.
+func (mapping Mapping) Filter() Filter {
+	var pipeline Pipeline
+
+	if len(mapping.Keys) != 0 {
+		pipeline = append(pipeline, &keyMapFilter{keys: mapping.Keys})
+	}
+
+	if len(mapping.Layers) != 0 {
+		pipeline = append(pipeline, NewLayerFilter(mapping.Layers...))
+	}
+
+	if len(mapping.Chords) != 0 {
+		pipeline = append(pipeline, NewChordFilter(mapping.ChordTimeout, mapping.Chords...))
+	}
+
+	return pipeline
+}
+
+// Remapper grabs a source device, runs its events through a [Filter],
+// and emits the result from a [VirtualDevice], so that every other
+// reader, including the display server, sees the remapped device
+// instead of the source. It is the core of a key remapping or macro
+// daemon.
+type Remapper struct {
+	src    *Device
+	reader *SyncReader
+	dst    *VirtualDevice
+	filter Filter
+}
+
+// NewRemapper opens and grabs the event device at path and creates a
+// virtual copy of it named after it with " (remapped)" appended. The
+// copy supports every keyboard key, so that filters may emit keys the
+// source lacks.
+func NewRemapper(path string, filter Filter) (*Remapper, error) {
+	var (
+		remapper *Remapper
+		config   VirtualConfig
+		code     mylib.InputCode
+		err      error
+	)
+
+	remapper = &Remapper{filter: filter}
+
+	// A non-blocking descriptor lets Run wait through the runtime poller,
+	// which can be interrupted when the context is done.
+	remapper.src, err = NewDevice(path, NonBlocking(), GrabOnOpen())
+	if err != nil {
+		return nil, fmt.Errorf("input.NewRemapper: %w", err)
+	}
+
+	remapper.reader, err = NewSyncReader(remapper.src)
+	if err == nil {
+		config, err = remapper.src.VirtualConfig()
+	}
+
+	if err != nil {
+		_ = remapper.src.Close()
+
+		return nil, fmt.Errorf("input.NewRemapper: %w", err)
+	}
+
+	config.Name += " (remapped)"
+	for code = KEY_ESC; code < BTN_MISC; code++ {
+		if !slices.Contains(config.Codes[EV_KEY], code) {
+			config.Codes[EV_KEY] = append(config.Codes[EV_KEY], code)
+		}
+	}
+
+	remapper.dst, err = NewVirtualDevice(config)
+	if err != nil {
+		_ = remapper.src.Close()
+
+		return nil, fmt.Errorf("input.NewRemapper: %w", err)
+	}
+
+	return remapper, nil
+}
+
+// VirtualDevice returns the device the remapped events are emitted
+// from.
+func (remapper *Remapper) VirtualDevice() *VirtualDevice {
+	return remapper.dst
+}
+
+// Run remaps events until ctx is done, in which case it returns
+// ctx.Err(), or until reading or writing fails. If the filter is a
+// [Flusher], the events it holds back are flushed once no events arrive
+// for its delay.
+func (remapper *Remapper) Run(ctx context.Context) error {
+	var (
+		stop     func() bool
+		flusher  Flusher
+		after    time.Duration
+		flushing bool
+		frame    []Event
+		ev       Event
+		err      error
+	)
+
+	stop = context.AfterFunc(ctx, func() {
+		_ = remapper.src.file.SetReadDeadline(time.Now())
+	})
+	defer stop()
+
+	flusher, _ = remapper.filter.(Flusher)
+
+	for {
+		if flusher != nil && len(frame) == 0 {
+			after, flushing = flusher.FlushAfter()
+			if flushing {
+				err = remapper.src.file.SetReadDeadline(time.Now().Add(after))
+			} else {
+				err = remapper.src.file.SetReadDeadline(time.Time{})
+			}
+
+			if err != nil {
+				return fmt.Errorf("Remapper.Run: %w", err)
+			}
+
+			// The deadline set above may have replaced the one set when
+			// ctx was done.
+			if ctx.Err() != nil {
+				return fmt.Errorf("Remapper.Run: %w", ctx.Err())
+			}
+		}
+
+		ev, err = waitEvent(remapper.src, remapper.reader.ReadEvent)
+		if errors.Is(err, os.ErrDeadlineExceeded) && ctx.Err() != nil {
+			return fmt.Errorf("Remapper.Run: %w", ctx.Err())
+		}
+
+		if errors.Is(err, os.ErrDeadlineExceeded) && flushing {
+			err = remapper.write(flusher.Flush())
+			if err != nil {
+				return fmt.Errorf("Remapper.Run: %w", err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			return fmt.Errorf("Remapper.Run: %w", err)
+		}
+
+		frame = append(frame, ev)
+		if ev.Type != EV_SYN || ev.Code != SYN_REPORT {
+			continue
+		}
+
+		err = remapper.write(remapper.filter.Filter(frame))
+		if err != nil {
+			return fmt.Errorf("Remapper.Run: %w", err)
+		}
+
+		frame = frame[:0]
+	}
+}
+
+// write emits events from the virtual device.
+func (remapper *Remapper) write(events []Event) error {
+	var (
+		ev  Event
+		err error
+	)
+
+	for _, ev = range events {
+		err = remapper.dst.WriteEvent(ev)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close removes the virtual device and closes the source device,
+// releasing its grab.
+func (remapper *Remapper) Close() error {
+	var err error
+
+	err = errors.Join(remapper.dst.Close(), remapper.src.Close())
+	if err != nil {
+		return fmt.Errorf("Remapper.Close: %w", err)
+	}
+
+	return nil
+}
+
+// keyMapFilter is a [Filter] translating key codes through a fixed map.
+type keyMapFilter struct {
+	keys map[mylib.InputCode]mylib.InputCode
+	out  []Event
+}
+
+func (filter *keyMapFilter) Filter(frame []Event) []Event {
+	var (
+		ev   Event
+		code mylib.InputCode
+		ok   bool
+	)
+
+	filter.out = filter.out[:0]
+
+	for _, ev = range frame {
+		if ev.Type == EV_KEY {
+			code, ok = filter.keys[mylib.InputCode(ev.Code)]
+			if ok && code == KEY_RESERVED {
+				continue
+			}
+
+			if ok {
+				ev.Code = uint16(code)
+			}
+		}
+
+		filter.out = append(filter.out, ev)
+	}
+
+	return filter.out
+}
@@ -0,0 +1,92 @@
+//go:build linux
+
+package input_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+func TestMappingFilter(t *testing.T) {
+	var (
+		mapping input.Mapping
+		tests   []filterTest
+		test    filterTest
+	)
+
+	mapping = input.Mapping{
+		Keys: map[mylib.InputCode]mylib.InputCode{
+			input.KEY_CAPSLOCK: input.KEY_LEFTCTRL,
+			input.KEY_INSERT:   input.KEY_RESERVED,
+		},
+		Layers: []input.Layer{{
+			Map:       map[mylib.InputCode]mylib.InputCode{input.KEY_H: input.KEY_J},
+			Momentary: []mylib.InputCode{input.KEY_RIGHTALT},
+		}},
+		Chords:       testChords(),
+		ChordTimeout: 50 * time.Millisecond,
+	}
+
+	tests = []filterTest{
+		{
+			name: "keys",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				frame(key(1, input.KEY_INSERT, 1)),
+				frame(key(2, input.KEY_A, 1)),
+			},
+			want: []input.Event{
+				key(0, input.KEY_LEFTCTRL, 1), syn(0),
+				syn(1),
+				key(2, input.KEY_A, 1), syn(2),
+			},
+		},
+		{
+			name: "chords on layer output",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_RIGHTALT, 1)),
+				frame(key(10, input.KEY_H, 1)),
+				frame(key(20, input.KEY_K, 1)),
+				frame(key(30, input.KEY_H, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				syn(10),
+				key(20, input.KEY_ESC, 1), syn(20),
+				syn(20),
+				key(30, input.KEY_ESC, 0), syn(30),
+				syn(30),
+			},
+		},
+		{
+			name: "flushed through the chords",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_RIGHTALT, 1)),
+				frame(key(10, input.KEY_H, 1)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(0),
+				syn(10),
+				key(10, input.KEY_J, 1), syn(10),
+			},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkEvents(t, filterFrames(mapping.Filter(), test.frames, test.flush), test.want)
+		})
+	}
+}
+
+func TestMappingFilterEmpty(t *testing.T) {
+	var frames [][]input.Event
+
+	frames = [][]input.Event{frame(key(0, input.KEY_A, 1), key(0, input.KEY_A, 0))}
+
+	checkEvents(t, filterFrames(input.Mapping{}.Filter(), frames, true), frames[0])
+}
@@ -0,0 +1,433 @@
+//go:build linux
+
+package input
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/andrieee44/mylib"
+	"golang.org/x/sys/unix"
+)
+
+// State mirrors the state of a device as seen by a client through its
+// events: held keys, lit LEDs, closed switches, absolute axis values, and
+// multi-touch slots. Feed it every event with [State.Update], and call
+// [State.Resync] after a [SYN_DROPPED] to bring it back in line with the
+// kernel.
+type State struct {
+	keys     map[mylib.InputCode]bool
+	leds     map[mylib.InputCode]bool
+	switches map[mylib.InputCode]bool
+	abs      map[mylib.InputCode]int32
+	slots    map[mylib.InputCode][]int32
+	slot     int32
+}
+
+// SyncReader reads events from a device like [Device.ReadEvent], but
+// recovers from buffer overruns. When the kernel reports [SYN_DROPPED],
+// SyncReader discards the incomplete frame, calls [State.Resync], and
+// returns the synthetic events before continuing with the device's
+// events, so the caller's view of the device never diverges from the
+// kernel's.
+type SyncReader struct {
+	dev      *Device
+	state    *State
+	pending  []Event
+	dropping bool
+}
+
+// NewState returns the current state of dev, queried with ioctls.
+func NewState(dev *Device) (*State, error) {
+	var (
+		state *State
+		err   error
+	)
+
+	state, err = snapshot(dev)
+	if err != nil {
+		return nil, fmt.Errorf("input.NewState: %w", err)
+	}
+
+	return state, nil
+}
+
+// NewSyncReader returns a SyncReader for dev, seeded with its current
+// state.
+func NewSyncReader(dev *Device) (*SyncReader, error) {
+	var (
+		state *State
+		err   error
+	)
+
+	state, err = snapshot(dev)
+	if err != nil {
+		return nil, fmt.Errorf("input.NewSyncReader: %w", err)
+	}
+
+	return &SyncReader{dev: dev, state: state}, nil
+}
+
+// Update applies ev to the state.
+func (state *State) Update(ev Event) {
+	var code mylib.InputCode
+
+	code = mylib.InputCode(ev.Code)
+
+	switch ev.Type {
+	case EV_KEY:
+		state.keys[code] = ev.Value != 0
+	case EV_LED:
+		state.leds[code] = ev.Value != 0
+	case EV_SW:
+		state.switches[code] = ev.Value != 0
+	case EV_ABS:
+		switch {
+		case code == ABS_MT_SLOT:
+			state.slot = ev.Value
+		case state.slots[code] != nil:
+			if state.slot >= 0 && int(state.slot) < len(state.slots[code]) {
+				state.slots[code][state.slot] = ev.Value
+			}
+		default:
+			state.abs[code] = ev.Value
+		}
+	}
+}
+
+// Key reports whether the key or button code is held down.
+func (state *State) Key(code mylib.InputCode) bool {
+	return state.keys[code]
+}
+
+// LED reports whether the LED code is lit.
+func (state *State) LED(code mylib.InputCode) bool {
+	return state.leds[code]
+}
+
+// Switch reports whether the switch code is on.
+func (state *State) Switch(code mylib.InputCode) bool {
+	return state.switches[code]
+}
+
+// Abs returns the value of the absolute axis code. For multi-touch axes,
+// it returns the value in the current slot.
+func (state *State) Abs(code mylib.InputCode) int32 {
+	if code == ABS_MT_SLOT {
+		return state.slot
+	}
+
+	if state.slots[code] != nil {
+		return state.Slot(code, state.slot)
+	}
+
+	return state.abs[code]
+}
+
+// Slot returns the value of the multi-touch axis code in slot.
+func (state *State) Slot(code mylib.InputCode, slot int32) int32 {
+	if slot < 0 || int(slot) >= len(state.slots[code]) {
+		return 0
+	}
+
+	return state.slots[code][slot]
+}
+
+// Resync re-reads the key, LED, switch, absolute axis, and multi-touch
+// slot state of dev with ioctls, and returns the synthetic events that
+// take a client from the old state to the new one, terminated by a
+// [SYN_REPORT]. It returns no events if nothing changed. The state is
+// updated to match dev. The events are stamped with the current time of
+// the clock dev stamps its own events with, as set by
+// [Device.SetClock], so that they order correctly among them.
+func (state *State) Resync(dev *Device) ([]Event, error) {
+	var (
+		current *State
+		events  []Event
+		now     unix.Timespec
+		i       int
+		err     error
+	)
+
+	current, err = snapshot(dev)
+	if err != nil {
+		return nil, fmt.Errorf("State.Resync: %w", err)
+	}
+
+	events = state.diff(current)
+	*state = *current
+
+	if len(events) == 0 {
+		return nil, nil
+	}
+
+	err = unix.ClockGettime(dev.Clock(), &now)
+	if err != nil {
+		return nil, fmt.Errorf("State.Resync: %w", err)
+	}
+
+	events = append(events, Event{Type: EV_SYN, Code: SYN_REPORT})
+
+	for i = range events {
+		events[i].Sec, events[i].Usec = uint64(now.Sec), uint64(now.Nsec/1000)
+	}
+
+	return events, nil
+}
+
+// ReadEvent returns the next event, reading from the device when no
+// synthetic events are pending. [SYN_DROPPED] and the events of the frame
+// it interrupted are never returned.
+func (reader *SyncReader) ReadEvent() (Event, error) {
+	var (
+		ev  Event
+		err error
+	)
+
+	for {
+		if len(reader.pending) != 0 {
+			ev = reader.pending[0]
+			reader.pending = reader.pending[1:]
+
+			return ev, nil
+		}
+
+		ev, err = reader.dev.ReadEvent()
+		if err != nil {
+			return Event{}, fmt.Errorf("SyncReader.ReadEvent: %w", err)
+		}
+
+		switch {
+		case ev.Type == EV_SYN && ev.Code == SYN_DROPPED:
+			reader.dropping = true
+		case reader.dropping:
+			if ev.Type != EV_SYN || ev.Code != SYN_REPORT {
+				continue
+			}
+
+			reader.dropping = false
+
+			reader.pending, err = reader.state.Resync(reader.dev)
+			if err != nil {
+				return Event{}, fmt.Errorf("SyncReader.ReadEvent: %w", err)
+			}
+		default:
+			reader.state.Update(ev)
+
+			return ev, nil
+		}
+	}
+}
+
+// State returns the state tracked by the reader. It reflects every event
+// returned so far.
+func (reader *SyncReader) State() *State {
+	return reader.state
+}
+
+func snapshot(dev *Device) (*State, error) {
+	var (
+		state  *State
+		events []mylib.InputEvent
+		codes  []mylib.InputCode
+		code   mylib.InputCode
+		info   AbsInfo
+		sws    []Switch
+		sw     Switch
+		err    error
+	)
+
+	state = &State{
+		keys:     make(map[mylib.InputCode]bool),
+		leds:     make(map[mylib.InputCode]bool),
+		switches: make(map[mylib.InputCode]bool),
+		abs:      make(map[mylib.InputCode]int32),
+		slots:    make(map[mylib.InputCode][]int32),
+	}
+
+	events, err = dev.Events()
+	if err != nil {
+		return nil, err
+	}
+
+	if slices.Contains(events, EV_KEY) {
+		codes, err = dev.KeyState()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, code = range codes {
+			state.keys[code] = true
+		}
+	}
+
+	if slices.Contains(events, EV_LED) {
+		codes, err = dev.LEDState()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, code = range codes {
+			state.leds[code] = true
+		}
+	}
+
+	if slices.Contains(events, EV_SW) {
+		sws, err = dev.Switches()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sw = range sws {
+			state.switches[sw.Code] = sw.On
+		}
+	}
+
+	if !slices.Contains(events, EV_ABS) {
+		return state, nil
+	}
+
+	codes, err = dev.Codes(EV_ABS)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, code = range codes {
+		info, err = dev.AbsInfo(code)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case code == ABS_MT_SLOT:
+			state.slot = info.Value
+		case code > ABS_MT_SLOT && slices.Contains(codes, ABS_MT_SLOT):
+			state.slots[code], err = dev.MTSlots(code)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			state.abs[code] = info.Value
+		}
+	}
+
+	return state, nil
+}
+
+// diff returns the events taking a client from state to current, without
+// a terminating [SYN_REPORT] or timestamps.
+func (state *State) diff(current *State) []Event {
+	var events []Event
+
+	events = append(events, diffBool(EV_KEY, state.keys, current.keys)...)
+	events = append(events, diffBool(EV_LED, state.leds, current.leds)...)
+	events = append(events, diffBool(EV_SW, state.switches, current.switches)...)
+	events = append(events, diffAbs(state.abs, current.abs)...)
+	events = append(events, state.diffSlots(current)...)
+
+	return events
+}
+
+func diffBool(eventType mylib.InputEvent, old, current map[mylib.InputCode]bool) []Event {
+	var (
+		events []Event
+		code   mylib.InputCode
+		value  int32
+	)
+
+	for _, code = range sortedCodes(old, current) {
+		if old[code] == current[code] {
+			continue
+		}
+
+		value = 0
+		if current[code] {
+			value = 1
+		}
+
+		events = append(events, Event{
+			Type:  uint16(eventType),
+			Code:  uint16(code),
+			Value: value,
+		})
+	}
+
+	return events
+}
+
+func diffAbs(old, current map[mylib.InputCode]int32) []Event {
+	var (
+		events []Event
+		code   mylib.InputCode
+	)
+
+	for _, code = range sortedCodes(old, current) {
+		if old[code] == current[code] {
+			continue
+		}
+
+		events = append(events, Event{
+			Type:  EV_ABS,
+			Code:  uint16(code),
+			Value: current[code],
+		})
+	}
+
+	return events
+}
+
+// diffSlots returns the events updating the multi-touch slots from state
+// to current, selecting each changed slot with ABS_MT_SLOT and finally
+// restoring the kernel's current slot.
+func (state *State) diffSlots(current *State) []Event {
+	var (
+		events   []Event
+		codes    []mylib.InputCode
+		code     mylib.InputCode
+		slot     int32
+		selected int32
+		slots    int
+	)
+
+	codes = slices.Sorted(maps.Keys(current.slots))
+	selected = state.slot
+
+	for _, code = range codes {
+		slots = max(slots, len(current.slots[code]))
+	}
+
+	for slot = range int32(slots) {
+		for _, code = range codes {
+			if state.Slot(code, slot) == current.Slot(code, slot) {
+				continue
+			}
+
+			if selected != slot {
+				events = append(events, Event{Type: EV_ABS, Code: ABS_MT_SLOT, Value: slot})
+				selected = slot
+			}
+
+			events = append(events, Event{
+				Type:  EV_ABS,
+				Code:  uint16(code),
+				Value: current.Slot(code, slot),
+			})
+		}
+	}
+
+	if selected != current.slot {
+		events = append(events, Event{Type: EV_ABS, Code: ABS_MT_SLOT, Value: current.slot})
+	}
+
+	return events
+}
+
+func sortedCodes[V any](old, current map[mylib.InputCode]V) []mylib.InputCode {
+	var codes []mylib.InputCode
+
+	codes = slices.AppendSeq(slices.Collect(maps.Keys(old)), maps.Keys(current))
+	slices.Sort(codes)
+
+	return slices.Compact(codes)
+}
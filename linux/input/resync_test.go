@@ -0,0 +1,323 @@
+//go:build linux
+
+package input
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/andrieee44/mylib"
+	"golang.org/x/sys/unix"
+)
+
+type stateTest struct {
+	name   string
+	events []Event
+	want   *State
+}
+
+type diffTest struct {
+	name    string
+	old     *State
+	current *State
+	want    []Event
+}
+
+// testState returns an empty state of a device with two multi-touch
+// slots, as [snapshot] would build it, with fields applied on top.
+func testState(fields func(state *State)) *State {
+	var state *State
+
+	state = &State{
+		keys:     make(map[mylib.InputCode]bool),
+		leds:     make(map[mylib.InputCode]bool),
+		switches: make(map[mylib.InputCode]bool),
+		abs:      make(map[mylib.InputCode]int32),
+		slots: map[mylib.InputCode][]int32{
+			ABS_MT_POSITION_X:  {0, 0},
+			ABS_MT_TRACKING_ID: {-1, -1},
+		},
+	}
+
+	if fields != nil {
+		fields(state)
+	}
+
+	return state
+}
+
+func TestStateUpdate(t *testing.T) {
+	var (
+		tests []stateTest
+		test  stateTest
+		state *State
+		ev    Event
+	)
+
+	tests = []stateTest{
+		{
+			name: "keys",
+			events: []Event{
+				{Type: EV_KEY, Code: KEY_A, Value: 1},
+				{Type: EV_KEY, Code: KEY_B, Value: 1},
+				{Type: EV_KEY, Code: KEY_B, Value: 2},
+				{Type: EV_KEY, Code: KEY_A, Value: 0},
+			},
+			want: testState(func(state *State) {
+				state.keys[KEY_A] = false
+				state.keys[KEY_B] = true
+			}),
+		},
+		{
+			name: "LEDs and switches",
+			events: []Event{
+				{Type: EV_LED, Code: LED_CAPSL, Value: 1},
+				{Type: EV_SW, Code: SW_LID, Value: 1},
+				{Type: EV_SW, Code: SW_LID, Value: 0},
+			},
+			want: testState(func(state *State) {
+				state.leds[LED_CAPSL] = true
+				state.switches[SW_LID] = false
+			}),
+		},
+		{
+			name:   "absolute axis",
+			events: []Event{{Type: EV_ABS, Code: ABS_X, Value: 10}, {Type: EV_ABS, Code: ABS_X, Value: 20}},
+			want:   testState(func(state *State) { state.abs[ABS_X] = 20 }),
+		},
+		{
+			name: "slots",
+			events: []Event{
+				{Type: EV_ABS, Code: ABS_MT_SLOT, Value: 1},
+				{Type: EV_ABS, Code: ABS_MT_TRACKING_ID, Value: 7},
+				{Type: EV_ABS, Code: ABS_MT_POSITION_X, Value: 100},
+			},
+			want: testState(func(state *State) {
+				state.slot = 1
+				state.slots[ABS_MT_TRACKING_ID][1] = 7
+				state.slots[ABS_MT_POSITION_X][1] = 100
+			}),
+		},
+		{
+			name: "slot out of range",
+			events: []Event{
+				{Type: EV_ABS, Code: ABS_MT_SLOT, Value: 2},
+				{Type: EV_ABS, Code: ABS_MT_TRACKING_ID, Value: 7},
+				{Type: EV_ABS, Code: ABS_MT_SLOT, Value: -1},
+				{Type: EV_ABS, Code: ABS_MT_POSITION_X, Value: 100},
+			},
+			want: testState(func(state *State) { state.slot = -1 }),
+		},
+		{
+			name: "other events",
+			events: []Event{
+				{Type: EV_SYN, Code: SYN_REPORT},
+				{Type: EV_REL, Code: REL_X, Value: 5},
+				{Type: EV_MSC, Code: MSC_SCAN, Value: 0x70004},
+			},
+			want: testState(nil),
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			state = testState(nil)
+
+			for _, ev = range test.events {
+				state.Update(ev)
+			}
+
+			if !reflect.DeepEqual(state, test.want) {
+				t.Errorf("state = %+v, want %+v", state, test.want)
+			}
+		})
+	}
+}
+
+func TestStateQueries(t *testing.T) {
+	var state *State
+
+	state = testState(func(state *State) {
+		state.keys[KEY_A] = true
+		state.leds[LED_NUML] = true
+		state.switches[SW_TABLET_MODE] = true
+		state.abs[ABS_X] = 10
+		state.slot = 1
+		state.slots[ABS_MT_POSITION_X][1] = 100
+	})
+
+	if !state.Key(KEY_A) || state.Key(KEY_B) {
+		t.Errorf("Key(KEY_A), Key(KEY_B) = %t, %t, want true, false", state.Key(KEY_A), state.Key(KEY_B))
+	}
+
+	if !state.LED(LED_NUML) || !state.Switch(SW_TABLET_MODE) {
+		t.Errorf("LED, Switch = %t, %t, want true, true", state.LED(LED_NUML), state.Switch(SW_TABLET_MODE))
+	}
+
+	if state.Abs(ABS_X) != 10 || state.Abs(ABS_MT_SLOT) != 1 {
+		t.Errorf("Abs(ABS_X), Abs(ABS_MT_SLOT) = %d, %d, want 10, 1", state.Abs(ABS_X), state.Abs(ABS_MT_SLOT))
+	}
+
+	// Multi-touch axes read from the current slot.
+	if state.Abs(ABS_MT_POSITION_X) != 100 {
+		t.Errorf("Abs(ABS_MT_POSITION_X) = %d, want 100", state.Abs(ABS_MT_POSITION_X))
+	}
+
+	if state.Slot(ABS_MT_TRACKING_ID, 0) != -1 || state.Slot(ABS_MT_TRACKING_ID, 2) != 0 {
+		t.Errorf(
+			"Slot(ABS_MT_TRACKING_ID, 0), Slot(ABS_MT_TRACKING_ID, 2) = %d, %d, want -1, 0",
+			state.Slot(ABS_MT_TRACKING_ID, 0),
+			state.Slot(ABS_MT_TRACKING_ID, 2),
+		)
+	}
+}
+
+func TestStateDiff(t *testing.T) {
+	var (
+		tests []diffTest
+		test  diffTest
+		got   []Event
+	)
+
+	tests = []diffTest{
+		{
+			name:    "unchanged",
+			old:     testState(func(state *State) { state.keys[KEY_A] = false }),
+			current: testState(nil),
+		},
+		{
+			name: "keys in code order",
+			old: testState(func(state *State) {
+				state.keys[KEY_A] = true
+				state.keys[KEY_C] = true
+			}),
+			current: testState(func(state *State) {
+				state.keys[KEY_B] = true
+				state.keys[KEY_C] = true
+			}),
+			want: []Event{
+				{Type: EV_KEY, Code: KEY_A, Value: 0},
+				{Type: EV_KEY, Code: KEY_B, Value: 1},
+			},
+		},
+		{
+			name: "every kind",
+			old:  testState(func(state *State) { state.leds[LED_CAPSL] = true }),
+			current: testState(func(state *State) {
+				state.keys[BTN_LEFT] = true
+				state.switches[SW_LID] = true
+				state.abs[ABS_Y] = -5
+			}),
+			want: []Event{
+				{Type: EV_KEY, Code: BTN_LEFT, Value: 1},
+				{Type: EV_LED, Code: LED_CAPSL, Value: 0},
+				{Type: EV_SW, Code: SW_LID, Value: 1},
+				{Type: EV_ABS, Code: ABS_Y, Value: -5},
+			},
+		},
+		{
+			name: "slots",
+			old:  testState(func(state *State) { state.slots[ABS_MT_TRACKING_ID][0] = 3 }),
+			current: testState(func(state *State) {
+				state.slots[ABS_MT_TRACKING_ID][1] = 7
+				state.slots[ABS_MT_POSITION_X][1] = 100
+			}),
+			want: []Event{
+				{Type: EV_ABS, Code: ABS_MT_TRACKING_ID, Value: -1},
+				{Type: EV_ABS, Code: ABS_MT_SLOT, Value: 1},
+				{Type: EV_ABS, Code: ABS_MT_POSITION_X, Value: 100},
+				{Type: EV_ABS, Code: ABS_MT_TRACKING_ID, Value: 7},
+				{Type: EV_ABS, Code: ABS_MT_SLOT, Value: 0},
+			},
+		},
+		{
+			name:    "current slot",
+			old:     testState(nil),
+			current: testState(func(state *State) { state.slot = 1 }),
+			want:    []Event{{Type: EV_ABS, Code: ABS_MT_SLOT, Value: 1}},
+		},
+		{
+			name: "slot left selected",
+			old:  testState(func(state *State) { state.slot = 1 }),
+			current: testState(func(state *State) {
+				state.slot = 1
+				state.slots[ABS_MT_TRACKING_ID][1] = 7
+			}),
+			want: []Event{{Type: EV_ABS, Code: ABS_MT_TRACKING_ID, Value: 7}},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got = test.old.diff(test.current)
+			if !slices.Equal(got, test.want) {
+				t.Errorf("diff = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSyncReaderDropped(t *testing.T) {
+	var (
+		pipeReader, pipeWriter *os.File
+		feed, dev              *Device
+		reader                 *SyncReader
+		events                 []Event
+		ev, want               Event
+		err                    error
+	)
+
+	pipeReader, pipeWriter, err = os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feed = NewDeviceFromFile(pipeWriter)
+	defer feed.Close()
+
+	dev = NewDeviceFromFile(pipeReader)
+	defer dev.Close()
+
+	events = []Event{
+		{Sec: 1, Type: EV_KEY, Code: KEY_A, Value: 1},
+		{Sec: 1, Type: EV_SYN, Code: SYN_REPORT},
+		{Sec: 2, Type: EV_KEY, Code: KEY_B, Value: 1},
+		{Sec: 2, Type: EV_SYN, Code: SYN_DROPPED},
+		{Sec: 2, Type: EV_KEY, Code: KEY_C, Value: 1},
+		{Sec: 2, Type: EV_SYN, Code: SYN_REPORT},
+	}
+
+	for _, ev = range events {
+		err = feed.WriteEvent(ev)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reader = &SyncReader{dev: dev, state: testState(nil)}
+
+	for _, want = range events[:3] {
+		ev, err = reader.ReadEvent()
+		if err != nil || ev != want {
+			t.Fatalf("ReadEvent = %v, %v, want %v", ev, err, want)
+		}
+	}
+
+	// A pipe answers no ioctls, so the resync at the end of the dropped
+	// frame fails, after the frame has been discarded.
+	_, err = reader.ReadEvent()
+	if !errors.Is(err, unix.ENOTTY) {
+		t.Errorf("ReadEvent = %v, want ENOTTY", err)
+	}
+
+	if !reader.State().Key(KEY_B) || reader.State().Key(KEY_C) {
+		t.Errorf(
+			"Key(KEY_B), Key(KEY_C) = %t, %t, want true, false",
+			reader.State().Key(KEY_B),
+			reader.State().Key(KEY_C),
+		)
+	}
+}
@@ -0,0 +1,468 @@
+//go:build linux
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/andrieee44/mylib"
+	"golang.org/x/sys/unix"
+)
+
+// DeviceInfo holds the metadata sysfs exposes for an evdev device. It is
+// read with [SysInfo] without opening the device node.
+type DeviceInfo struct {
+	// Name is the device name, as returned by [Device.Name].
+	Name string
+
+	// Phys is the physical topology path, or empty if the driver sets
+	// none.
+	Phys string
+
+	// Uniq is the unique identifier, or empty if the driver sets none.
+	Uniq string
+
+	// ID holds the bus type, vendor, product, and version.
+	ID ID
+
+	// Modalias is the device's module alias, such as
+	// "input:b0003v046DpC24Fe0111-e0,1,4,...", used to match drivers.
+	Modalias string
+
+	// Properties lists the device's input properties.
+	Properties []Property
+
+	// Codes maps each supported event type to its supported codes, as
+	// [Device.Codes] would report them. Event types that sysfs has no
+	// capabilities file for, such as EV_SYN and EV_REP, are omitted.
+	Codes map[mylib.InputEvent][]mylib.InputCode
+}
+
+var sysfsCapabilities map[mylib.InputEvent]string = map[mylib.InputEvent]string{
+	EV_KEY: "key",
+	EV_REL: "rel",
+	EV_ABS: "abs",
+	EV_MSC: "msc",
+	EV_SW:  "sw",
+	EV_LED: "led",
+	EV_SND: "snd",
+	EV_FF:  "ff",
+}
+
+// System is the view of the host's /sys and /dev trees used to discover
+// devices. It reads them through an [fs.FS], so that tests can run
+// discovery against an in-memory tree, such as an [fstest.MapFS] holding
+// a fake sys/class/input and dev/input, instead of real hardware.
+//
+// Symbolic links, such as those of /dev/input/by-id and the sysfs device
+// links, are followed if the FS has ReadLink and Lstat methods, as
+// [fstest.MapFS] has since Go 1.25. Otherwise they are treated as
+// regular entries.
+//
+// [fstest.MapFS]: https://pkg.go.dev/testing/fstest#MapFS
+type System struct {
+	fsys fs.FS
+}
+
+// readLinkFS is an [fs.FS] that can read symbolic links, like
+// fs.ReadLinkFS of Go 1.25.
+type readLinkFS interface {
+	fs.FS
+	ReadLink(name string) (string, error)
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+// hostFS is the root directory of the host, able to read symbolic links
+// with any Go version.
+type hostFS struct {
+	fs.FS
+}
+
+// maxSymlinks is how many symbolic links [System.resolve] follows before
+// giving up, like the kernel's limit.
+const maxSymlinks = 40
+
+// NewSystem returns a System reading from fsys, whose root stands for
+// the root directory of the host. If fsys is nil, the host's root
+// directory is used, like os.DirFS("/").
+func NewSystem(fsys fs.FS) *System {
+	if fsys == nil {
+		fsys = hostFS{FS: os.DirFS("/")}
+	}
+
+	return &System{fsys: fsys}
+}
+
+func (hostFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return os.Readlink("/" + name)
+}
+
+func (hostFS) Lstat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return os.Lstat("/" + name)
+}
+
+// resolve returns name, a path in the FS, with every symbolic link in it
+// followed, like [filepath.EvalSymlinks].
+func (sys *System) resolve(name string) (string, error) {
+	var (
+		links    readLinkFS
+		ok       bool
+		parts    []string
+		part     string
+		resolved string
+		next     string
+		info     fs.FileInfo
+		target   string
+		hops     int
+		err      error
+	)
+
+	links, ok = sys.fsys.(readLinkFS)
+	if !ok {
+		return name, nil
+	}
+
+	parts = strings.Split(name, "/")
+
+	for len(parts) != 0 {
+		part, parts = parts[0], parts[1:]
+
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			resolved = path.Dir(resolved)
+			if resolved == "." {
+				resolved = ""
+			}
+
+			continue
+		}
+
+		next = path.Join(resolved, part)
+
+		info, err = links.Lstat(next)
+		if err != nil {
+			return "", err
+		}
+
+		if info.Mode()&fs.ModeSymlink == 0 {
+			resolved = next
+
+			continue
+		}
+
+		hops++
+		if hops > maxSymlinks {
+			return "", &fs.PathError{Op: "resolve", Path: name, Err: unix.ELOOP}
+		}
+
+		target, err = links.ReadLink(next)
+		if err != nil {
+			return "", err
+		}
+
+		if path.IsAbs(target) {
+			resolved = ""
+		}
+
+		parts = append(strings.Split(target, "/"), parts...)
+	}
+
+	if resolved == "" {
+		return ".", nil
+	}
+
+	return resolved, nil
+}
+
+// SysInfo reads the metadata of an event device using NewSystem(nil).
+func SysInfo(event string) (*DeviceInfo, error) {
+	return NewSystem(nil).SysInfo(event)
+}
+
+// EventNodes returns the paths of the event device nodes in /dev/input,
+// such as "/dev/input/event3", in lexical order.
+func (sys *System) EventNodes() ([]string, error) {
+	var (
+		matches []string
+		i       int
+		err     error
+	)
+
+	matches, err = fs.Glob(sys.fsys, "dev/input/event*")
+	if err != nil {
+		return nil, fmt.Errorf("System.EventNodes: %w", err)
+	}
+
+	for i = range matches {
+		matches[i] = "/" + matches[i]
+	}
+
+	return matches, nil
+}
+
+// Match returns the paths of the event device nodes whose metadata, as
+// read by [System.SysInfo], satisfies all of the predicates. No device
+// is opened. Nodes that disappear while they are inspected, as devices
+// are unplugged, are skipped.
+func (sys *System) Match(predicates ...Predicate) ([]string, error) {
+	var (
+		nodes     []string
+		node      string
+		matches   []string
+		info      *DeviceInfo
+		predicate Predicate
+		match     bool
+		err       error
+	)
+
+	nodes, err = sys.EventNodes()
+	if err != nil {
+		return nil, fmt.Errorf("System.Match: %w", err)
+	}
+
+	for _, node = range nodes {
+		info, err = sys.SysInfo(node)
+		if errors.Is(err, fs.ErrNotExist) {
+			// The device was unplugged after it was listed.
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("System.Match: %w", err)
+		}
+
+		match = true
+		for _, predicate = range predicates {
+			if !predicate(info) {
+				match = false
+
+				break
+			}
+		}
+
+		if match {
+			matches = append(matches, node)
+		}
+	}
+
+	return matches, nil
+}
+
+// SysInfo reads the metadata of the event device event, such as "event3"
+// or "/dev/input/event3", from /sys/class/input/event3/device. It works
+// even when the process lacks permission to open the device node.
+func (sys *System) SysInfo(event string) (*DeviceInfo, error) {
+	var (
+		info    *DeviceInfo
+		dir     string
+		types   []mylib.InputEvent
+		ev      mylib.InputEvent
+		maxCode uint
+		props   []mylib.InputCode
+		prop    mylib.InputCode
+		err     error
+	)
+
+	dir = path.Join("sys/class/input", filepath.Base(event), "device")
+	info = &DeviceInfo{Codes: make(map[mylib.InputEvent][]mylib.InputCode)}
+
+	info.Name, err = sysfsString(sys.fsys, dir, "name")
+	if err != nil {
+		return nil, fmt.Errorf("System.SysInfo: %w", err)
+	}
+
+	info.Phys, err = sysfsString(sys.fsys, dir, "phys")
+	if err != nil {
+		return nil, fmt.Errorf("System.SysInfo: %w", err)
+	}
+
+	info.Uniq, err = sysfsString(sys.fsys, dir, "uniq")
+	if err != nil {
+		return nil, fmt.Errorf("System.SysInfo: %w", err)
+	}
+
+	info.Modalias, err = sysfsString(sys.fsys, dir, "modalias")
+	if err != nil {
+		return nil, fmt.Errorf("System.SysInfo: %w", err)
+	}
+
+	info.ID, err = sysfsID(sys.fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("System.SysInfo: %w", err)
+	}
+
+	props, err = sysfsBitmap(sys.fsys, dir, "properties", INPUT_PROP_MAX)
+	if err != nil {
+		return nil, fmt.Errorf("System.SysInfo: %w", err)
+	}
+
+	info.Properties = make([]Property, 0, len(props))
+	for _, prop = range props {
+		info.Properties = append(info.Properties, Property(prop))
+	}
+
+	types, err = sysfsEvents(sys.fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("System.SysInfo: %w", err)
+	}
+
+	for _, ev = range types {
+		maxCode, _ = MaxCodes(ev)
+
+		info.Codes[ev], err = sysfsBitmap(
+			sys.fsys,
+			path.Join(dir, "capabilities"),
+			sysfsCapabilities[ev],
+			maxCode,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("System.SysInfo: %w", err)
+		}
+	}
+
+	return info, nil
+}
+
+// Events returns the supported event types in ascending order.
+func (info *DeviceInfo) Events() []mylib.InputEvent {
+	var (
+		events []mylib.InputEvent
+		ev     mylib.InputEvent
+	)
+
+	events = make([]mylib.InputEvent, 0, len(info.Codes))
+	for ev = range info.Codes {
+		events = append(events, ev)
+	}
+
+	slices.Sort(events)
+
+	return events
+}
+
+func sysfsString(fsys fs.FS, dir, name string) (string, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	data, err = fs.ReadFile(fsys, path.Join(dir, name))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+func sysfsID(fsys fs.FS, dir string) (ID, error) {
+	var (
+		id     ID
+		fields []*uint16
+		names  []string
+		value  string
+		parsed uint64
+		i      int
+		err    error
+	)
+
+	fields = []*uint16{&id.Bustype, &id.Vendor, &id.Product, &id.Version}
+	names = []string{"bustype", "vendor", "product", "version"}
+
+	for i = range fields {
+		value, err = sysfsString(fsys, path.Join(dir, "id"), names[i])
+		if err != nil {
+			return ID{}, err
+		}
+
+		parsed, err = strconv.ParseUint(value, 16, 16)
+		if err != nil {
+			return ID{}, err
+		}
+
+		*fields[i] = uint16(parsed)
+	}
+
+	return id, nil
+}
+
+func sysfsEvents(fsys fs.FS, dir string) ([]mylib.InputEvent, error) {
+	var (
+		codes  []mylib.InputCode
+		code   mylib.InputCode
+		events []mylib.InputEvent
+		ok     bool
+		err    error
+	)
+
+	codes, err = sysfsBitmap(fsys, path.Join(dir, "capabilities"), "ev", EV_MAX)
+	if err != nil {
+		return nil, err
+	}
+
+	events = make([]mylib.InputEvent, 0, len(codes))
+	for _, code = range codes {
+		_, ok = sysfsCapabilities[mylib.InputEvent(code)]
+		if !ok {
+			continue
+		}
+
+		events = append(events, mylib.InputEvent(code))
+	}
+
+	return events, nil
+}
+
+// sysfsBitmap parses a sysfs bitmap, written as space-separated
+// hexadecimal words of the kernel's long size, most significant first.
+func sysfsBitmap(fsys fs.FS, dir, name string, maxCode uint) ([]mylib.InputCode, error) {
+	var (
+		value string
+		words []string
+		mask  *Bitmask
+		word  uint64
+		index uint
+		bit   uint
+		err   error
+	)
+
+	value, err = sysfsString(fsys, dir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	words = strings.Fields(value)
+	mask = NewBitmask(maxCode)
+
+	for index = range uint(len(words)) {
+		word, err = strconv.ParseUint(words[uint(len(words))-1-index], 16, strconv.IntSize)
+		if err != nil {
+			return nil, fmt.Errorf("/%s: %w", path.Join(dir, name), err)
+		}
+
+		for bit = range uint(strconv.IntSize) {
+			if word&(1<<bit) != 0 && index*strconv.IntSize+bit <= maxCode {
+				mask.Set(mylib.InputCode(index*strconv.IntSize + bit))
+			}
+		}
+	}
+
+	return mask.Codes(), nil
+}
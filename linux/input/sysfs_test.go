@@ -0,0 +1,271 @@
+//go:build linux
+
+package input_test
+
+import (
+	"errors"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+// addDevice adds the sysfs attributes of an event device with the given
+// name, vendor and product, and key capability bitmap to fsys.
+func addDevice(fsys fstest.MapFS, event, name, vendor, product, keys string) {
+	var (
+		dir   string
+		files map[string]string
+		file  string
+		data  string
+	)
+
+	dir = "sys/class/input/" + event + "/device/"
+	files = map[string]string{
+		"name":             name + "\n",
+		"phys":             "usb-0000:00:14.0-2/input0\n",
+		"uniq":             "\n",
+		"modalias":         "input:b0003v" + vendor + "p" + product + "e0111-e0,1\n",
+		"id/bustype":       "0003\n",
+		"id/vendor":        vendor + "\n",
+		"id/product":       product + "\n",
+		"id/version":       "0111\n",
+		"properties":       "0\n",
+		"capabilities/ev":  "3\n",
+		"capabilities/key": keys + "\n",
+	}
+
+	for file, data = range files {
+		fsys[dir+file] = &fstest.MapFile{Data: []byte(data)}
+	}
+
+	fsys["dev/input/"+event] = &fstest.MapFile{Mode: fs.ModeDevice}
+}
+
+type eventNodesTest struct {
+	name string
+	fsys fstest.MapFS
+	want []string
+}
+
+type sysInfoErrorTest struct {
+	name     string
+	file     string
+	data     string
+	remove   bool
+	notExist bool
+}
+
+type matchTest struct {
+	name       string
+	predicates []input.Predicate
+	want       []string
+}
+
+func TestSystemEventNodes(t *testing.T) {
+	var (
+		tests []eventNodesTest
+		test  eventNodesTest
+		nodes []string
+		err   error
+	)
+
+	tests = []eventNodesTest{
+		{
+			name: "sorted",
+			fsys: fstest.MapFS{
+				"dev/input/event10": {Mode: fs.ModeDevice},
+				"dev/input/event2":  {Mode: fs.ModeDevice},
+				"dev/input/event0":  {Mode: fs.ModeDevice},
+			},
+			want: []string{"/dev/input/event0", "/dev/input/event10", "/dev/input/event2"},
+		},
+		{
+			name: "legacy nodes skipped",
+			fsys: fstest.MapFS{
+				"dev/input/event1":     {Mode: fs.ModeDevice},
+				"dev/input/mice":       {Mode: fs.ModeDevice},
+				"dev/input/js0":        {Mode: fs.ModeDevice},
+				"dev/input/by-id/link": {Data: []byte("../event1")},
+			},
+			want: []string{"/dev/input/event1"},
+		},
+		{
+			name: "no devices",
+			fsys: fstest.MapFS{"dev/null": {Mode: fs.ModeDevice}},
+			want: nil,
+		},
+		{
+			name: "missing directory",
+			fsys: fstest.MapFS{},
+			want: nil,
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodes, err = input.NewSystem(test.fsys).EventNodes()
+			if err != nil {
+				t.Fatalf("EventNodes: %v", err)
+			}
+
+			if !slices.Equal(nodes, test.want) {
+				t.Errorf("EventNodes = %q, want %q", nodes, test.want)
+			}
+		})
+	}
+}
+
+func TestSystemSysInfo(t *testing.T) {
+	var (
+		fsys fstest.MapFS
+		info *input.DeviceInfo
+		want []mylib.InputCode
+		err  error
+	)
+
+	fsys = fstest.MapFS{}
+	addDevice(fsys, "event3", "Test Keyboard", "046d", "c31c", "10000 0 40000000")
+
+	info, err = input.NewSystem(fsys).SysInfo("/dev/input/event3")
+	if err != nil {
+		t.Fatalf("SysInfo: %v", err)
+	}
+
+	if info.Name != "Test Keyboard" || info.Phys != "usb-0000:00:14.0-2/input0" || info.Uniq != "" {
+		t.Errorf("Name, Phys, Uniq = %q, %q, %q", info.Name, info.Phys, info.Uniq)
+	}
+
+	if info.ID != (input.ID{Bustype: 0x3, Vendor: 0x46d, Product: 0xc31c, Version: 0x111}) {
+		t.Errorf("ID = %+v", info.ID)
+	}
+
+	if !slices.Equal(info.Events(), []mylib.InputEvent{input.EV_KEY}) {
+		t.Errorf("Events = %v, want [EV_KEY]", info.Events())
+	}
+
+	// The bitmap is written most significant word first, in words of
+	// the kernel's long size.
+	want = []mylib.InputCode{input.KEY_A, 2*strconv.IntSize + 16}
+	if !slices.Equal(info.Codes[input.EV_KEY], want) {
+		t.Errorf("Codes[EV_KEY] = %v, want %v", info.Codes[input.EV_KEY], want)
+	}
+}
+
+func TestSystemSysInfoErrors(t *testing.T) {
+	var (
+		tests []sysInfoErrorTest
+		test  sysInfoErrorTest
+		fsys  fstest.MapFS
+		err   error
+	)
+
+	tests = []sysInfoErrorTest{
+		{name: "missing name", file: "name", remove: true, notExist: true},
+		{name: "missing id", file: "id/vendor", remove: true, notExist: true},
+		{name: "missing capabilities", file: "capabilities/ev", remove: true, notExist: true},
+		{name: "malformed id", file: "id/vendor", data: "xyz\n"},
+		{name: "oversized id", file: "id/product", data: "10000\n"},
+		{name: "malformed bitmap", file: "capabilities/key", data: "0 zz\n"},
+		{name: "malformed properties", file: "properties", data: "-1\n"},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fsys = fstest.MapFS{}
+			addDevice(fsys, "event3", "Test Keyboard", "046d", "c31c", "40000000")
+
+			if test.remove {
+				delete(fsys, "sys/class/input/event3/device/"+test.file)
+			} else {
+				fsys["sys/class/input/event3/device/"+test.file] = &fstest.MapFile{
+					Data: []byte(test.data),
+				}
+			}
+
+			_, err = input.NewSystem(fsys).SysInfo("event3")
+			if err == nil {
+				t.Fatal("SysInfo succeeded")
+			}
+
+			if errors.Is(err, fs.ErrNotExist) != test.notExist {
+				t.Errorf("SysInfo = %v, want fs.ErrNotExist %t", err, test.notExist)
+			}
+		})
+	}
+}
+
+func TestSystemMatch(t *testing.T) {
+	var (
+		fsys  fstest.MapFS
+		tests []matchTest
+		test  matchTest
+		nodes []string
+		err   error
+	)
+
+	fsys = fstest.MapFS{}
+	addDevice(fsys, "event1", "Test Keyboard", "046d", "c31c", "40000000")
+	addDevice(fsys, "event2", "Test Mouse", "046d", "c077", "0")
+
+	// event3 was unplugged after /dev/input was listed.
+	fsys["dev/input/event3"] = &fstest.MapFile{Mode: fs.ModeDevice}
+
+	tests = []matchTest{
+		{
+			name: "all",
+			want: []string{"/dev/input/event1", "/dev/input/event2"},
+		},
+		{
+			name:       "name",
+			predicates: []input.Predicate{input.ByName(regexp.MustCompile("Mouse"))},
+			want:       []string{"/dev/input/event2"},
+		},
+		{
+			name:       "capability",
+			predicates: []input.Predicate{input.ByCapability(input.EV_KEY, input.KEY_A)},
+			want:       []string{"/dev/input/event1"},
+		},
+		{
+			name: "every predicate",
+			predicates: []input.Predicate{
+				input.ByVendorProduct(0x046d, 0xc077),
+				input.ByCapability(input.EV_KEY, input.KEY_A),
+			},
+			want: nil,
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			nodes, err = input.NewSystem(fsys).Match(test.predicates...)
+			if err != nil {
+				t.Fatalf("Match: %v", err)
+			}
+
+			if !slices.Equal(nodes, test.want) {
+				t.Errorf("Match = %q, want %q", nodes, test.want)
+			}
+		})
+	}
+}
+
+func TestSystemMatchMalformed(t *testing.T) {
+	var (
+		fsys fstest.MapFS
+		err  error
+	)
+
+	fsys = fstest.MapFS{}
+	addDevice(fsys, "event1", "Test Keyboard", "046d", "c31c", "zz")
+
+	_, err = input.NewSystem(fsys).Match()
+	if err == nil {
+		t.Error("Match succeeded with a malformed bitmap")
+	}
+}
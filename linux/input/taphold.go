@@ -0,0 +1,188 @@
+//go:build linux
+
+package input
+
+import (
+	"time"
+
+	"github.com/andrieee44/mylib"
+)
+
+// TapHold is a [Filter] giving a key two functions: tapping it emits Tap,
+// while holding it emits Hold, typically a modifier. The classic use is
+// Caps Lock acting as Escape when tapped and as Control when held.
+//
+// A press of Key is held back until it resolves. It resolves as a hold
+// once the key is down for at least Timeout, judged by the timestamps of
+// later events, including autorepeat, or by [TapHold.Flush] if the
+// device reports nothing else by then. [Remapper] calls Flush on its
+// own; other readers should wait with a read deadline from
+// [TapHold.FlushAfter]. It resolves as a tap if the key is released
+// earlier. If another key is pressed first, HoldOnInterrupt
+// decides: if set, the press resolves as a hold, so Key+X acts as
+// Hold+X; otherwise it resolves as a tap emitted before X.
+type TapHold struct {
+	// Key is the physical key code to intercept.
+	Key mylib.InputCode
+
+	// Tap is the key code emitted when Key is tapped.
+	Tap mylib.InputCode
+
+	// Hold is the key code emitted while Key is held.
+	Hold mylib.InputCode
+
+	// Timeout is how long Key must be held to resolve as a hold.
+	Timeout time.Duration
+
+	// HoldOnInterrupt resolves a pending press as a hold, instead of a
+	// tap, when another key is pressed before Timeout.
+	HoldOnInterrupt bool
+
+	state     tapHoldState
+	pressedAt time.Duration
+	last      time.Duration
+	out       []Event
+}
+
+type tapHoldState int
+
+const (
+	tapHoldIdle tapHoldState = iota
+	tapHoldPending
+	tapHoldHolding
+	tapHoldTapped
+)
+
+var (
+	_ Filter  = (*TapHold)(nil)
+	_ Flusher = (*TapHold)(nil)
+)
+
+// Filter applies tap-hold resolution to frame.
+func (filter *TapHold) Filter(frame []Event) []Event {
+	var ev Event
+
+	filter.out = filter.out[:0]
+
+	for _, ev = range frame {
+		filter.last = eventTime(ev)
+
+		if filter.state == tapHoldPending &&
+			filter.last-filter.pressedAt >= filter.Timeout {
+			filter.press(ev, filter.Hold)
+			filter.state = tapHoldHolding
+		}
+
+		if ev.Type != EV_KEY {
+			filter.out = append(filter.out, ev)
+
+			continue
+		}
+
+		if mylib.InputCode(ev.Code) == filter.Key {
+			filter.handleKey(ev)
+
+			continue
+		}
+
+		if ev.Value == 1 && filter.state == tapHoldPending {
+			filter.interrupt(ev)
+		}
+
+		filter.out = append(filter.out, ev)
+	}
+
+	return filter.out
+}
+
+// FlushAfter implements [Flusher], returning the time left until a held
+// back press of Key resolves as a hold.
+func (filter *TapHold) FlushAfter() (time.Duration, bool) {
+	if filter.state != tapHoldPending {
+		return 0, false
+	}
+
+	return max(filter.pressedAt+filter.Timeout-filter.last, 0), true
+}
+
+// Flush implements [Flusher], resolving a held-back press of Key as a
+// hold, stamped with the time it resolved at.
+func (filter *TapHold) Flush() []Event {
+	if filter.state != tapHoldPending {
+		return nil
+	}
+
+	filter.out = nil
+	filter.press(eventAt(filter.pressedAt+filter.Timeout), filter.Hold)
+	filter.state = tapHoldHolding
+
+	return filter.out
+}
+
+func (filter *TapHold) handleKey(ev Event) {
+	switch ev.Value {
+	case 1:
+		filter.state = tapHoldPending
+		filter.pressedAt = eventTime(ev)
+	case 0:
+		switch filter.state {
+		case tapHoldPending:
+			filter.press(ev, filter.Tap)
+			filter.release(ev, filter.Tap)
+		case tapHoldHolding:
+			filter.release(ev, filter.Hold)
+		case tapHoldIdle, tapHoldTapped:
+		}
+
+		filter.state = tapHoldIdle
+	}
+}
+
+func (filter *TapHold) interrupt(ev Event) {
+	if filter.HoldOnInterrupt {
+		filter.press(ev, filter.Hold)
+		filter.state = tapHoldHolding
+
+		return
+	}
+
+	filter.press(ev, filter.Tap)
+	filter.release(ev, filter.Tap)
+	filter.state = tapHoldTapped
+}
+
+func (filter *TapHold) press(at Event, code mylib.InputCode) {
+	filter.out = append(filter.out, synthKey(at, code, 1), synthSyn(at))
+}
+
+func (filter *TapHold) release(at Event, code mylib.InputCode) {
+	filter.out = append(filter.out, synthKey(at, code, 0), synthSyn(at))
+}
+
+func eventTime(ev Event) time.Duration {
+	return time.Duration(ev.Sec)*time.Second +
+		time.Duration(ev.Usec)*time.Microsecond
+}
+
+// eventAt returns an event stamped with at, for passing to [synthKey]
+// and [synthSyn].
+func eventAt(at time.Duration) Event {
+	return Event{
+		Sec:  uint64(at / time.Second),
+		Usec: uint64(at % time.Second / time.Microsecond),
+	}
+}
+
+func synthKey(at Event, code mylib.InputCode, value int32) Event {
+	return Event{
+		Sec:   at.Sec,
+		Usec:  at.Usec,
+		Type:  EV_KEY,
+		Code:  uint16(code),
+		Value: value,
+	}
+}
+
+func synthSyn(at Event) Event {
+	return Event{Sec: at.Sec, Usec: at.Usec, Type: EV_SYN, Code: SYN_REPORT}
+}
@@ -0,0 +1,211 @@
+//go:build linux
+
+package input_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+type tapHoldTest struct {
+	name            string
+	holdOnInterrupt bool
+	frames          [][]input.Event
+	flush           bool
+	want            []input.Event
+}
+
+func TestTapHold(t *testing.T) {
+	var (
+		tests []tapHoldTest
+		test  tapHoldTest
+	)
+
+	tests = []tapHoldTest{
+		{
+			name: "tap",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				frame(key(50, input.KEY_CAPSLOCK, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(50, input.KEY_ESC, 1), syn(50),
+				key(50, input.KEY_ESC, 0), syn(50),
+				syn(50),
+			},
+		},
+		{
+			name: "hold through autorepeat",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				frame(key(250, input.KEY_CAPSLOCK, 2)),
+				frame(key(300, input.KEY_CAPSLOCK, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(250, input.KEY_LEFTCTRL, 1), syn(250),
+				syn(250),
+				key(300, input.KEY_LEFTCTRL, 0), syn(300),
+				syn(300),
+			},
+		},
+		{
+			name: "hold on timeout",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(0),
+				key(200, input.KEY_LEFTCTRL, 1), syn(200),
+			},
+		},
+		{
+			name: "released after timeout without other events",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				frame(key(500, input.KEY_CAPSLOCK, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(500, input.KEY_LEFTCTRL, 1), syn(500),
+				key(500, input.KEY_LEFTCTRL, 0), syn(500),
+				syn(500),
+			},
+		},
+		{
+			name: "interrupted as tap",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				frame(key(50, input.KEY_A, 1)),
+				frame(key(100, input.KEY_CAPSLOCK, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(50, input.KEY_ESC, 1), syn(50),
+				key(50, input.KEY_ESC, 0), syn(50),
+				key(50, input.KEY_A, 1), syn(50),
+				syn(100),
+			},
+		},
+		{
+			name:            "interrupted as hold",
+			holdOnInterrupt: true,
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				frame(key(50, input.KEY_A, 1)),
+				frame(key(60, input.KEY_A, 0)),
+				frame(key(100, input.KEY_CAPSLOCK, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(50, input.KEY_LEFTCTRL, 1), syn(50),
+				key(50, input.KEY_A, 1), syn(50),
+				key(60, input.KEY_A, 0), syn(60),
+				key(100, input.KEY_LEFTCTRL, 0), syn(100),
+				syn(100),
+			},
+		},
+		{
+			name: "releases do not interrupt",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				frame(key(50, input.KEY_A, 0)),
+				frame(key(60, input.KEY_CAPSLOCK, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				key(50, input.KEY_A, 0), syn(50),
+				key(60, input.KEY_ESC, 1), syn(60),
+				key(60, input.KEY_ESC, 0), syn(60),
+				syn(60),
+			},
+		},
+		{
+			name: "SYN_DROPPED passes",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				{at(10, input.EV_SYN, input.SYN_DROPPED, 0)},
+				frame(key(20, input.KEY_CAPSLOCK, 0)),
+			},
+			want: []input.Event{
+				syn(0),
+				at(10, input.EV_SYN, input.SYN_DROPPED, 0),
+				key(20, input.KEY_ESC, 1), syn(20),
+				key(20, input.KEY_ESC, 0), syn(20),
+				syn(20),
+			},
+		},
+		{
+			name: "nothing to flush",
+			frames: [][]input.Event{
+				frame(key(0, input.KEY_CAPSLOCK, 1)),
+				frame(key(50, input.KEY_CAPSLOCK, 0)),
+			},
+			flush: true,
+			want: []input.Event{
+				syn(0),
+				key(50, input.KEY_ESC, 1), syn(50),
+				key(50, input.KEY_ESC, 0), syn(50),
+				syn(50),
+			},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkEvents(t, filterFrames(&input.TapHold{
+				Key:             input.KEY_CAPSLOCK,
+				Tap:             input.KEY_ESC,
+				Hold:            input.KEY_LEFTCTRL,
+				Timeout:         200 * time.Millisecond,
+				HoldOnInterrupt: test.holdOnInterrupt,
+			}, test.frames, test.flush), test.want)
+		})
+	}
+}
+
+func TestTapHoldFlushAfter(t *testing.T) {
+	var (
+		filter *input.TapHold
+		after  time.Duration
+		ok     bool
+	)
+
+	filter = &input.TapHold{
+		Key:     input.KEY_CAPSLOCK,
+		Tap:     input.KEY_ESC,
+		Hold:    input.KEY_LEFTCTRL,
+		Timeout: 200 * time.Millisecond,
+	}
+
+	_, ok = filter.FlushAfter()
+	if ok {
+		t.Error("FlushAfter reported a pending press before any")
+	}
+
+	filter.Filter(frame(key(0, input.KEY_CAPSLOCK, 1)))
+
+	after, ok = filter.FlushAfter()
+	if !ok || after != 200*time.Millisecond {
+		t.Errorf("FlushAfter = %v, %t, want 200ms, true", after, ok)
+	}
+
+	// The deadline counts from the press, not from the last frame.
+	filter.Filter(frame(at(150, input.EV_MSC, input.MSC_SCAN, 0x70039)))
+
+	after, ok = filter.FlushAfter()
+	if !ok || after != 50*time.Millisecond {
+		t.Errorf("FlushAfter = %v, %t, want 50ms, true", after, ok)
+	}
+
+	filter.Flush()
+
+	_, ok = filter.FlushAfter()
+	if ok {
+		t.Error("FlushAfter reported a pending press after Flush")
+	}
+}
@@ -0,0 +1,91 @@
+//go:build linux
+
+package input
+
+import "time"
+
+// FrameTime is the timing of a frame that carried an [MSC_TIMESTAMP]
+// event, as returned by [HardwareClock.Update].
+type FrameTime struct {
+	// Kernel is the timestamp the kernel gave the frame's SYN_REPORT, on
+	// the clock of the device.
+	Kernel time.Duration
+
+	// Hardware is the time at which the device sampled the frame, on its
+	// own clock. The device reports it as a 32-bit microsecond counter,
+	// which is unwrapped here, so Hardware keeps growing past the
+	// counter's 71-minute period.
+	Hardware time.Duration
+
+	// Jitter is how much longer the kernel took than the device between
+	// the previous timestamped frame and this one; it is negative when
+	// the frame was delivered sooner than the device sampled it. It is
+	// zero for the first frame.
+	Jitter time.Duration
+}
+
+// HardwareClock tracks the [MSC_TIMESTAMP] events that tablets, pens,
+// and touchscreens send with each frame, the time at which the device
+// sampled it. The hardware timestamp is free of the USB polling and
+// scheduling delays in the kernel's timestamp, which high-precision pen
+// applications rely on to compute velocities.
+//
+// The zero HardwareClock is ready to use.
+type HardwareClock struct {
+	pending  bool
+	value    uint32
+	started  bool
+	last     uint32
+	hardware time.Duration
+	kernel   time.Duration
+}
+
+// Update feeds ev to the clock. At the SYN_REPORT ending a frame that
+// carried an MSC_TIMESTAMP, it returns the frame's timing and true;
+// otherwise it returns false.
+func (clock *HardwareClock) Update(ev Event) (FrameTime, bool) {
+	var (
+		frame  FrameTime
+		kernel time.Duration
+	)
+
+	switch {
+	case ev.Type == EV_MSC && ev.Code == MSC_TIMESTAMP:
+		clock.pending = true
+		clock.value = uint32(ev.Value)
+
+		return FrameTime{}, false
+	case ev.Type == EV_SYN && ev.Code == SYN_DROPPED:
+		clock.pending = false
+		clock.started = false
+
+		return FrameTime{}, false
+	case ev.Type != EV_SYN || ev.Code != SYN_REPORT || !clock.pending:
+		return FrameTime{}, false
+	}
+
+	clock.pending = false
+	kernel = eventTime(ev)
+
+	if !clock.started {
+		clock.started = true
+		clock.hardware = time.Duration(clock.value) * time.Microsecond
+	} else {
+		// The subtraction wraps along with the counter.
+		clock.hardware += time.Duration(clock.value-clock.last) * time.Microsecond
+		frame.Jitter = kernel - clock.kernel - time.Duration(clock.value-clock.last)*time.Microsecond
+	}
+
+	clock.last = clock.value
+	clock.kernel = kernel
+	frame.Kernel = kernel
+	frame.Hardware = clock.hardware
+
+	return frame, true
+}
+
+// Reset forgets the previous timestamps, such as after the device was
+// resumed from suspend and restarted its counter.
+func (clock *HardwareClock) Reset() {
+	*clock = HardwareClock{}
+}
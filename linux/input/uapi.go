@@ -5,7 +5,8 @@ package input
 import "github.com/andrieee44/mylib/linux/ioctl"
 
 // Event represents a single input event delivered by the Linux kernel’s
-// input subsystem.
+// input subsystem. Its layout is the same on every architecture; events
+// are converted to and from the kernel's layout when read and written.
 type Event struct {
 	// Sec is the seconds portion of the event timestamp.
 	Sec uint64
@@ -29,6 +30,17 @@ type Event struct {
 	Value int32
 }
 
+// rawEvent is struct input_event as the kernel reads and writes it. Its
+// timestamp fields are as wide as a long, so its layout differs between
+// 32-bit and 64-bit architectures; [Event] is the same on both.
+type rawEvent struct {
+	Sec   kernelULong
+	Usec  kernelULong
+	Type  uint16
+	Code  uint16
+	Value int32
+}
+
 // ID identifies an input device by its bus type, vendor ID, product ID,
 // and version.
 type ID struct {
@@ -146,7 +158,9 @@ type Mask struct {
 	CodesSize uint32
 
 	// CodesPtr specifies the user‐space address of the codes bitmask buffer.
-	CodesPtr uint32
+	// The kernel always reads it as a 64-bit value, regardless of the
+	// architecture's pointer size.
+	CodesPtr uint64
 }
 
 // FFReplay defines the scheduling parameters for a force-feedback effect.
@@ -343,7 +357,7 @@ type FFPeriodicEffect struct {
 
 	// CustomLen is the number of samples in CustomData when Waveform is
 	// [FF_CUSTOM].
-	CustomLen uint16
+	CustomLen uint32
 
 	// CustomData points to a buffer of raw samples for a custom waveform.
 	// The driver copies this data, so it can be released after uploading.
@@ -417,8 +431,13 @@ type FFEffect struct {
 	// Replay defines the scheduling parameters for the effect.
 	Replay FFReplay
 
-	// U holds effect-specific parameters as a raw union payload.
-	U [32]byte
+	// The kernel aligns the union to the pointer in
+	// ff_periodic_effect, which leaves a hole before it.
+	_ uint16
+
+	// U holds effect-specific parameters as a raw union payload. Its
+	// size follows the pointer size of the architecture.
+	U [ffUnionSize]byte
 }
 
 const (
@@ -611,32 +630,32 @@ const (
 
 var (
 	// EVIOCGVERSION is the ioctl request code to get the evdev
-	// driver version. It reads an int into the provided variable.
-	EVIOCGVERSION = ioctl.IOR('E', 0x01, int(0))
+	// driver version. It reads an int32 into the provided variable.
+	EVIOCGVERSION = ioctl.IOR('E', 0x01, int32(0))
 
 	// EVIOCGID is the ioctl request code to retrieve the device identifier.
 	// It reads into an ID struct.
 	EVIOCGID = ioctl.IOR('E', 0x02, ID{})
 
 	// EVIOCGREP is the ioctl request code to get keyboard auto‐repeat
-	// settings. It reads a [2]uint: [0] = delay in ms, [1] = period in ms.
-	EVIOCGREP = ioctl.IOR('E', 0x03, [2]uint{})
+	// settings. It reads a [2]uint32: [0] = delay in ms, [1] = period in ms.
+	EVIOCGREP = ioctl.IOR('E', 0x03, [2]uint32{})
 
 	// EVIOCSREP is the ioctl request code to set keyboard auto‐repeat
-	// settings. It writes a [2]uint: [0] = delay in ms, [1] = period in ms.
-	EVIOCSREP = ioctl.IOW('E', 0x03, [2]uint{})
+	// settings. It writes a [2]uint32: [0] = delay in ms, [1] = period in ms.
+	EVIOCSREP = ioctl.IOW('E', 0x03, [2]uint32{})
 
 	// EVIOCGKEYCODE is the ioctl request code to get a simple keycode
-	// mapping. It reads a [2]uint: [0] = scancode, [1] = keycode.
-	EVIOCGKEYCODE = ioctl.IOR('E', 0x04, [2]uint{})
+	// mapping. It reads a [2]uint32: [0] = scancode, [1] = keycode.
+	EVIOCGKEYCODE = ioctl.IOR('E', 0x04, [2]uint32{})
 
 	// EVIOCGKEYCODE_V2 is the ioctl request code to get an extended
 	// keymap entry. It reads into a KeymapEntry struct.
 	EVIOCGKEYCODE_V2 = ioctl.IOR('E', 0x04, KeymapEntry{})
 
 	// EVIOCSKEYCODE is the ioctl request code to set a simple keycode
-	// mapping. It writes a [2]uint: [0] = scancode, [1] = keycode.
-	EVIOCSKEYCODE = ioctl.IOW('E', 0x04, [2]uint{})
+	// mapping. It writes a [2]uint32: [0] = scancode, [1] = keycode.
+	EVIOCSKEYCODE = ioctl.IOW('E', 0x04, [2]uint32{})
 
 	// EVIOCSKEYCODE_V2 is the ioctl request code to set an extended
 	// keymap entry. It writes in a KeymapEntry struct.
@@ -755,26 +774,26 @@ func EVIOCSFF() uint {
 // EVIOCRMFF returns the ioctl request code for erasing a previously
 // uploaded force-feedback effect.
 func EVIOCRMFF() uint {
-	return ioctl.IOW('E', 0x81, int(0))
+	return ioctl.IOW('E', 0x81, int32(0))
 }
 
 // EVIOCGEFFECTS returns the ioctl request code for querying how many
 // force-feedback effects the device supports.
 func EVIOCGEFFECTS() uint {
-	return ioctl.IOR('E', 0x84, int(0))
+	return ioctl.IOR('E', 0x84, int32(0))
 }
 
 // EVIOCGRAB returns the ioctl request code for grabbing or releasing an
 // input device. Passing a non-zero argument locks event delivery to the
 // calling process; zero releases it.
 func EVIOCGRAB() uint {
-	return ioctl.IOW('E', 0x90, int(0))
+	return ioctl.IOW('E', 0x90, int32(0))
 }
 
 // EVIOCREVOKE returns the ioctl request code for revoking a grab on an
 // input device.
 func EVIOCREVOKE() uint {
-	return ioctl.IOW('E', 0x91, int(0))
+	return ioctl.IOW('E', 0x91, int32(0))
 }
 
 // EVIOCGMASK returns the ioctl request code to retrieve the per-clienta
@@ -844,5 +863,5 @@ func EVIOCSMASK() uint {
 // EVIOCSCLOCKID returns the ioctl request code which sets the clock
 // source used to timestamp input events on a Linux event device.
 func EVIOCSCLOCKID() uint {
-	return ioctl.IOW('E', 0xa0, int(0))
+	return ioctl.IOW('E', 0xa0, int32(0))
 }
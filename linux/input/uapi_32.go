@@ -0,0 +1,12 @@
+//go:build linux && (386 || arm || mips || mipsle)
+
+package input
+
+// kernelULong is __kernel_ulong_t, the type of the timestamp fields of
+// struct input_event. It is 32 bits wide both for the legacy struct
+// timeval and for the __sec and __usec fields of time64 kernels.
+type kernelULong = uint32
+
+// ffUnionSize is the size of the union in struct ff_effect, which holds
+// a 32-bit pointer in struct ff_periodic_effect.
+const ffUnionSize = 28
@@ -0,0 +1,11 @@
+//go:build linux && (amd64 || arm64 || loong64 || mips64 || mips64le || ppc64 || ppc64le || riscv64 || s390x)
+
+package input
+
+// kernelULong is __kernel_ulong_t, the type of the timestamp fields of
+// struct input_event.
+type kernelULong = uint64
+
+// ffUnionSize is the size of the union in struct ff_effect, which holds
+// a 64-bit pointer in struct ff_periodic_effect.
+const ffUnionSize = 32
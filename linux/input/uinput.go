@@ -0,0 +1,390 @@
+//go:build linux
+
+package input
+
+import (
+	"encoding/binary"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/ioctl"
+	"golang.org/x/sys/unix"
+)
+
+// UINPUT_MAX_NAME_SIZE is the size of the name buffer in [uinput.h]'s
+// struct uinput_setup.
+//
+// [uinput.h]: https://github.com/torvalds/linux/blob/master/include/uapi/linux/uinput.h
+const UINPUT_MAX_NAME_SIZE = 80
+
+// uinputSetup is struct uinput_setup from uinput.h, used by
+// [UI_DEV_SETUP].
+type uinputSetup struct {
+	id           ID
+	name         [UINPUT_MAX_NAME_SIZE]byte
+	ffEffectsMax uint32
+}
+
+// uinputAbsSetup is struct uinput_abs_setup from uinput.h, used by
+// [UI_ABS_SETUP].
+type uinputAbsSetup struct {
+	code    uint16
+	_       uint16
+	absinfo AbsInfo
+}
+
+var (
+	// UI_DEV_CREATE is the ioctl request code that creates the device
+	// configured on a uinput file descriptor.
+	UI_DEV_CREATE = ioctl.IO('U', 1)
+
+	// UI_DEV_DESTROY is the ioctl request code that removes the device
+	// created on a uinput file descriptor.
+	UI_DEV_DESTROY = ioctl.IO('U', 2)
+
+	// UI_DEV_SETUP is the ioctl request code that sets the name, ID, and
+	// force-feedback capacity of a uinput device before it is created.
+	UI_DEV_SETUP = ioctl.IOW('U', 3, uinputSetup{})
+
+	// UI_ABS_SETUP is the ioctl request code that sets the parameters of
+	// one absolute axis of a uinput device before it is created.
+	UI_ABS_SETUP = ioctl.IOW('U', 4, uinputAbsSetup{})
+
+	// UI_SET_EVBIT is the ioctl request code that enables an event type.
+	// It takes the EV_* value by value.
+	UI_SET_EVBIT = ioctl.IOW('U', 100, int32(0))
+
+	// UI_SET_KEYBIT is the ioctl request code that enables a key code.
+	UI_SET_KEYBIT = ioctl.IOW('U', 101, int32(0))
+
+	// UI_SET_RELBIT is the ioctl request code that enables a relative
+	// axis.
+	UI_SET_RELBIT = ioctl.IOW('U', 102, int32(0))
+
+	// UI_SET_ABSBIT is the ioctl request code that enables an absolute
+	// axis.
+	UI_SET_ABSBIT = ioctl.IOW('U', 103, int32(0))
+
+	// UI_SET_MSCBIT is the ioctl request code that enables a
+	// miscellaneous event code.
+	UI_SET_MSCBIT = ioctl.IOW('U', 104, int32(0))
+
+	// UI_SET_LEDBIT is the ioctl request code that enables an LED.
+	UI_SET_LEDBIT = ioctl.IOW('U', 105, int32(0))
+
+	// UI_SET_SNDBIT is the ioctl request code that enables a sound.
+	UI_SET_SNDBIT = ioctl.IOW('U', 106, int32(0))
+
+	// UI_SET_FFBIT is the ioctl request code that enables a
+	// force-feedback effect type.
+	UI_SET_FFBIT = ioctl.IOW('U', 107, int32(0))
+
+	// UI_SET_SWBIT is the ioctl request code that enables a switch.
+	UI_SET_SWBIT = ioctl.IOW('U', 109, int32(0))
+
+	// UI_SET_PROPBIT is the ioctl request code that sets an input
+	// property.
+	UI_SET_PROPBIT = ioctl.IOW('U', 110, int32(0))
+)
+
+// UI_GET_SYSNAME returns the ioctl request code that reads the sysfs
+// name of a created uinput device, such as "input42". The length
+// parameter specifies the size of the buffer.
+func UI_GET_SYSNAME(length uint) uint {
+	return ioctl.IOC(ioctl.IOC_READ, 'U', 44, length)
+}
+
+// uinputCodeBits maps the event types a uinput device can enable codes
+// for to their UI_SET_*BIT request codes.
+var uinputCodeBits map[mylib.InputEvent]uint = map[mylib.InputEvent]uint{
+	EV_KEY: UI_SET_KEYBIT,
+	EV_REL: UI_SET_RELBIT,
+	EV_ABS: UI_SET_ABSBIT,
+	EV_MSC: UI_SET_MSCBIT,
+	EV_LED: UI_SET_LEDBIT,
+	EV_SND: UI_SET_SNDBIT,
+	EV_FF:  UI_SET_FFBIT,
+	EV_SW:  UI_SET_SWBIT,
+}
+
+// VirtualConfig describes the device [NewVirtualDevice] creates.
+type VirtualConfig struct {
+	// Name is the device name, truncated to 79 bytes.
+	Name string
+
+	// ID holds the bus type, vendor, product, and version.
+	ID ID
+
+	// Properties lists the device's input properties.
+	Properties []Property
+
+	// Codes maps each event type to the codes the device supports.
+	// EV_SYN is always enabled. An event type with no codes, such as
+	// EV_REP, is enabled on its own.
+	Codes map[mylib.InputEvent][]mylib.InputCode
+
+	// Abs holds the parameters of each absolute axis listed in
+	// Codes[EV_ABS]. Axes without an entry get a zero range.
+	Abs map[mylib.InputCode]AbsInfo
+
+	// FFEffects is how many force-feedback effects the device can hold.
+	FFEffects uint32
+}
+
+// VirtualDevice is an input device created through /dev/uinput. Events
+// written to it are delivered to readers of its event node as if they
+// came from hardware.
+type VirtualDevice struct {
+	file *os.File
+	fd   uintptr
+}
+
+// NewVirtualDevice creates a virtual input device with the capabilities
+// in config. The process needs write access to /dev/uinput. The device
+// exists until [VirtualDevice.Close] is called or the process exits.
+func NewVirtualDevice(config VirtualConfig) (*VirtualDevice, error) {
+	var (
+		vdev  *VirtualDevice
+		setup uinputSetup
+		err   error
+	)
+
+	vdev = &VirtualDevice{}
+
+	vdev.file, err = os.OpenFile("/dev/uinput", os.O_WRONLY, 0)
+	if err != nil {
+		return nil, fmt.Errorf("input.NewVirtualDevice: %w", err)
+	}
+
+	vdev.fd = vdev.file.Fd()
+
+	err = vdev.enable(config)
+	if err != nil {
+		_ = vdev.file.Close()
+
+		return nil, fmt.Errorf("input.NewVirtualDevice: %w", err)
+	}
+
+	setup.id = config.ID
+	setup.ffEffectsMax = config.FFEffects
+	copy(setup.name[:UINPUT_MAX_NAME_SIZE-1], config.Name)
+
+	err = ioctl.Any(vdev.fd, UI_DEV_SETUP, &setup)
+	if err == nil {
+		err = ioctl.Value(vdev.fd, UI_DEV_CREATE, 0)
+	}
+
+	if err != nil {
+		_ = vdev.file.Close()
+
+		return nil, fmt.Errorf("input.NewVirtualDevice: %w", err)
+	}
+
+	return vdev, nil
+}
+
+// VirtualConfig returns a [VirtualConfig] with the name, ID, properties,
+// capabilities, and axis parameters of dev, for creating a virtual copy
+// of it, for example to re-emit its events after remapping them.
+//
+// EV_REP is kept if dev supports it, so that the kernel auto-repeats the
+// copy's keys. Force feedback is left out: a [VirtualDevice] does not
+// service effect uploads, and clients uploading to it would block.
+func (dev *Device) VirtualConfig() (VirtualConfig, error) {
+	var (
+		caps  *Capabilities
+		codes map[mylib.InputEvent][]mylib.InputCode
+		err   error
+	)
+
+	caps, err = dev.Capabilities()
+	if err != nil {
+		return VirtualConfig{}, fmt.Errorf("Device.VirtualConfig: %w", err)
+	}
+
+	codes = maps.Clone(caps.Codes)
+	if codes == nil {
+		codes = make(map[mylib.InputEvent][]mylib.InputCode)
+	}
+
+	delete(codes, EV_FF)
+
+	if caps.Repeat {
+		codes[EV_REP] = nil
+	}
+
+	return VirtualConfig{
+		Name:       caps.Name,
+		ID:         caps.ID,
+		Properties: caps.Properties,
+		Codes:      codes,
+		Abs:        caps.Abs,
+	}, nil
+}
+
+// SysName returns the sysfs name of the device, such as "input42",
+// under /sys/devices/virtual/input.
+func (vdev *VirtualDevice) SysName() (string, error) {
+	var (
+		buf []byte
+		err error
+	)
+
+	buf = make([]byte, 64)
+
+	err = ioctl.Any(vdev.fd, UI_GET_SYSNAME(uint(len(buf))), &buf[0])
+	if err != nil {
+		return "", fmt.Errorf("VirtualDevice.SysName: %w", err)
+	}
+
+	return unix.ByteSliceToString(buf), nil
+}
+
+// EventPath returns the path of the device's event node, such as
+// "/dev/input/event17", for opening it with [NewDevice].
+func (vdev *VirtualDevice) EventPath() (string, error) {
+	var (
+		name    string
+		matches []string
+		err     error
+	)
+
+	name, err = vdev.SysName()
+	if err != nil {
+		return "", fmt.Errorf("VirtualDevice.EventPath: %w", err)
+	}
+
+	matches, err = filepath.Glob(filepath.Join("/sys/devices/virtual/input", name, "event*"))
+	if err != nil {
+		return "", fmt.Errorf("VirtualDevice.EventPath: %w", err)
+	}
+
+	if len(matches) == 0 {
+		return "", fmt.Errorf("VirtualDevice.EventPath: %s: %w", name, os.ErrNotExist)
+	}
+
+	return filepath.Join("/dev/input", filepath.Base(matches[0])), nil
+}
+
+// WriteEvent emits ev from the device. The kernel stamps the event with
+// the current time, so ev.Sec and ev.Usec are ignored. Readers see a
+// frame once a [SYN_REPORT] is written.
+func (vdev *VirtualDevice) WriteEvent(ev Event) error {
+	var (
+		raw rawEvent
+		err error
+	)
+
+	raw = newRawEvent(ev)
+
+	err = binary.Write(vdev.file, binary.NativeEndian, &raw)
+	if err != nil {
+		return fmt.Errorf("VirtualDevice.WriteEvent: %w", err)
+	}
+
+	return nil
+}
+
+// WriteFrame emits the events of frame followed by a [SYN_REPORT], so
+// that readers see them as one frame.
+func (vdev *VirtualDevice) WriteFrame(frame []Event) error {
+	var (
+		ev  Event
+		err error
+	)
+
+	for _, ev = range frame {
+		err = vdev.WriteEvent(ev)
+		if err != nil {
+			return fmt.Errorf("VirtualDevice.WriteFrame: %w", err)
+		}
+	}
+
+	err = vdev.WriteEvent(Event{Type: EV_SYN, Code: SYN_REPORT})
+	if err != nil {
+		return fmt.Errorf("VirtualDevice.WriteFrame: %w", err)
+	}
+
+	return nil
+}
+
+// Close removes the device and closes /dev/uinput.
+func (vdev *VirtualDevice) Close() error {
+	var err error
+
+	err = ioctl.Value(vdev.fd, UI_DEV_DESTROY, 0)
+	if err != nil {
+		_ = vdev.file.Close()
+
+		return fmt.Errorf("VirtualDevice.Close: %w", err)
+	}
+
+	err = vdev.file.Close()
+	if err != nil {
+		return fmt.Errorf("VirtualDevice.Close: %w", err)
+	}
+
+	return nil
+}
+
+func (vdev *VirtualDevice) enable(config VirtualConfig) error {
+	var (
+		ev    mylib.InputEvent
+		codes []mylib.InputCode
+		code  mylib.InputCode
+		req   uint
+		ok    bool
+		prop  Property
+		setup uinputAbsSetup
+		err   error
+	)
+
+	err = ioctl.Value(vdev.fd, UI_SET_EVBIT, EV_SYN)
+	if err != nil {
+		return err
+	}
+
+	for ev, codes = range config.Codes {
+		if ev == EV_SYN {
+			continue
+		}
+
+		err = ioctl.Value(vdev.fd, UI_SET_EVBIT, uintptr(ev))
+		if err != nil {
+			return err
+		}
+
+		req, ok = uinputCodeBits[ev]
+		if !ok {
+			continue
+		}
+
+		for _, code = range codes {
+			err = ioctl.Value(vdev.fd, req, uintptr(code))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, prop = range config.Properties {
+		err = ioctl.Value(vdev.fd, UI_SET_PROPBIT, uintptr(prop))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, code = range config.Codes[EV_ABS] {
+		setup = uinputAbsSetup{code: uint16(code), absinfo: config.Abs[code]}
+
+		err = ioctl.Any(vdev.fd, UI_ABS_SETUP, &setup)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
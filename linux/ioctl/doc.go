@@ -20,5 +20,13 @@
 // this explicit here. Please be sure to use the decoding macros
 // below from now on.
 //
+// Request codes are built the same way as with the C macros and passed
+// to [Any] or [Value]:
+//
+//	// #define EVIOCGVERSION _IOR('E', 0x01, int)
+//	var version int32
+//
+//	err := ioctl.Any(file.Fd(), ioctl.IOR('E', 0x01, int32(0)), &version)
+//
 // [ioctl.h]: https://github.com/torvalds/linux/blob/master/include/uapi/asm-generic/ioctl.h
 package ioctl
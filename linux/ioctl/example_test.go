@@ -0,0 +1,43 @@
+//go:build linux
+
+package ioctl_test
+
+import (
+	"fmt"
+
+	"github.com/andrieee44/mylib/linux/ioctl"
+)
+
+func ExampleIOC() {
+	var req uint
+
+	// #define EVIOCGVERSION _IOR('E', 0x01, int)
+	req = ioctl.IOC(ioctl.IOC_READ, 'E', 0x01, 4)
+
+	// The layout of the request code differs between architectures, so
+	// it is decoded rather than printed.
+	fmt.Println(
+		ioctl.IOC_DIR(req) == ioctl.IOC_READ,
+		string(rune(ioctl.IOC_TYPE(req))),
+		ioctl.IOC_NR(req),
+		ioctl.IOC_SIZE(req),
+	)
+	// Output:
+	// true E 1 4
+}
+
+func ExampleIOR() {
+	var req uint
+
+	// #define EVIOCGID _IOR('E', 0x02, struct input_id)
+	req = ioctl.IOR('E', 0x02, [4]uint16{})
+
+	fmt.Printf("read %t type %c nr %d size %d\n",
+		ioctl.IOC_DIR(req) == ioctl.IOC_READ,
+		rune(ioctl.IOC_TYPE(req)),
+		ioctl.IOC_NR(req),
+		ioctl.IOC_SIZE(req),
+	)
+	// Output:
+	// read true type E nr 2 size 8
+}
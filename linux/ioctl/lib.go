@@ -32,3 +32,42 @@ func Any[T any](fd uintptr, req uint, arg *T) error {
 
 	return nil
 }
+
+// AnyN is like [Any], but also returns the non-negative result of the
+// system call. Requests that fill a buffer, such as EVIOCGBIT and
+// EVIOCGNAME, return the number of bytes the kernel wrote, which may be
+// less than the size of the buffer.
+func AnyN[T any](fd uintptr, req uint, arg *T) (int, error) {
+	var (
+		n     uintptr
+		errno syscall.Errno
+	)
+
+	n, _, errno = unix.Syscall(
+		unix.SYS_IOCTL,
+		fd,
+		uintptr(req),
+		uintptr(unsafe.Pointer(arg)),
+	)
+	if errno != 0 {
+		return 0, errno
+	}
+
+	return int(n), nil
+}
+
+// Value performs an ioctl system call on the given file descriptor,
+// passing arg directly as the third argument instead of a pointer.
+// Some requests, such as EVIOCRMFF and EVIOCGRAB, take their argument
+// by value. On failure, the returned error is the underlying
+// [syscall.Errno].
+func Value(fd uintptr, req uint, arg uintptr) error {
+	var errno syscall.Errno
+
+	_, _, errno = unix.Syscall(unix.SYS_IOCTL, fd, uintptr(req), arg)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
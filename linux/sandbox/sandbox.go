@@ -0,0 +1,42 @@
+//go:build linux
+
+// Package sandbox forwards to
+// [github.com/andrieee44/mylib/x/linux/sandbox], where the experimental
+// process sandbox moved, so that existing imports keep building.
+//
+// Deprecated: Import github.com/andrieee44/mylib/x/linux/sandbox
+// instead. This package will be removed in a future release.
+package sandbox
+
+import "github.com/andrieee44/mylib/x/linux/sandbox"
+
+// ErrUnsupported is [sandbox.ErrUnsupported].
+var ErrUnsupported error = sandbox.ErrUnsupported
+
+type (
+	// Access is [sandbox.Access].
+	Access = sandbox.Access
+
+	// PathRule is [sandbox.PathRule].
+	PathRule = sandbox.PathRule
+
+	// Policy is [sandbox.Policy].
+	Policy = sandbox.Policy
+)
+
+// The access rights of [sandbox.Access].
+const (
+	AccessRead   = sandbox.AccessRead
+	AccessWrite  = sandbox.AccessWrite
+	AccessDevice = sandbox.AccessDevice
+)
+
+// Harden calls [sandbox.Harden].
+func Harden() error {
+	return sandbox.Harden()
+}
+
+// DefaultPolicy calls [sandbox.DefaultPolicy].
+func DefaultPolicy() *Policy {
+	return sandbox.DefaultPolicy()
+}
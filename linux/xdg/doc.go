@@ -2,5 +2,22 @@
 
 // Package xdg implements the [XDG Base Directory Specification].
 //
+// The *File functions open a file relative to a base directory,
+// creating it and its parent directories if needed, so that an
+// application opens its state file with:
+//
+//	file, err := xdg.StateFile("myapp/history")
+//	if err != nil {
+//		return err
+//	}
+//	defer file.Close()
+//
+// An [Environment] resolves the same paths from a custom lookup function
+// instead of the process environment, for tests and sandboxed helpers:
+//
+//	env := xdg.NewEnvironment(func(key string) string {
+//		return map[string]string{"XDG_CONFIG_HOME": "/tmp/config"}[key]
+//	})
+//
 // [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
 package xdg
@@ -0,0 +1,100 @@
+//go:build linux
+
+package xdg
+
+import (
+	"io/fs"
+	"os"
+)
+
+// Environment is a snapshot of the XDG base directories. Its fields are
+// resolved once, when the Environment is created, and are never re-read
+// from the process environment afterwards. Callers may override any
+// field, which lets libraries embedding this package honor per-instance
+// configuration and lets tests avoid global state.
+//
+// The package-level functions, such as [DataFile] and [ConfigDirs],
+// behave like the methods and fields of an Environment created with
+// NewEnvironment(nil) at the time of the call.
+type Environment struct {
+	// DataHome is the base directory for user-specific data files.
+	DataHome string
+
+	// ConfigHome is the base directory for user-specific configuration
+	// files.
+	ConfigHome string
+
+	// StateHome is the base directory for user-specific state files.
+	StateHome string
+
+	// CacheHome is the base directory for user-specific non-essential
+	// data files.
+	CacheHome string
+
+	// RuntimeDir is the base directory for user-specific runtime files.
+	RuntimeDir string
+
+	// DataDirs is the colon-separated, preference-ordered list of base
+	// directories to search for data files.
+	DataDirs string
+
+	// ConfigDirs is the colon-separated, preference-ordered list of base
+	// directories to search for configuration files.
+	ConfigDirs string
+
+	// FS is the filesystem searched by [Environment.FindConfig] and
+	// [Environment.FindData]. Its root stands for the root directory, so
+	// that tests can search an in-memory tree, such as an
+	// [fstest.MapFS], instead of the host. If nil, os.DirFS("/") is
+	// used.
+	//
+	// [fstest.MapFS]: https://pkg.go.dev/testing/fstest#MapFS
+	FS fs.FS
+}
+
+// NewEnvironment resolves the XDG base directories using getenv to look
+// up environment variables, applying the defaults of the
+// [XDG Base Directory Specification] to unset, empty, or relative
+// values. If getenv is nil, [os.Getenv] is used.
+//
+// [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
+func NewEnvironment(getenv func(string) string) *Environment {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	return &Environment{
+		DataHome:   resolve(getenv, dataHome).Value,
+		ConfigHome: resolve(getenv, configHome).Value,
+		StateHome:  resolve(getenv, stateHome).Value,
+		CacheHome:  resolve(getenv, cacheHome).Value,
+		RuntimeDir: resolve(getenv, runtimeDir).Value,
+		DataDirs:   resolve(getenv, dataDirs).Value,
+		ConfigDirs: resolve(getenv, configDirs).Value,
+	}
+}
+
+// DataFile is like [DataFile], but relative to env.DataHome.
+func (env *Environment) DataFile(relPath string) (*os.File, error) {
+	return xdgFile(env.DataHome, relPath)
+}
+
+// ConfigFile is like [ConfigFile], but relative to env.ConfigHome.
+func (env *Environment) ConfigFile(relPath string) (*os.File, error) {
+	return xdgFile(env.ConfigHome, relPath)
+}
+
+// StateFile is like [StateFile], but relative to env.StateHome.
+func (env *Environment) StateFile(relPath string) (*os.File, error) {
+	return xdgFile(env.StateHome, relPath)
+}
+
+// CacheFile is like [CacheFile], but relative to env.CacheHome.
+func (env *Environment) CacheFile(relPath string) (*os.File, error) {
+	return xdgFile(env.CacheHome, relPath)
+}
+
+// RuntimeFile is like [RuntimeFile], but relative to env.RuntimeDir.
+func (env *Environment) RuntimeFile(relPath string) (*os.File, error) {
+	return xdgFile(env.RuntimeDir, relPath)
+}
@@ -0,0 +1,79 @@
+//go:build linux
+
+package xdg_test
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"testing/fstest"
+
+	"github.com/andrieee44/mylib/linux/xdg"
+)
+
+func ExampleConfigFile() {
+	var (
+		dir, rel string
+		file     *os.File
+		err      error
+	)
+
+	// Point $XDG_CONFIG_HOME at a scratch directory for the example.
+	dir, err = os.MkdirTemp("", "xdg")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	err = os.Setenv("XDG_CONFIG_HOME", dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err = xdg.ConfigFile("myapp/app.conf")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer file.Close()
+
+	rel, err = filepath.Rel(dir, file.Name())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(rel)
+	// Output:
+	// myapp/app.conf
+}
+
+func ExampleEnvironment_FindConfig() {
+	var (
+		env  *xdg.Environment
+		path string
+		err  error
+	)
+
+	env = xdg.NewEnvironment(func(key string) string {
+		return map[string]string{
+			"HOME":            "/home/user",
+			"XDG_CONFIG_DIRS": "/etc/xdg",
+		}[key]
+	})
+
+	// The user has no copy of their own, so the system-wide one is used.
+	env.FS = fstest.MapFS{
+		"etc/xdg/myapp/app.conf": {Data: []byte("[keyboard]\n")},
+	}
+
+	path, err = env.FindConfig("myapp/app.conf")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(path)
+	// Output:
+	// /etc/xdg/myapp/app.conf
+}
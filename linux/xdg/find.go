@@ -0,0 +1,96 @@
+//go:build linux
+
+package xdg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FindConfig returns the path of the first file named relPath in
+// ConfigHome and then in each of ConfigDirs, using
+// NewEnvironment(nil). It returns an error wrapping [fs.ErrNotExist] if
+// none exists.
+func FindConfig(relPath string) (string, error) {
+	return NewEnvironment(nil).FindConfig(relPath)
+}
+
+// FindData returns the path of the first file named relPath in DataHome
+// and then in each of DataDirs, using NewEnvironment(nil). It returns an
+// error wrapping [fs.ErrNotExist] if none exists.
+func FindData(relPath string) (string, error) {
+	return NewEnvironment(nil).FindData(relPath)
+}
+
+// FindConfig is like [FindConfig], but searches the directories of env
+// through env.FS.
+func (env *Environment) FindConfig(relPath string) (string, error) {
+	var (
+		path string
+		err  error
+	)
+
+	path, err = env.find(env.ConfigHome, env.ConfigDirs, relPath)
+	if err != nil {
+		return "", fmt.Errorf("Environment.FindConfig: %w", err)
+	}
+
+	return path, nil
+}
+
+// FindData is like [FindData], but searches the directories of env
+// through env.FS.
+func (env *Environment) FindData(relPath string) (string, error) {
+	var (
+		path string
+		err  error
+	)
+
+	path, err = env.find(env.DataHome, env.DataDirs, relPath)
+	if err != nil {
+		return "", fmt.Errorf("Environment.FindData: %w", err)
+	}
+
+	return path, nil
+}
+
+// find returns the first existing relPath in home and then in the
+// colon-separated dirs.
+func (env *Environment) find(home, dirs, relPath string) (string, error) {
+	var (
+		fsys fs.FS
+		dir  string
+		path string
+		err  error
+	)
+
+	fsys = env.FS
+	if fsys == nil {
+		fsys = os.DirFS("/")
+	}
+
+	for _, dir = range append([]string{home}, filepath.SplitList(dirs)...) {
+		if !filepath.IsAbs(dir) {
+			continue
+		}
+
+		path = filepath.Join(dir, relPath)
+
+		_, err = fs.Stat(fsys, strings.TrimPrefix(path, "/"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return "", err
+		}
+
+		return path, nil
+	}
+
+	return "", fmt.Errorf("%s: %w", relPath, fs.ErrNotExist)
+}
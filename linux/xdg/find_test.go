@@ -0,0 +1,123 @@
+//go:build linux
+
+package xdg_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andrieee44/mylib/linux/xdg"
+)
+
+type findTest struct {
+	name       string
+	configHome string
+	configDirs string
+	want       string
+}
+
+func TestEnvironmentFindConfig(t *testing.T) {
+	var (
+		fsys  fstest.MapFS
+		tests []findTest
+		test  findTest
+		env   *xdg.Environment
+		path  string
+		err   error
+	)
+
+	fsys = fstest.MapFS{
+		"home/user/.config/app/app.conf": {Data: []byte("home")},
+		"etc/xdg/app/app.conf":           {Data: []byte("system")},
+		"opt/xdg/app/app.conf":           {Data: []byte("opt")},
+		"opt/xdg/app/only.conf":          {Data: []byte("opt")},
+	}
+
+	tests = []findTest{
+		{
+			name:       "home first",
+			configHome: "/home/user/.config",
+			configDirs: "/etc/xdg:/opt/xdg",
+			want:       "/home/user/.config/app/app.conf",
+		},
+		{
+			name:       "dirs in order",
+			configHome: "/home/other/.config",
+			configDirs: "/opt/xdg:/etc/xdg",
+			want:       "/opt/xdg/app/app.conf",
+		},
+		{
+			name:       "relative entries ignored",
+			configHome: "home/user/.config",
+			configDirs: "etc/xdg::/etc/xdg",
+			want:       "/etc/xdg/app/app.conf",
+		},
+		{
+			name:       "missing",
+			configHome: "/home/other/.config",
+			configDirs: "/usr/xdg",
+		},
+		{
+			name: "empty",
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			env = &xdg.Environment{
+				ConfigHome: test.configHome,
+				ConfigDirs: test.configDirs,
+				FS:         fsys,
+			}
+
+			path, err = env.FindConfig("app/app.conf")
+			if test.want == "" {
+				if !errors.Is(err, fs.ErrNotExist) {
+					t.Errorf("FindConfig = %q, %v, want fs.ErrNotExist", path, err)
+				}
+
+				return
+			}
+
+			if err != nil || path != test.want {
+				t.Errorf("FindConfig = %q, %v, want %q", path, err, test.want)
+			}
+		})
+	}
+}
+
+func TestEnvironmentFindData(t *testing.T) {
+	var (
+		env  *xdg.Environment
+		path string
+		err  error
+	)
+
+	env = &xdg.Environment{
+		DataHome: "/home/user/.local/share",
+		DataDirs: "/usr/local/share/:/usr/share/",
+		FS: fstest.MapFS{
+			"usr/share/app/icon.png":       {Data: []byte("icon")},
+			"home/user/.local/share/app/x": {Data: []byte("x")},
+		},
+	}
+
+	path, err = env.FindData("app/icon.png")
+	if err != nil || path != "/usr/share/app/icon.png" {
+		t.Errorf("FindData = %q, %v, want /usr/share/app/icon.png", path, err)
+	}
+
+	// A directory counts as found, like a file.
+	path, err = env.FindData("app")
+	if err != nil || path != "/home/user/.local/share/app" {
+		t.Errorf("FindData = %q, %v, want /home/user/.local/share/app", path, err)
+	}
+
+	// Paths escaping the FS are invalid rather than missing.
+	_, err = env.FindData("../../../../etc/passwd")
+	if err == nil {
+		t.Error("FindData succeeded outside the FS")
+	}
+}
@@ -0,0 +1,234 @@
+//go:build linux
+
+package xdg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Source tells where the value of a base directory came from.
+type Source int
+
+const (
+	// SourceEnvironment means the value was taken from its environment
+	// variable.
+	SourceEnvironment Source = iota
+
+	// SourceDefault means the variable was unset, empty, or invalid, and
+	// the default of the specification was used.
+	SourceDefault
+
+	// SourceFallback means the default could not be applied as specified,
+	// for example because $HOME or $XDG_RUNTIME_DIR is unset, and a
+	// replacement was used.
+	SourceFallback
+)
+
+// String returns the lowercase name of the source.
+func (source Source) String() string {
+	switch source {
+	case SourceEnvironment:
+		return "environment"
+	case SourceDefault:
+		return "default"
+	case SourceFallback:
+		return "fallback"
+	default:
+		return fmt.Sprintf("Source(%d)", int(source))
+	}
+}
+
+// Resolution describes how a single base directory was resolved.
+type Resolution struct {
+	// Variable is the environment variable, such as "XDG_DATA_HOME".
+	Variable string
+
+	// Value is the resolved directory, or colon-separated directories.
+	Value string
+
+	// Source tells where Value came from.
+	Source Source
+
+	// Warnings describes misconfigurations worth reporting to the user.
+	Warnings []string
+}
+
+type baseDir struct {
+	variable string
+	homeRel  string
+	fallback string
+	list     bool
+}
+
+var (
+	dataHome   baseDir = baseDir{variable: "XDG_DATA_HOME", homeRel: ".local/share"}
+	configHome baseDir = baseDir{variable: "XDG_CONFIG_HOME", homeRel: ".config"}
+	stateHome  baseDir = baseDir{variable: "XDG_STATE_HOME", homeRel: ".local/state"}
+	cacheHome  baseDir = baseDir{variable: "XDG_CACHE_HOME", homeRel: ".cache"}
+	runtimeDir baseDir = baseDir{variable: "XDG_RUNTIME_DIR", fallback: "/tmp"}
+	dataDirs   baseDir = baseDir{
+		variable: "XDG_DATA_DIRS",
+		fallback: "/usr/local/share/:/usr/share/",
+		list:     true,
+	}
+	configDirs baseDir = baseDir{
+		variable: "XDG_CONFIG_DIRS",
+		fallback: "/etc/xdg",
+		list:     true,
+	}
+)
+
+// Resolve reports, for every base directory, its value, where the value
+// came from, and any validation warnings, such as relative paths that
+// were ignored or a runtime directory with unsafe permissions. It uses
+// getenv to look up environment variables; if getenv is nil,
+// [os.Getenv] is used.
+func Resolve(getenv func(string) string) []Resolution {
+	var (
+		resolutions []Resolution
+		res         Resolution
+		dir         baseDir
+	)
+
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	for _, dir = range []baseDir{
+		dataHome,
+		configHome,
+		stateHome,
+		cacheHome,
+		runtimeDir,
+		dataDirs,
+		configDirs,
+	} {
+		res = resolve(getenv, dir)
+		if dir == runtimeDir {
+			res.Warnings = append(res.Warnings, runtimeDirWarnings(res)...)
+		}
+
+		resolutions = append(resolutions, res)
+	}
+
+	return resolutions
+}
+
+func resolve(getenv func(string) string, dir baseDir) Resolution {
+	var (
+		res  Resolution
+		home string
+	)
+
+	res = Resolution{Variable: dir.variable, Value: getenv(dir.variable)}
+
+	if res.Value != "" && filepath.IsAbs(res.Value) {
+		res.Source = SourceEnvironment
+
+		if dir.list {
+			res.Warnings = relativeEntries(res)
+		}
+
+		return res
+	}
+
+	if res.Value != "" {
+		res.Warnings = append(res.Warnings, fmt.Sprintf(
+			"%s=%q is not an absolute path and was ignored",
+			dir.variable,
+			res.Value,
+		))
+	}
+
+	if dir.homeRel == "" {
+		res.Value = dir.fallback
+		res.Source = SourceDefault
+
+		if dir == runtimeDir {
+			res.Source = SourceFallback
+			res.Warnings = append(res.Warnings, fmt.Sprintf(
+				"%s is not set; falling back to %s, which may be shared "+
+					"with other users",
+				dir.variable,
+				dir.fallback,
+			))
+		}
+
+		return res
+	}
+
+	home = getenv("HOME")
+	res.Source = SourceDefault
+
+	if home == "" {
+		home = "/"
+		res.Source = SourceFallback
+		res.Warnings = append(
+			res.Warnings,
+			"HOME is not set; using / as the home directory",
+		)
+	}
+
+	res.Value = filepath.Join(home, dir.homeRel)
+
+	return res
+}
+
+func relativeEntries(res Resolution) []string {
+	var (
+		warnings []string
+		entry    string
+	)
+
+	for entry = range strings.SplitSeq(res.Value, ":") {
+		if entry != "" && !filepath.IsAbs(entry) {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s entry %q is not an absolute path and should be ignored",
+				res.Variable,
+				entry,
+			))
+		}
+	}
+
+	return warnings
+}
+
+func runtimeDirWarnings(res Resolution) []string {
+	var (
+		stat unix.Stat_t
+		err  error
+	)
+
+	if res.Source != SourceEnvironment {
+		return nil
+	}
+
+	err = unix.Stat(res.Value, &stat)
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", res.Variable, err)}
+	}
+
+	if stat.Uid != uint32(os.Geteuid()) {
+		return []string{fmt.Sprintf(
+			"%s=%s is not owned by the current user",
+			res.Variable,
+			res.Value,
+		)}
+	}
+
+	if stat.Mode&0o777 != 0o700 {
+		return []string{fmt.Sprintf(
+			"%s=%s has mode %#o instead of 0700",
+			res.Variable,
+			res.Value,
+			stat.Mode&0o777,
+		)}
+	}
+
+	return nil
+}
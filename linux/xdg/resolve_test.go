@@ -0,0 +1,112 @@
+//go:build linux
+
+package xdg_test
+
+import (
+	"testing"
+
+	"github.com/andrieee44/mylib/linux/xdg"
+)
+
+type resolveTest struct {
+	name     string
+	env      map[string]string
+	variable string
+	want     string
+	source   xdg.Source
+	warnings int
+}
+
+func TestResolve(t *testing.T) {
+	var (
+		tests []resolveTest
+		test  resolveTest
+		res   xdg.Resolution
+		found bool
+	)
+
+	tests = []resolveTest{
+		{
+			name:     "environment",
+			env:      map[string]string{"HOME": "/home/user", "XDG_CONFIG_HOME": "/cfg"},
+			variable: "XDG_CONFIG_HOME",
+			want:     "/cfg",
+			source:   xdg.SourceEnvironment,
+		},
+		{
+			name:     "default",
+			env:      map[string]string{"HOME": "/home/user"},
+			variable: "XDG_DATA_HOME",
+			want:     "/home/user/.local/share",
+			source:   xdg.SourceDefault,
+		},
+		{
+			name:     "relative ignored",
+			env:      map[string]string{"HOME": "/home/user", "XDG_CACHE_HOME": "cache"},
+			variable: "XDG_CACHE_HOME",
+			want:     "/home/user/.cache",
+			source:   xdg.SourceDefault,
+			warnings: 1,
+		},
+		{
+			name:     "no home",
+			env:      map[string]string{},
+			variable: "XDG_STATE_HOME",
+			want:     "/.local/state",
+			source:   xdg.SourceFallback,
+			warnings: 1,
+		},
+		{
+			name:     "no runtime dir",
+			env:      map[string]string{"HOME": "/home/user"},
+			variable: "XDG_RUNTIME_DIR",
+			want:     "/tmp",
+			source:   xdg.SourceFallback,
+			warnings: 1,
+		},
+		{
+			name:     "relative list entries",
+			env:      map[string]string{"XDG_CONFIG_DIRS": "/etc/xdg:xdg:/opt/xdg"},
+			variable: "XDG_CONFIG_DIRS",
+			want:     "/etc/xdg:xdg:/opt/xdg",
+			source:   xdg.SourceEnvironment,
+			warnings: 1,
+		},
+		{
+			name:     "default list",
+			env:      map[string]string{},
+			variable: "XDG_DATA_DIRS",
+			want:     "/usr/local/share/:/usr/share/",
+			source:   xdg.SourceDefault,
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			found = false
+
+			for _, res = range xdg.Resolve(func(key string) string {
+				return test.env[key]
+			}) {
+				if res.Variable != test.variable {
+					continue
+				}
+
+				found = true
+
+				if res.Value != test.want || res.Source != test.source {
+					t.Errorf("%s = %q (%s), want %q (%s)",
+						res.Variable, res.Value, res.Source, test.want, test.source)
+				}
+
+				if len(res.Warnings) != test.warnings {
+					t.Errorf("%s warnings = %q, want %d", res.Variable, res.Warnings, test.warnings)
+				}
+			}
+
+			if !found {
+				t.Errorf("Resolve did not report %s", test.variable)
+			}
+		})
+	}
+}
@@ -0,0 +1,269 @@
+//go:build linux
+
+package xdg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// ErrAddressInUse is returned by [RuntimeSocket] when another process is
+// already listening on the socket.
+var ErrAddressInUse error = errors.New("socket is in use by another process")
+
+// RuntimeSocket listens on a unix socket at a relative path (e.g.,
+// "appname/app.sock") in the base runtime directory. Missing directories
+// are auto-created with 0700 permissions and the socket itself is
+// restricted to the user. A stale socket left behind by a crashed
+// process is removed first; a socket with a live listener results in
+// [ErrAddressInUse]. The socket file is removed when the listener is
+// closed.
+//
+// See [RuntimeFile] for the requirements the [XDG Base Directory
+// Specification] places on the runtime directory.
+//
+// [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
+func RuntimeSocket(relPath string) (net.Listener, error) {
+	var (
+		listener net.Listener
+		path     string
+		err      error
+	)
+
+	path, err = runtimePath(relPath)
+	if err != nil {
+		return nil, fmt.Errorf("xdg.RuntimeSocket: %w", err)
+	}
+
+	err = removeStaleSocket(path)
+	if err != nil {
+		return nil, fmt.Errorf("xdg.RuntimeSocket: %w", err)
+	}
+
+	listener, err = listenUserOnly(path)
+	if err != nil {
+		return nil, fmt.Errorf("xdg.RuntimeSocket: %w", err)
+	}
+
+	return listener, nil
+}
+
+// listenUserOnly listens on a unix socket at path whose file is
+// restricted to the user from the moment bind creates it. The kernel
+// takes the mode of the socket file from the socket itself, so the mode
+// is set with fchmod before binding rather than with chmod after
+// listening, which would leave the socket open to other users in
+// between.
+func listenUserOnly(path string) (net.Listener, error) {
+	const userOnly uint32 = 0o700
+
+	var (
+		fd       int
+		file     *os.File
+		listener net.Listener
+		err      error
+	)
+
+	fd, err = unix.Socket(unix.AF_UNIX, unix.SOCK_STREAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, os.NewSyscallError("socket", err)
+	}
+
+	file = os.NewFile(uintptr(fd), path)
+	defer file.Close()
+
+	err = unix.Fchmod(fd, userOnly)
+	if err != nil {
+		return nil, os.NewSyscallError("fchmod", err)
+	}
+
+	err = unix.Bind(fd, &unix.SockaddrUnix{Name: path})
+	if err != nil {
+		return nil, os.NewSyscallError("bind", err)
+	}
+
+	err = unix.Listen(fd, unix.SOMAXCONN)
+	if err != nil {
+		_ = os.Remove(path)
+
+		return nil, os.NewSyscallError("listen", err)
+	}
+
+	listener, err = net.FileListener(file)
+	if err != nil {
+		_ = os.Remove(path)
+
+		return nil, err
+	}
+
+	listener.(*net.UnixListener).SetUnlinkOnClose(true)
+
+	return listener, nil
+}
+
+// RuntimeFIFO creates a named pipe at a relative path (e.g.,
+// "appname/app.fifo") in the base runtime directory, unless one already
+// exists, and returns its absolute path. Missing directories are
+// auto-created with 0700 permissions and the pipe is restricted to the
+// user. The pipe is not opened, since opening one end blocks until the
+// other end is opened too.
+func RuntimeFIFO(relPath string) (string, error) {
+	var (
+		path string
+		info os.FileInfo
+		err  error
+	)
+
+	path, err = runtimePath(relPath)
+	if err != nil {
+		return "", fmt.Errorf("xdg.RuntimeFIFO: %w", err)
+	}
+
+	info, err = os.Lstat(path)
+	if err == nil && info.Mode()&fs.ModeNamedPipe != 0 {
+		return path, nil
+	}
+
+	err = unix.Mkfifo(path, 0o600)
+	if err != nil {
+		return "", fmt.Errorf("xdg.RuntimeFIFO: %w", err)
+	}
+
+	return path, nil
+}
+
+// KeepAliveInterval is how often [KeepAlive] refreshes the access time,
+// well within the six hours allowed by the specification.
+const KeepAliveInterval time.Duration = time.Hour
+
+// KeepAlive protects a file, socket, or named pipe in the runtime
+// directory from periodic clean-up by refreshing its access time
+// immediately and then every [KeepAliveInterval], leaving the
+// modification time untouched. It blocks until ctx is done and returns
+// nil, or returns the first error encountered.
+//
+// From the [XDG Base Directory Specification]:
+//
+// Files in this directory MAY be subjected to periodic clean-up. To
+// ensure that your files are not removed, they should have their access
+// time timestamp modified at least once every 6 hours of monotonic time
+// or the 'sticky' bit should be set on the file.
+//
+// [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
+func KeepAlive(ctx context.Context, path string) error {
+	var (
+		ticker *time.Ticker
+		err    error
+	)
+
+	ticker = time.NewTicker(KeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		err = touchAccessTime(path)
+		if err != nil {
+			return fmt.Errorf("xdg.KeepAlive: %w", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetSticky sets the sticky bit on a file in the runtime directory,
+// which exempts it from periodic clean-up without the need for
+// [KeepAlive].
+func SetSticky(path string) error {
+	var (
+		info os.FileInfo
+		err  error
+	)
+
+	info, err = os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("xdg.SetSticky: %w", err)
+	}
+
+	err = os.Chmod(path, info.Mode()|os.ModeSticky)
+	if err != nil {
+		return fmt.Errorf("xdg.SetSticky: %w", err)
+	}
+
+	return nil
+}
+
+func touchAccessTime(path string) error {
+	return unix.UtimesNanoAt(
+		unix.AT_FDCWD,
+		path,
+		[]unix.Timespec{
+			{Nsec: unix.UTIME_NOW},
+			{Nsec: unix.UTIME_OMIT},
+		},
+		0,
+	)
+}
+
+func runtimePath(relPath string) (string, error) {
+	const userOnly os.FileMode = 0o700
+
+	var (
+		path string
+		err  error
+	)
+
+	path = filepath.Join(RuntimeDir(), relPath)
+
+	err = os.MkdirAll(filepath.Dir(path), userOnly)
+	if err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func removeStaleSocket(path string) error {
+	var (
+		info os.FileInfo
+		conn net.Conn
+		err  error
+	)
+
+	info, err = os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%s: %w", path, syscall.EADDRINUSE)
+	}
+
+	conn, err = net.Dial("unix", path)
+	if err == nil {
+		_ = conn.Close()
+
+		return fmt.Errorf("%s: %w", path, ErrAddressInUse)
+	}
+
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return err
+	}
+
+	return os.Remove(path)
+}
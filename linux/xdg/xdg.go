@@ -8,26 +8,6 @@ import (
 	"path/filepath"
 )
 
-func home() string {
-	var home string
-
-	home = os.Getenv("HOME")
-	if home == "" {
-		return "/"
-	}
-
-	return home
-}
-
-func xdg(env string, subPaths ...string) string {
-	env = os.Getenv(env)
-	if env == "" || !filepath.IsAbs(env) {
-		env = filepath.Join(subPaths...)
-	}
-
-	return env
-}
-
 func xdgFile(xdgPath, relPath string) (*os.File, error) {
 	const userOnly os.FileMode = 0o700
 
@@ -52,6 +32,36 @@ func xdgFile(xdgPath, relPath string) (*os.File, error) {
 	return file, nil
 }
 
+// DataHome returns the base directory for user-specific data files,
+// $XDG_DATA_HOME or its default $HOME/.local/share.
+func DataHome() string {
+	return NewEnvironment(nil).DataHome
+}
+
+// ConfigHome returns the base directory for user-specific configuration
+// files, $XDG_CONFIG_HOME or its default $HOME/.config.
+func ConfigHome() string {
+	return NewEnvironment(nil).ConfigHome
+}
+
+// StateHome returns the base directory for user-specific state files,
+// $XDG_STATE_HOME or its default $HOME/.local/state.
+func StateHome() string {
+	return NewEnvironment(nil).StateHome
+}
+
+// CacheHome returns the base directory for user-specific non-essential
+// data files, $XDG_CACHE_HOME or its default $HOME/.cache.
+func CacheHome() string {
+	return NewEnvironment(nil).CacheHome
+}
+
+// RuntimeDir returns the base directory for user-specific runtime files,
+// $XDG_RUNTIME_DIR or the fallback /tmp.
+func RuntimeDir() string {
+	return NewEnvironment(nil).RuntimeDir
+}
+
 // DataFile opens the file with read/write access using a relative path
 // (e.g., "appname/app.data") that includes the filename and optional
 // directories. Missing directories are auto-created relative to the
@@ -66,7 +76,7 @@ func xdgFile(xdgPath, relPath string) (*os.File, error) {
 //
 // [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
 func DataFile(relPath string) (*os.File, error) {
-	return xdgFile(xdg("XDG_DATA_HOME", home(), ".local/share"), relPath)
+	return xdgFile(DataHome(), relPath)
 }
 
 // ConfigFile opens the file with read/write access using a relative path
@@ -84,7 +94,7 @@ func DataFile(relPath string) (*os.File, error) {
 //
 // [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
 func ConfigFile(relPath string) (*os.File, error) {
-	return xdgFile(xdg("XDG_CONFIG_HOME", home(), ".config"), relPath)
+	return xdgFile(ConfigHome(), relPath)
 }
 
 // StateFile opens the file with read/write access using a relative path
@@ -112,7 +122,7 @@ func ConfigFile(relPath string) (*os.File, error) {
 //
 // [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
 func StateFile(relPath string) (*os.File, error) {
-	return xdgFile(xdg("XDG_STATE_HOME", home(), ".local/state"), relPath)
+	return xdgFile(StateHome(), relPath)
 }
 
 // DataDirs retrieves the value of $XDG_DATA_DIRS if it is defined,
@@ -130,7 +140,7 @@ func StateFile(relPath string) (*os.File, error) {
 //
 // [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
 func DataDirs() string {
-	return xdg("XDG_DATA_DIRS", "/usr/local/share/:/usr/share/")
+	return NewEnvironment(nil).DataDirs
 }
 
 // ConfigDirs retrieves the value of $XDG_CONFIG_DIRS if it is defined,
@@ -158,7 +168,7 @@ func DataDirs() string {
 //
 // [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
 func ConfigDirs() string {
-	return xdg("XDG_CONFIG_DIRS", "/etc/xdg")
+	return NewEnvironment(nil).ConfigDirs
 }
 
 // CacheFile opens the file with read/write access using a relative path
@@ -176,7 +186,7 @@ func ConfigDirs() string {
 //
 // [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
 func CacheFile(relPath string) (*os.File, error) {
-	return xdgFile(xdg("XDG_CACHE_HOME", home(), "$HOME/.cache"), relPath)
+	return xdgFile(CacheHome(), relPath)
 }
 
 // RuntimeFile opens the file with read/write access using a relative
@@ -222,5 +232,5 @@ func CacheFile(relPath string) (*os.File, error) {
 //
 // [XDG Base Directory Specification]: https://specifications.freedesktop.org/basedir-spec/latest
 func RuntimeFile(relPath string) (*os.File, error) {
-	return xdgFile(xdg("XDG_RUNTIME_DIR", "/tmp"), relPath)
+	return xdgFile(RuntimeDir(), relPath)
 }
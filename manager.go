@@ -0,0 +1,188 @@
+package mylib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// DeviceChange reports an input device being attached or detached.
+type DeviceChange struct {
+	// Key identifies the device to its backend, such as the path of its
+	// device node.
+	Key string
+
+	// Device is the device. When it is detached, it is the device that
+	// was reported when it was attached, still open.
+	Device InputDevice
+
+	// Added is true if the device was attached and false if it was
+	// detached.
+	Added bool
+}
+
+// DeviceMonitor is implemented by backends that can watch for input
+// devices being attached and detached. A monitor may also have an
+// Err() error method reporting why its channel was closed if watching
+// failed, which [DeviceManager.Run] then returns.
+type DeviceMonitor interface {
+	// Watch reports every device attached when it is called as added,
+	// opening it, and then reports devices as they are attached and
+	// detached. The channel is closed when ctx is done or watching
+	// fails. The receiver owns the reported devices and closes them.
+	Watch(ctx context.Context) (<-chan DeviceChange, error)
+}
+
+// DeviceManager keeps track of the attached input devices through a
+// [DeviceMonitor] and calls the registered callbacks as they come and
+// go, giving applications one place to handle the device lifecycle. It
+// is safe for concurrent use.
+type DeviceManager struct {
+	mu       sync.Mutex
+	monitor  DeviceMonitor
+	devices  map[string]InputDevice
+	onAdd    []func(key string, dev InputDevice)
+	onRemove []func(key string, dev InputDevice)
+}
+
+// NewDeviceManager returns a DeviceManager watching devices with
+// monitor, such as the Monitor of the linux/input package.
+func NewDeviceManager(monitor DeviceMonitor) *DeviceManager {
+	return &DeviceManager{
+		monitor: monitor,
+		devices: make(map[string]InputDevice),
+	}
+}
+
+// OnAdd registers fn to be called with every device that is attached,
+// including those already attached when [DeviceManager.Run] starts.
+func (mgr *DeviceManager) OnAdd(fn func(key string, dev InputDevice)) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	mgr.onAdd = append(mgr.onAdd, fn)
+}
+
+// OnRemove registers fn to be called with every device that is
+// detached, before the manager closes it.
+func (mgr *DeviceManager) OnRemove(fn func(key string, dev InputDevice)) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	mgr.onRemove = append(mgr.onRemove, fn)
+}
+
+// Devices returns the attached devices, ordered by key.
+func (mgr *DeviceManager) Devices() []InputDevice {
+	var (
+		devices []InputDevice
+		key     string
+	)
+
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	for _, key = range slices.Sorted(maps.Keys(mgr.devices)) {
+		devices = append(devices, mgr.devices[key])
+	}
+
+	return devices
+}
+
+// Run watches for devices until ctx is done, in which case it returns
+// ctx.Err(), or until the monitor stops, in which case it returns the
+// error the monitor reports, if any. The callbacks are called from
+// Run, one at a time. Before returning, Run calls the OnRemove
+// callbacks for the devices still attached and closes them.
+func (mgr *DeviceManager) Run(ctx context.Context) error {
+	var (
+		changes   <-chan DeviceChange
+		change    DeviceChange
+		remaining map[string]InputDevice
+		key       string
+		failer    interface{ Err() error }
+		ok        bool
+		errs      []error
+		err       error
+	)
+
+	changes, err = mgr.monitor.Watch(ctx)
+	if err != nil {
+		return fmt.Errorf("DeviceManager.Run: %w", err)
+	}
+
+	for change = range changes {
+		if change.Added {
+			mgr.add(change.Key, change.Device)
+
+			continue
+		}
+
+		err = mgr.remove(change.Key, change.Device)
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	failer, ok = mgr.monitor.(interface{ Err() error })
+	if ok {
+		errs = append(errs, failer.Err())
+	}
+
+	mgr.mu.Lock()
+	remaining = maps.Clone(mgr.devices)
+	mgr.mu.Unlock()
+
+	for _, key = range slices.Sorted(maps.Keys(remaining)) {
+		err = mgr.remove(key, remaining[key])
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	err = errors.Join(append(errs, ctx.Err())...)
+	if err != nil {
+		return fmt.Errorf("DeviceManager.Run: %w", err)
+	}
+
+	return nil
+}
+
+func (mgr *DeviceManager) add(key string, dev InputDevice) {
+	var (
+		callbacks []func(string, InputDevice)
+		fn        func(string, InputDevice)
+	)
+
+	mgr.mu.Lock()
+	mgr.devices[key] = dev
+	callbacks = slices.Clone(mgr.onAdd)
+	mgr.mu.Unlock()
+
+	for _, fn = range callbacks {
+		fn(key, dev)
+	}
+}
+
+// remove forgets the device, calls the OnRemove callbacks, and closes
+// it.
+func (mgr *DeviceManager) remove(key string, dev InputDevice) error {
+	var (
+		callbacks []func(string, InputDevice)
+		fn        func(string, InputDevice)
+	)
+
+	mgr.mu.Lock()
+	delete(mgr.devices, key)
+	callbacks = slices.Clone(mgr.onRemove)
+	mgr.mu.Unlock()
+
+	for _, fn = range callbacks {
+		fn(key, dev)
+	}
+
+	return dev.Close()
+}
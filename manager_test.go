@@ -0,0 +1,212 @@
+package mylib_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/inputtest"
+)
+
+// fakeMonitor reports its changes, then closes the channel and reports
+// err from Err.
+type fakeMonitor struct {
+	changes  []mylib.DeviceChange
+	watchErr error
+	err      error
+}
+
+type managerTest struct {
+	name     string
+	changes  []managerChange
+	cancel   bool
+	watchErr error
+	err      error
+	calls    []string
+	want     error
+}
+
+// managerChange attaches or detaches the fake device with key.
+type managerChange struct {
+	key   string
+	added bool
+}
+
+var errMonitor error = errors.New("monitor failed")
+
+func (monitor *fakeMonitor) Watch(context.Context) (<-chan mylib.DeviceChange, error) {
+	var (
+		changes chan mylib.DeviceChange
+		change  mylib.DeviceChange
+	)
+
+	if monitor.watchErr != nil {
+		return nil, monitor.watchErr
+	}
+
+	changes = make(chan mylib.DeviceChange, len(monitor.changes))
+	for _, change = range monitor.changes {
+		changes <- change
+	}
+
+	close(changes)
+
+	return changes, nil
+}
+
+func (monitor *fakeMonitor) Err() error {
+	return monitor.err
+}
+
+func TestDeviceManager(t *testing.T) {
+	var (
+		tests   []managerTest
+		test    managerTest
+		devices map[string]*inputtest.FakeDevice
+		monitor *fakeMonitor
+		mgr     *mylib.DeviceManager
+		ctx     context.Context
+		cancel  context.CancelFunc
+		change  managerChange
+		calls   []string
+		key     string
+		err     error
+	)
+
+	tests = []managerTest{
+		{
+			name: "attached and detached",
+			changes: []managerChange{
+				{key: "b", added: true},
+				{key: "a", added: true},
+				{key: "b"},
+			},
+			calls: []string{"add b", "add a", "remove b", "remove a"},
+		},
+		{
+			name:    "still attached when the monitor fails",
+			changes: []managerChange{{key: "a", added: true}},
+			err:     errMonitor,
+			calls:   []string{"add a", "remove a"},
+			want:    errMonitor,
+		},
+		{
+			name:    "context done",
+			changes: []managerChange{{key: "a", added: true}},
+			cancel:  true,
+			calls:   []string{"add a", "remove a"},
+			want:    context.Canceled,
+		},
+		{
+			name:     "Watch fails",
+			watchErr: errMonitor,
+			want:     errMonitor,
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			devices = make(map[string]*inputtest.FakeDevice)
+			monitor = &fakeMonitor{watchErr: test.watchErr, err: test.err}
+
+			for _, change = range test.changes {
+				if devices[change.key] == nil {
+					devices[change.key] = inputtest.NewFakeDevice(change.key, change.key)
+				}
+
+				monitor.changes = append(monitor.changes, mylib.DeviceChange{
+					Key:    change.key,
+					Device: devices[change.key],
+					Added:  change.added,
+				})
+			}
+
+			calls = nil
+			mgr = mylib.NewDeviceManager(monitor)
+			mgr.OnAdd(func(key string, dev mylib.InputDevice) {
+				calls = append(calls, "add "+key)
+			})
+			mgr.OnRemove(func(key string, dev mylib.InputDevice) {
+				if devices[key].Closes() != 0 {
+					t.Errorf("%s closed before OnRemove", key)
+				}
+
+				calls = append(calls, "remove "+key)
+			})
+
+			ctx, cancel = context.WithCancel(context.Background())
+			defer cancel()
+
+			if test.cancel {
+				cancel()
+			}
+
+			err = mgr.Run(ctx)
+			if !errors.Is(err, test.want) {
+				t.Errorf("Run = %v, want %v", err, test.want)
+			}
+
+			if !slices.Equal(calls, test.calls) {
+				t.Errorf("calls = %q, want %q", calls, test.calls)
+			}
+
+			for key = range devices {
+				if devices[key].Closes() != 1 {
+					t.Errorf("%s closed %d times, want 1", key, devices[key].Closes())
+				}
+			}
+
+			if len(mgr.Devices()) != 0 {
+				t.Errorf("Devices = %v after Run, want none", mgr.Devices())
+			}
+		})
+	}
+}
+
+func TestDeviceManagerDevices(t *testing.T) {
+	var (
+		a, b    *inputtest.FakeDevice
+		monitor *fakeMonitor
+		mgr     *mylib.DeviceManager
+		seen    [][]mylib.InputDevice
+		want    [][]mylib.InputDevice
+		index   int
+		err     error
+	)
+
+	a = inputtest.NewFakeDevice("a", "a")
+	b = inputtest.NewFakeDevice("b", "b")
+	monitor = &fakeMonitor{changes: []mylib.DeviceChange{
+		{Key: "event1", Device: b, Added: true},
+		{Key: "event0", Device: a, Added: true},
+		{Key: "event1", Device: b},
+	}}
+
+	mgr = mylib.NewDeviceManager(monitor)
+	mgr.OnAdd(func(string, mylib.InputDevice) {
+		seen = append(seen, mgr.Devices())
+	})
+	mgr.OnRemove(func(string, mylib.InputDevice) {
+		seen = append(seen, mgr.Devices())
+	})
+
+	err = mgr.Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Devices are ordered by key, and are gone by the time OnRemove is
+	// called for them.
+	want = [][]mylib.InputDevice{{b}, {a, b}, {a}, nil}
+	if len(seen) != len(want) {
+		t.Fatalf("Devices seen %d times, want %d", len(seen), len(want))
+	}
+
+	for index = range want {
+		if !slices.Equal(seen[index], want[index]) {
+			t.Errorf("Devices = %v, want %v", seen[index], want[index])
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package mylib
+
+import (
+	"strconv"
+	"sync"
+)
+
+// Namer names the event types and codes of a backend, which registers
+// it with [RegisterNamer] so that [EventName] and [CodeName] work
+// without naming the backend.
+type Namer interface {
+	// EventName returns the name of eventType, such as "EV_KEY", or an
+	// empty string if it is unknown.
+	EventName(eventType InputEvent) string
+
+	// CodeName returns the name of code within eventType, such as
+	// "KEY_A", or an empty string if either is unknown.
+	CodeName(eventType InputEvent, code InputCode) string
+}
+
+var (
+	namersMutex sync.RWMutex
+	namers      []Namer
+)
+
+// RegisterNamer adds namer to the namers consulted by [EventName] and
+// [CodeName], after those registered before it. Backends call it from
+// an init function, so importing a backend is enough to name its
+// events.
+func RegisterNamer(namer Namer) {
+	namersMutex.Lock()
+	defer namersMutex.Unlock()
+
+	namers = append(namers, namer)
+}
+
+// EventName returns the name of eventType given by the first registered
+// [Namer] that knows it, or its decimal value if none does.
+func EventName(eventType InputEvent) string {
+	var (
+		namer Namer
+		name  string
+	)
+
+	namersMutex.RLock()
+	defer namersMutex.RUnlock()
+
+	for _, namer = range namers {
+		name = namer.EventName(eventType)
+		if name != "" {
+			return name
+		}
+	}
+
+	return strconv.FormatUint(uint64(eventType), 10)
+}
+
+// CodeName returns the name of code within eventType given by the first
+// registered [Namer] that knows it, or its decimal value if none does.
+func CodeName(eventType InputEvent, code InputCode) string {
+	var (
+		namer Namer
+		name  string
+	)
+
+	namersMutex.RLock()
+	defer namersMutex.RUnlock()
+
+	for _, namer = range namers {
+		name = namer.CodeName(eventType, code)
+		if name != "" {
+			return name
+		}
+	}
+
+	return strconv.FormatUint(uint64(code), 10)
+}
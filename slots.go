@@ -0,0 +1,202 @@
+package mylib
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNoFreeSlot is returned by [SlotManager.Connect] when every player
+// slot is occupied by a connected device.
+var ErrNoFreeSlot error = errors.New("no free player slot")
+
+// ErrUnknownDevice is returned by [SlotManager.Disconnect] when no
+// connected device has the given fingerprint.
+var ErrUnknownDevice error = errors.New("unknown device")
+
+// SlotChange describes a device joining or leaving a player slot.
+type SlotChange struct {
+	// Slot is the 1-based player slot.
+	Slot int
+
+	// Fingerprint identifies the device, see [Fingerprint].
+	Fingerprint string
+
+	// Connected is true if the device took the slot and false if it
+	// left it.
+	Connected bool
+}
+
+// SlotManager assigns devices such as gamepads to stable player slots,
+// the way game consoles do. A device that disconnects keeps its slot
+// reserved, and gets the same slot back when it reconnects unless
+// another device needed it in the meantime. It is safe for concurrent
+// use.
+type SlotManager struct {
+	mu       sync.Mutex
+	slots    []playerSlot
+	onChange func(SlotChange)
+}
+
+type playerSlot struct {
+	fingerprint string
+	connected   bool
+}
+
+// NewSlotManager returns a SlotManager with the given number of player
+// slots, numbered from 1. If onChange is non-nil, it is called after
+// every slot change, outside of the manager's lock.
+func NewSlotManager(players int, onChange func(SlotChange)) *SlotManager {
+	return &SlotManager{
+		slots:    make([]playerSlot, players),
+		onChange: onChange,
+	}
+}
+
+// Fingerprint returns a string identifying dev across reconnects, built
+// from its ID and name. Identical devices share a fingerprint; the
+// [SlotManager] then tells them apart by connection order.
+func Fingerprint(dev InputDevice) (string, error) {
+	var (
+		id, name string
+		err      error
+	)
+
+	id, err = dev.ID()
+	if err != nil {
+		return "", fmt.Errorf("mylib.Fingerprint: %w", err)
+	}
+
+	name, err = dev.Name()
+	if err != nil {
+		return "", fmt.Errorf("mylib.Fingerprint: %w", err)
+	}
+
+	return id + " name " + name, nil
+}
+
+// Connect assigns the device with the given fingerprint to a player slot
+// and returns the 1-based slot. It prefers, in order, a free slot last
+// held by the same fingerprint, a slot never held by another device, and
+// any free slot.
+func (mgr *SlotManager) Connect(fingerprint string) (int, error) {
+	var (
+		index int
+		ok    bool
+	)
+
+	mgr.mu.Lock()
+
+	index, ok = mgr.pick(fingerprint)
+	if !ok {
+		mgr.mu.Unlock()
+
+		return 0, fmt.Errorf("SlotManager.Connect: %w", ErrNoFreeSlot)
+	}
+
+	mgr.slots[index] = playerSlot{fingerprint: fingerprint, connected: true}
+	mgr.mu.Unlock()
+
+	mgr.notify(SlotChange{
+		Slot:        index + 1,
+		Fingerprint: fingerprint,
+		Connected:   true,
+	})
+
+	return index + 1, nil
+}
+
+// Disconnect releases the slot of a connected device with the given
+// fingerprint and returns that 1-based slot. The slot stays reserved for
+// the fingerprint until another device needs it.
+func (mgr *SlotManager) Disconnect(fingerprint string) (int, error) {
+	var index int
+
+	mgr.mu.Lock()
+
+	for index = range mgr.slots {
+		if !mgr.slots[index].connected ||
+			mgr.slots[index].fingerprint != fingerprint {
+			continue
+		}
+
+		mgr.slots[index].connected = false
+		mgr.mu.Unlock()
+
+		mgr.notify(SlotChange{
+			Slot:        index + 1,
+			Fingerprint: fingerprint,
+			Connected:   false,
+		})
+
+		return index + 1, nil
+	}
+
+	mgr.mu.Unlock()
+
+	return 0, fmt.Errorf("SlotManager.Disconnect: %w %q", ErrUnknownDevice, fingerprint)
+}
+
+// Slots returns the fingerprint of the device connected to each slot,
+// indexed from 0 for slot 1. Free slots hold the empty string.
+func (mgr *SlotManager) Slots() []string {
+	var (
+		fingerprints []string
+		slot         playerSlot
+	)
+
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+
+	fingerprints = make([]string, 0, len(mgr.slots))
+	for _, slot = range mgr.slots {
+		if !slot.connected {
+			fingerprints = append(fingerprints, "")
+
+			continue
+		}
+
+		fingerprints = append(fingerprints, slot.fingerprint)
+	}
+
+	return fingerprints
+}
+
+func (mgr *SlotManager) pick(fingerprint string) (int, bool) {
+	var (
+		index, unused, free int
+		slot                playerSlot
+	)
+
+	unused, free = -1, -1
+
+	for index, slot = range mgr.slots {
+		if slot.connected {
+			continue
+		}
+
+		if slot.fingerprint == fingerprint {
+			return index, true
+		}
+
+		if slot.fingerprint == "" && unused == -1 {
+			unused = index
+		}
+
+		if free == -1 {
+			free = index
+		}
+	}
+
+	if unused != -1 {
+		return unused, true
+	}
+
+	return free, free != -1
+}
+
+func (mgr *SlotManager) notify(change SlotChange) {
+	if mgr.onChange != nil {
+		mgr.onChange(change)
+	}
+}
@@ -0,0 +1,163 @@
+package mylib_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/inputtest"
+)
+
+type slotTest struct {
+	name    string
+	players int
+	steps   []slotStep
+	slots   []string
+}
+
+// slotStep connects or disconnects the device with fingerprint, which
+// should take or leave slot, or fail with err.
+type slotStep struct {
+	disconnect  bool
+	fingerprint string
+	slot        int
+	err         error
+}
+
+func TestSlotManager(t *testing.T) {
+	var (
+		tests   []slotTest
+		test    slotTest
+		mgr     *mylib.SlotManager
+		changes []mylib.SlotChange
+		want    []mylib.SlotChange
+		step    slotStep
+		slot    int
+		err     error
+	)
+
+	tests = []slotTest{
+		{
+			name:    "connection order",
+			players: 3,
+			steps:   []slotStep{{fingerprint: "a", slot: 1}, {fingerprint: "b", slot: 2}},
+			slots:   []string{"a", "b", ""},
+		},
+		{
+			name:    "reconnected to the same slot",
+			players: 3,
+			steps: []slotStep{
+				{fingerprint: "a", slot: 1},
+				{fingerprint: "b", slot: 2},
+				{disconnect: true, fingerprint: "a", slot: 1},
+				{fingerprint: "c", slot: 3},
+				{fingerprint: "a", slot: 1},
+			},
+			slots: []string{"a", "b", "c"},
+		},
+		{
+			name:    "reserved slot given away",
+			players: 2,
+			steps: []slotStep{
+				{fingerprint: "a", slot: 1},
+				{fingerprint: "b", slot: 2},
+				{disconnect: true, fingerprint: "a", slot: 1},
+				{fingerprint: "c", slot: 1},
+				{disconnect: true, fingerprint: "b", slot: 2},
+				{fingerprint: "a", slot: 2},
+			},
+			slots: []string{"c", "a"},
+		},
+		{
+			name:    "identical devices",
+			players: 2,
+			steps: []slotStep{
+				{fingerprint: "x", slot: 1},
+				{fingerprint: "x", slot: 2},
+				{disconnect: true, fingerprint: "x", slot: 1},
+				{fingerprint: "x", slot: 1},
+			},
+			slots: []string{"x", "x"},
+		},
+		{
+			name:    "no free slot",
+			players: 1,
+			steps: []slotStep{
+				{fingerprint: "a", slot: 1},
+				{fingerprint: "b", err: mylib.ErrNoFreeSlot},
+			},
+			slots: []string{"a"},
+		},
+		{
+			name:    "unknown device",
+			players: 2,
+			steps: []slotStep{
+				{disconnect: true, fingerprint: "a", err: mylib.ErrUnknownDevice},
+				{fingerprint: "a", slot: 1},
+				{disconnect: true, fingerprint: "a", slot: 1},
+				{disconnect: true, fingerprint: "a", err: mylib.ErrUnknownDevice},
+			},
+			slots: []string{"", ""},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			changes, want = nil, nil
+			mgr = mylib.NewSlotManager(test.players, func(change mylib.SlotChange) {
+				changes = append(changes, change)
+			})
+
+			for _, step = range test.steps {
+				if step.disconnect {
+					slot, err = mgr.Disconnect(step.fingerprint)
+				} else {
+					slot, err = mgr.Connect(step.fingerprint)
+				}
+
+				if !errors.Is(err, step.err) || slot != step.slot {
+					t.Fatalf("%+v: slot %d, error %v", step, slot, err)
+				}
+
+				if err == nil {
+					want = append(want, mylib.SlotChange{
+						Slot:        step.slot,
+						Fingerprint: step.fingerprint,
+						Connected:   !step.disconnect,
+					})
+				}
+			}
+
+			if !slices.Equal(changes, want) {
+				t.Errorf("changes = %+v, want %+v", changes, want)
+			}
+
+			if !slices.Equal(mgr.Slots(), test.slots) {
+				t.Errorf("Slots = %q, want %q", mgr.Slots(), test.slots)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	var (
+		dev         *inputtest.FakeDevice
+		fingerprint string
+		err         error
+	)
+
+	dev = inputtest.NewFakeDevice("Pad", "usb 045e:028e")
+
+	fingerprint, err = mylib.Fingerprint(dev)
+	if err != nil || fingerprint != "usb 045e:028e name Pad" {
+		t.Errorf("Fingerprint = %q, %v, want %q", fingerprint, err, "usb 045e:028e name Pad")
+	}
+
+	dev.Close()
+
+	_, err = mylib.Fingerprint(dev)
+	if !errors.Is(err, inputtest.ErrClosed) {
+		t.Errorf("Fingerprint of a closed device = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,59 @@
+package mylib
+
+import (
+	"context"
+	"fmt"
+)
+
+// Stream reads the events of reader on a new goroutine and delivers them
+// on the first channel until ctx is done or reading fails. The error
+// that ended the stream, ctx.Err() or that of [EventReader.ReadInput],
+// is then sent on the second channel, and both channels are closed.
+//
+// A read already blocked when ctx is done lasts until the next event
+// arrives, unless the backend interrupts it; close the device to stop
+// it at once.
+func Stream(ctx context.Context, reader EventReader) (<-chan Event, <-chan error) {
+	var (
+		events chan Event
+		errs   chan error
+	)
+
+	events = make(chan Event)
+	errs = make(chan error, 1)
+
+	go func() {
+		var (
+			ev  Event
+			err error
+		)
+
+		defer close(errs)
+		defer close(events)
+
+		for {
+			ev, err = reader.ReadInput()
+			if ctx.Err() != nil {
+				errs <- ctx.Err()
+
+				return
+			}
+
+			if err != nil {
+				errs <- fmt.Errorf("mylib.Stream: %w", err)
+
+				return
+			}
+
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
@@ -0,0 +1,95 @@
+//go:build windows
+
+// Package input forwards to [github.com/andrieee44/mylib/x/windows/input],
+// where the experimental Windows backend moved, so that existing imports
+// keep building.
+//
+// Deprecated: Import github.com/andrieee44/mylib/x/windows/input
+// instead. This package will be removed in a future release.
+package input
+
+import (
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/x/windows/input"
+)
+
+var (
+	// ErrClosed is [input.ErrClosed].
+	ErrClosed error = input.ErrClosed
+
+	// ErrDisconnected is [input.ErrDisconnected].
+	ErrDisconnected error = input.ErrDisconnected
+
+	// ErrInvalidEventType is [input.ErrInvalidEventType].
+	ErrInvalidEventType error = input.ErrInvalidEventType
+)
+
+type (
+	// Gamepad is [input.Gamepad].
+	Gamepad = input.Gamepad
+
+	// RawDevice is [input.RawDevice].
+	RawDevice = input.RawDevice
+)
+
+// The event types and codes of [input].
+const (
+	EV_SYN = input.EV_SYN
+	EV_KEY = input.EV_KEY
+	EV_REL = input.EV_REL
+	EV_ABS = input.EV_ABS
+
+	SYN_REPORT = input.SYN_REPORT
+
+	KEY_ESC = input.KEY_ESC
+	KEY_A   = input.KEY_A
+	KEY_F12 = input.KEY_F12
+
+	BTN_LEFT   = input.BTN_LEFT
+	BTN_RIGHT  = input.BTN_RIGHT
+	BTN_MIDDLE = input.BTN_MIDDLE
+	BTN_SIDE   = input.BTN_SIDE
+	BTN_EXTRA  = input.BTN_EXTRA
+
+	BTN_SOUTH      = input.BTN_SOUTH
+	BTN_EAST       = input.BTN_EAST
+	BTN_NORTH      = input.BTN_NORTH
+	BTN_WEST       = input.BTN_WEST
+	BTN_TL         = input.BTN_TL
+	BTN_TR         = input.BTN_TR
+	BTN_SELECT     = input.BTN_SELECT
+	BTN_START      = input.BTN_START
+	BTN_THUMBL     = input.BTN_THUMBL
+	BTN_THUMBR     = input.BTN_THUMBR
+	BTN_DPAD_UP    = input.BTN_DPAD_UP
+	BTN_DPAD_DOWN  = input.BTN_DPAD_DOWN
+	BTN_DPAD_LEFT  = input.BTN_DPAD_LEFT
+	BTN_DPAD_RIGHT = input.BTN_DPAD_RIGHT
+
+	REL_X      = input.REL_X
+	REL_Y      = input.REL_Y
+	REL_HWHEEL = input.REL_HWHEEL
+	REL_WHEEL  = input.REL_WHEEL
+
+	ABS_X  = input.ABS_X
+	ABS_Y  = input.ABS_Y
+	ABS_Z  = input.ABS_Z
+	ABS_RX = input.ABS_RX
+	ABS_RY = input.ABS_RY
+	ABS_RZ = input.ABS_RZ
+)
+
+// Devices calls [input.Devices].
+func Devices() ([]mylib.InputDevice, error) {
+	return input.Devices()
+}
+
+// Gamepads calls [input.Gamepads].
+func Gamepads() ([]*Gamepad, error) {
+	return input.Gamepads()
+}
+
+// RawDevices calls [input.RawDevices].
+func RawDevices() ([]*RawDevice, error) {
+	return input.RawDevices()
+}
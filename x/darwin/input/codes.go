@@ -0,0 +1,108 @@
+//go:build darwin
+
+package input
+
+import "github.com/andrieee44/mylib"
+
+// Event types, numbered like their Linux counterparts.
+const (
+	// EV_SYN marks the end of a frame of events.
+	EV_SYN = 0x00
+
+	// EV_KEY reports keys and buttons.
+	EV_KEY = 0x01
+
+	// EV_REL reports relative motion, such as that of a mouse.
+	EV_REL = 0x02
+
+	// EV_ABS reports absolute axes, such as joystick sticks.
+	EV_ABS = 0x03
+)
+
+// SYN_REPORT is the [EV_SYN] code ending a frame.
+const SYN_REPORT = 0x00
+
+// Selected key and button codes. Every key code of the Linux backend may
+// be reported; these are the ones the mapping refers to.
+const (
+	// KEY_A is the A key.
+	KEY_A = 30
+
+	// BTN_MISC is the first button of devices that are neither mice,
+	// joysticks, nor gamepads.
+	BTN_MISC = 0x100
+
+	// BTN_MOUSE is the first mouse button, the left one.
+	BTN_MOUSE = 0x110
+
+	// BTN_JOYSTICK is the first joystick button, the trigger.
+	BTN_JOYSTICK = 0x120
+
+	// BTN_GAMEPAD is the first gamepad button, the bottom face button.
+	BTN_GAMEPAD = 0x130
+)
+
+// HID usage pages and generic desktop usages, from the HID Usage Tables.
+const (
+	pageGenericDesktop = 0x01
+	pageKeyboard       = 0x07
+	pageButton         = 0x09
+
+	usageJoystick = 0x04
+	usageGamepad  = 0x05
+	usageMouse    = 0x02
+	usageX        = 0x30
+	usageWheel    = 0x38
+)
+
+// hidKeyboard maps keyboard usages to key codes like the hid_keyboard
+// table of the Linux kernel. Zero entries have no key.
+var hidKeyboard [0x82]mylib.InputCode = [0x82]mylib.InputCode{
+	0, 0, 0, 0, 30, 48, 46, 32, 18, 33, 34, 35, 23, 36, 37, 38,
+	50, 49, 24, 25, 16, 19, 31, 20, 22, 47, 17, 45, 21, 44, 2, 3,
+	4, 5, 6, 7, 8, 9, 10, 11, 28, 1, 14, 15, 57, 12, 13, 26,
+	27, 43, 43, 39, 40, 41, 51, 52, 53, 58, 59, 60, 61, 62, 63, 64,
+	65, 66, 67, 68, 87, 88, 99, 70, 119, 110, 102, 104, 111, 107, 109, 106,
+	105, 108, 103, 69, 98, 55, 74, 78, 96, 79, 80, 81, 75, 76, 77, 71,
+	72, 73, 82, 83, 86, 127, 116, 117, 183, 184, 185, 186, 187, 188, 189, 190,
+	191, 192, 193, 194, 134, 138, 130, 132, 128, 129, 131, 137, 133, 135, 136, 113,
+	115, 114,
+}
+
+// hidModifiers maps the keyboard usages 0xe0 through 0xe7, the left and
+// right control, shift, alt, and meta keys, to key codes.
+var hidModifiers [8]mylib.InputCode = [8]mylib.InputCode{29, 42, 56, 125, 97, 54, 100, 126}
+
+// usageCode returns the event type and code of a HID input usage on a
+// device whose primary usage is primary, or false if it has none.
+func usageCode(page, usage uint32, relative bool, primary uint32) (mylib.InputEvent, mylib.InputCode, bool) {
+	switch {
+	case page == pageKeyboard && usage < uint32(len(hidKeyboard)) && hidKeyboard[usage] != 0:
+		return EV_KEY, hidKeyboard[usage], true
+	case page == pageKeyboard && usage >= 0xe0 && usage <= 0xe7:
+		return EV_KEY, hidModifiers[usage-0xe0], true
+	case page == pageButton && usage >= 1 && usage <= 16:
+		return EV_KEY, buttonBase(primary) + mylib.InputCode(usage-1), true
+	case page == pageGenericDesktop && usage >= usageX && usage <= usageWheel && relative:
+		return EV_REL, mylib.InputCode(usage - usageX), true
+	case page == pageGenericDesktop && usage >= usageX && usage <= usageWheel:
+		return EV_ABS, mylib.InputCode(usage - usageX), true
+	default:
+		return 0, 0, false
+	}
+}
+
+// buttonBase returns the code of the first button of a device whose
+// primary generic desktop usage is primary.
+func buttonBase(primary uint32) mylib.InputCode {
+	switch primary {
+	case usageMouse:
+		return BTN_MOUSE
+	case usageJoystick:
+		return BTN_JOYSTICK
+	case usageGamepad:
+		return BTN_GAMEPAD
+	default:
+		return BTN_MISC
+	}
+}
@@ -0,0 +1,405 @@
+//go:build darwin
+
+package input
+
+/*
+#cgo LDFLAGS: -framework IOKit -framework CoreFoundation
+
+#include <stdlib.h>
+#include <CoreFoundation/CoreFoundation.h>
+#include <IOKit/hid/IOHIDManager.h>
+#include <IOKit/hid/IOHIDKeys.h>
+
+enum {
+	mylib_product,
+	mylib_transport,
+	mylib_vendor_id,
+	mylib_product_id,
+	mylib_version,
+	mylib_primary_usage_page,
+	mylib_primary_usage,
+};
+
+typedef struct {
+	uint32_t page;
+	uint32_t usage;
+	int input;
+	int relative;
+} mylib_element;
+
+static CFStringRef mylib_key(int key) {
+	switch (key) {
+	case mylib_product:
+		return CFSTR(kIOHIDProductKey);
+	case mylib_transport:
+		return CFSTR(kIOHIDTransportKey);
+	case mylib_vendor_id:
+		return CFSTR(kIOHIDVendorIDKey);
+	case mylib_product_id:
+		return CFSTR(kIOHIDProductIDKey);
+	case mylib_version:
+		return CFSTR(kIOHIDVersionNumberKey);
+	case mylib_primary_usage_page:
+		return CFSTR(kIOHIDPrimaryUsagePageKey);
+	default:
+		return CFSTR(kIOHIDPrimaryUsageKey);
+	}
+}
+
+// mylib_copy_devices returns the HID devices, each retained, in an array
+// to be freed with free. It sets count to -1 if the manager could not be
+// created. The manager is opened before the devices are copied, as the
+// HID Manager requires. Opening fails for devices the process may not
+// access, such as keyboards without the Input Monitoring permission,
+// but those are still listed, so the result of opening is ignored.
+static IOHIDDeviceRef *mylib_copy_devices(CFIndex *count) {
+	IOHIDManagerRef manager;
+	CFSetRef set;
+	IOHIDDeviceRef *devices;
+	CFIndex i;
+
+	*count = 0;
+
+	manager = IOHIDManagerCreate(kCFAllocatorDefault, kIOHIDOptionsTypeNone);
+	if (manager == NULL) {
+		*count = -1;
+		return NULL;
+	}
+
+	IOHIDManagerSetDeviceMatching(manager, NULL);
+	IOHIDManagerOpen(manager, kIOHIDOptionsTypeNone);
+
+	set = IOHIDManagerCopyDevices(manager);
+	if (set == NULL) {
+		IOHIDManagerClose(manager, kIOHIDOptionsTypeNone);
+		CFRelease(manager);
+		return NULL;
+	}
+
+	*count = CFSetGetCount(set);
+	devices = calloc(*count > 0 ? *count : 1, sizeof(IOHIDDeviceRef));
+	CFSetGetValues(set, (const void **)devices);
+
+	for (i = 0; i < *count; i++) {
+		CFRetain(devices[i]);
+	}
+
+	CFRelease(set);
+	IOHIDManagerClose(manager, kIOHIDOptionsTypeNone);
+	CFRelease(manager);
+
+	return devices;
+}
+
+static void mylib_release(IOHIDDeviceRef device) {
+	CFRelease(device);
+}
+
+static long mylib_number(IOHIDDeviceRef device, int key) {
+	CFTypeRef ref;
+	long value;
+
+	value = 0;
+	ref = IOHIDDeviceGetProperty(device, mylib_key(key));
+	if (ref != NULL && CFGetTypeID(ref) == CFNumberGetTypeID()) {
+		CFNumberGetValue((CFNumberRef)ref, kCFNumberLongType, &value);
+	}
+
+	return value;
+}
+
+// mylib_string returns a string property as UTF-8 to be freed with free,
+// or NULL if the device has none.
+static char *mylib_string(IOHIDDeviceRef device, int key) {
+	CFTypeRef ref;
+	CFIndex size;
+	char *buf;
+
+	ref = IOHIDDeviceGetProperty(device, mylib_key(key));
+	if (ref == NULL || CFGetTypeID(ref) != CFStringGetTypeID()) {
+		return NULL;
+	}
+
+	size = CFStringGetMaximumSizeForEncoding(
+		CFStringGetLength((CFStringRef)ref),
+		kCFStringEncodingUTF8
+	) + 1;
+
+	buf = malloc(size);
+	if (!CFStringGetCString((CFStringRef)ref, buf, size, kCFStringEncodingUTF8)) {
+		free(buf);
+		return NULL;
+	}
+
+	return buf;
+}
+
+// mylib_copy_elements returns the elements of the device in an array to
+// be freed with free.
+static mylib_element *mylib_copy_elements(IOHIDDeviceRef device, CFIndex *count) {
+	CFArrayRef elements;
+	IOHIDElementRef element;
+	IOHIDElementType type;
+	mylib_element *out;
+	CFIndex i;
+
+	*count = 0;
+
+	elements = IOHIDDeviceCopyMatchingElements(device, NULL, kIOHIDOptionsTypeNone);
+	if (elements == NULL) {
+		return NULL;
+	}
+
+	*count = CFArrayGetCount(elements);
+	out = calloc(*count > 0 ? *count : 1, sizeof(mylib_element));
+
+	for (i = 0; i < *count; i++) {
+		element = (IOHIDElementRef)CFArrayGetValueAtIndex(elements, i);
+		type = IOHIDElementGetType(element);
+
+		out[i].page = IOHIDElementGetUsagePage(element);
+		out[i].usage = IOHIDElementGetUsage(element);
+		out[i].input = type == kIOHIDElementTypeInput_Misc ||
+			type == kIOHIDElementTypeInput_Button ||
+			type == kIOHIDElementTypeInput_Axis;
+		out[i].relative = IOHIDElementIsRelative(element);
+	}
+
+	CFRelease(elements);
+
+	return out;
+}
+*/
+import "C"
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"unsafe"
+
+	"github.com/andrieee44/mylib"
+)
+
+// ErrClosed is returned by the methods of a [Device] once it is closed.
+var ErrClosed error = errors.New("device closed")
+
+// ErrInvalidEventType is returned when a device is asked for the codes
+// of an event type it does not support.
+var ErrInvalidEventType error = errors.New("invalid event type")
+
+// ErrHIDManager is returned by [Devices] when the HID Manager cannot be
+// created.
+var ErrHIDManager error = errors.New("cannot create the IOKit HID Manager")
+
+// Device is a HID device known to the IOKit HID Manager. It describes
+// the device but does not read its events, so it implements
+// [mylib.InputDevice] but not [mylib.EventReader]. Its methods are safe
+// for concurrent use, including with Close.
+type Device struct {
+	ref    C.IOHIDDeviceRef
+	codes  map[mylib.InputEvent][]mylib.InputCode
+	mutex  sync.RWMutex
+	closed bool
+}
+
+var _ mylib.InputDevice = (*Device)(nil)
+
+// Devices returns every HID device, such as keyboards, mice, and
+// gamepads, whether built in or attached over USB or Bluetooth. The
+// caller closes them.
+func Devices() ([]*Device, error) {
+	var (
+		count   C.CFIndex
+		refs    *C.IOHIDDeviceRef
+		ref     C.IOHIDDeviceRef
+		devices []*Device
+	)
+
+	refs = C.mylib_copy_devices(&count)
+	if count < 0 {
+		return nil, fmt.Errorf("input.Devices: %w", ErrHIDManager)
+	}
+
+	if refs == nil {
+		return nil, nil
+	}
+
+	defer C.free(unsafe.Pointer(refs))
+
+	for _, ref = range unsafe.Slice(refs, int(count)) {
+		devices = append(devices, newDevice(ref))
+	}
+
+	return devices, nil
+}
+
+// newDevice wraps a retained device and maps the usages of its input
+// elements to event codes.
+func newDevice(ref C.IOHIDDeviceRef) *Device {
+	var (
+		dev       *Device
+		primary   uint32
+		count     C.CFIndex
+		elements  *C.mylib_element
+		element   C.mylib_element
+		eventType mylib.InputEvent
+		code      mylib.InputCode
+		ok        bool
+	)
+
+	dev = &Device{
+		ref:   ref,
+		codes: make(map[mylib.InputEvent][]mylib.InputCode),
+	}
+
+	if C.mylib_number(ref, C.mylib_primary_usage_page) == pageGenericDesktop {
+		primary = uint32(C.mylib_number(ref, C.mylib_primary_usage))
+	}
+
+	elements = C.mylib_copy_elements(ref, &count)
+	if elements == nil {
+		return dev
+	}
+
+	defer C.free(unsafe.Pointer(elements))
+
+	for _, element = range unsafe.Slice(elements, int(count)) {
+		if element.input == 0 {
+			continue
+		}
+
+		eventType, code, ok = usageCode(
+			uint32(element.page),
+			uint32(element.usage),
+			element.relative != 0,
+			primary,
+		)
+		if ok && !slices.Contains(dev.codes[eventType], code) {
+			dev.codes[eventType] = append(dev.codes[eventType], code)
+		}
+	}
+
+	for eventType = range dev.codes {
+		slices.Sort(dev.codes[eventType])
+	}
+
+	if len(dev.codes) != 0 {
+		dev.codes[EV_SYN] = []mylib.InputCode{SYN_REPORT}
+	}
+
+	return dev
+}
+
+// Name returns the product name of the device, or an empty string if it
+// reports none.
+func (dev *Device) Name() (string, error) {
+	var name *C.char
+
+	dev.mutex.RLock()
+	defer dev.mutex.RUnlock()
+
+	if dev.closed {
+		return "", fmt.Errorf("Device.Name: %w", ErrClosed)
+	}
+
+	name = C.mylib_string(dev.ref, C.mylib_product)
+	if name == nil {
+		return "", nil
+	}
+
+	defer C.free(unsafe.Pointer(name))
+
+	return C.GoString(name), nil
+}
+
+// ID returns the bus, vendor, product, and version of the device in the
+// format of the Linux backend, such as
+// "bus 0x3 vendor 0x46d product 0xc24f version 0x111". The bus is 0x3
+// for USB, 0x5 for Bluetooth, and 0 for other transports.
+func (dev *Device) ID() (string, error) {
+	var (
+		transport *C.char
+		bus       int
+	)
+
+	dev.mutex.RLock()
+	defer dev.mutex.RUnlock()
+
+	if dev.closed {
+		return "", fmt.Errorf("Device.ID: %w", ErrClosed)
+	}
+
+	transport = C.mylib_string(dev.ref, C.mylib_transport)
+	if transport != nil {
+		switch C.GoString(transport) {
+		case "USB":
+			bus = 0x03
+		case "Bluetooth", "Bluetooth Low Energy":
+			bus = 0x05
+		}
+
+		C.free(unsafe.Pointer(transport))
+	}
+
+	return fmt.Sprintf(
+		"bus %#x vendor %#x product %#x version %#x",
+		bus,
+		int(C.mylib_number(dev.ref, C.mylib_vendor_id)),
+		int(C.mylib_number(dev.ref, C.mylib_product_id)),
+		int(C.mylib_number(dev.ref, C.mylib_version)),
+	), nil
+}
+
+// Events returns the event types the usages of the device map to, in
+// ascending order.
+func (dev *Device) Events() ([]mylib.InputEvent, error) {
+	dev.mutex.RLock()
+	defer dev.mutex.RUnlock()
+
+	if dev.closed {
+		return nil, fmt.Errorf("Device.Events: %w", ErrClosed)
+	}
+
+	return slices.Sorted(maps.Keys(dev.codes)), nil
+}
+
+// Codes returns the codes of eventType the usages of the device map to,
+// in ascending order.
+func (dev *Device) Codes(eventType mylib.InputEvent) ([]mylib.InputCode, error) {
+	var (
+		codes []mylib.InputCode
+		ok    bool
+	)
+
+	dev.mutex.RLock()
+	defer dev.mutex.RUnlock()
+
+	if dev.closed {
+		return nil, fmt.Errorf("Device.Codes: %w", ErrClosed)
+	}
+
+	codes, ok = dev.codes[eventType]
+	if !ok {
+		return nil, fmt.Errorf("Device.Codes: %w %d", ErrInvalidEventType, eventType)
+	}
+
+	return slices.Clone(codes), nil
+}
+
+// Close releases the device. It waits for the methods running
+// concurrently to return, after which they return [ErrClosed]. Calling
+// it again does nothing.
+func (dev *Device) Close() error {
+	dev.mutex.Lock()
+	defer dev.mutex.Unlock()
+
+	if !dev.closed {
+		dev.closed = true
+		C.mylib_release(dev.ref)
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+//go:build darwin
+
+// Package input implements the [mylib.InputDevice] interface on macOS
+// with the [IOKit HID Manager]:
+//
+//	devs, err := input.Devices()
+//	if err != nil {
+//		return err
+//	}
+//
+//	for _, dev := range devs {
+//		name, _ := dev.Name()
+//		fmt.Println(name)
+//	}
+//
+// The HID usages of each device are mapped to event types and codes
+// numbered like those of the Linux backend, the way the Linux kernel's
+// HID driver maps them: keyboard usages to keys such as [KEY_A],
+// buttons to mouse, joystick, or gamepad buttons depending on the kind
+// of device, and the generic desktop axes to [EV_ABS] or [EV_REL] codes.
+//
+// The package only enumerates devices: a [Device] reports its name, ID,
+// and capabilities, but does not implement [mylib.EventReader], since
+// reading events needs an input value callback on a run loop that this
+// package does not provide. Use the HID Manager directly to read them.
+//
+// The package needs cgo. It is experimental and its API may change in
+// any release.
+//
+// [IOKit HID Manager]: https://developer.apple.com/documentation/iokit/iohidmanager_h
+package input
@@ -0,0 +1,24 @@
+//go:build darwin
+
+package input
+
+import "github.com/andrieee44/mylib"
+
+//go:generate go run ../../../linux/input/gen_names.go -src ../../../linux/input -tag darwin -types EV_SYN,EV_KEY,EV_REL,EV_ABS
+
+func init() {
+	mylib.RegisterNamer(tableNamer{})
+}
+
+// tableNamer is the [mylib.Namer] of this package. Its tables are
+// generated from the Linux names, since this package numbers its events
+// like Linux.
+type tableNamer struct{}
+
+func (tableNamer) EventName(eventType mylib.InputEvent) string {
+	return eventNames[eventType]
+}
+
+func (tableNamer) CodeName(eventType mylib.InputEvent, code mylib.InputCode) string {
+	return codeNames[eventType][code]
+}
@@ -0,0 +1,329 @@
+//go:build linux
+
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/andrieee44/mylib/linux/xdg"
+)
+
+// ErrIncludeCycle is returned when a file includes itself, directly or
+// through other files.
+var ErrIncludeCycle error = errors.New("include cycle")
+
+// Entry is a single key-value pair read from a configuration file.
+type Entry struct {
+	// Section is the name of the enclosing section, or empty for entries
+	// before the first section header.
+	Section string
+
+	// Key is the entry's key, with surrounding whitespace removed.
+	Key string
+
+	// Value is the entry's value, with surrounding whitespace removed and
+	// environment variables expanded.
+	Value string
+
+	// File is the file the entry was read from.
+	File string
+
+	// Line is the 1-based line number of the entry in File.
+	Line int
+}
+
+// Config holds the entries of a configuration file and of every file it
+// includes, in the order they were read.
+type Config struct {
+	// Entries holds every entry in reading order.
+	Entries []Entry
+
+	// Files lists every file that was read, in reading order.
+	Files []string
+}
+
+// SyntaxError reports a malformed line in a configuration file.
+type SyntaxError struct {
+	// File is the file containing the malformed line.
+	File string
+
+	// Line is the 1-based line number of the malformed line.
+	Line int
+
+	// Msg describes the problem.
+	Msg string
+}
+
+// Loader reads configuration files from FS, expanding environment
+// variables with Getenv and searching the XDG configuration directories
+// of Env.
+type Loader struct {
+	// Getenv looks up environment variables referenced in values and
+	// include paths.
+	Getenv func(string) string
+
+	// Env holds the XDG base directories searched by [Loader.Find].
+	Env *xdg.Environment
+
+	// FS is the filesystem files are read from. Its root stands for the
+	// root directory, so that tests can load configuration from an
+	// in-memory tree, such as an [fstest.MapFS], instead of the host.
+	//
+	// [fstest.MapFS]: https://pkg.go.dev/testing/fstest#MapFS
+	FS fs.FS
+}
+
+// NewLoader returns a Loader that looks up environment variables and XDG
+// base directories with getenv and reads files from os.DirFS("/"). If
+// getenv is nil, [os.Getenv] is used.
+func NewLoader(getenv func(string) string) *Loader {
+	if getenv == nil {
+		getenv = os.Getenv
+	}
+
+	return &Loader{
+		Getenv: getenv,
+		Env:    xdg.NewEnvironment(getenv),
+		FS:     os.DirFS("/"),
+	}
+}
+
+// Load reads the configuration file at path using NewLoader(nil).
+func Load(path string) (*Config, error) {
+	return NewLoader(nil).Load(path)
+}
+
+// Find reads the configuration file relPath using NewLoader(nil).
+func Find(relPath string) (*Config, error) {
+	return NewLoader(nil).Find(relPath)
+}
+
+// Error implements the error interface.
+func (err *SyntaxError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", err.File, err.Line, err.Msg)
+}
+
+// Load reads the configuration file at path and the files it includes.
+// A relative path is resolved against the working directory.
+func (loader *Loader) Load(path string) (*Config, error) {
+	var (
+		cfg *Config
+		err error
+	)
+
+	cfg = &Config{}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("Loader.Load: %w", err)
+	}
+
+	err = loader.load(cfg, path, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("Loader.Load: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Find reads the first file named relPath in the XDG configuration
+// directories, searching ConfigHome and then each of ConfigDirs in order.
+// It returns an error wrapping [fs.ErrNotExist] if none exists.
+func (loader *Loader) Find(relPath string) (*Config, error) {
+	var (
+		env  xdg.Environment
+		path string
+		cfg  *Config
+		err  error
+	)
+
+	env = *loader.Env
+	env.FS = loader.FS
+
+	path, err = env.FindConfig(relPath)
+	if err != nil {
+		return nil, fmt.Errorf("Loader.Find: %w", err)
+	}
+
+	cfg, err = loader.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("Loader.Find: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Get returns the value of the last entry with the given section and key,
+// so that later files and lines override earlier ones.
+func (cfg *Config) Get(section, key string) (string, bool) {
+	var i int
+
+	for i = len(cfg.Entries) - 1; i >= 0; i-- {
+		if cfg.Entries[i].Section == section && cfg.Entries[i].Key == key {
+			return cfg.Entries[i].Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Section returns the entries of the named section in reading order.
+func (cfg *Config) Section(name string) []Entry {
+	var (
+		entries []Entry
+		entry   Entry
+	)
+
+	for _, entry = range cfg.Entries {
+		if entry.Section == name {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// Sections returns the names of the sections that hold entries, in the
+// order they first appear.
+func (cfg *Config) Sections() []string {
+	var (
+		names []string
+		entry Entry
+	)
+
+	for _, entry = range cfg.Entries {
+		if !slices.Contains(names, entry.Section) {
+			names = append(names, entry.Section)
+		}
+	}
+
+	return names
+}
+
+// load reads path into cfg. Entries start in section, and section
+// headers in path do not affect the including file. stack holds the
+// files currently being read, to detect include cycles.
+func (loader *Loader) load(cfg *Config, path, section string, stack []string) error {
+	var (
+		file    fs.File
+		scanner *bufio.Scanner
+		line    string
+		lineNum int
+		key     string
+		value   string
+		ok      bool
+		err     error
+	)
+
+	if slices.Contains(stack, path) {
+		return fmt.Errorf("%w: %s", ErrIncludeCycle, strings.Join(append(stack, path), " -> "))
+	}
+
+	file, err = loader.FS.Open(fsName(path))
+	if err != nil {
+		return hostPathError(err, path)
+	}
+
+	defer file.Close()
+
+	stack = append(stack, path)
+	cfg.Files = append(cfg.Files, path)
+	scanner = bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		lineNum++
+		line = strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "", line[0] == '#', line[0] == ';':
+		case line[0] == '[':
+			if line[len(line)-1] != ']' {
+				return &SyntaxError{File: path, Line: lineNum, Msg: "unterminated section header"}
+			}
+
+			section = strings.TrimSpace(line[1 : len(line)-1])
+		case strings.HasPrefix(line, "include ") || strings.HasPrefix(line, "include\t"):
+			err = loader.include(cfg, path, strings.TrimSpace(line[len("include"):]), section, stack)
+			if err != nil {
+				return err
+			}
+		default:
+			key, value, ok = strings.Cut(line, "=")
+			key = strings.TrimSpace(key)
+
+			if !ok || key == "" {
+				return &SyntaxError{File: path, Line: lineNum, Msg: "expected key = value"}
+			}
+
+			cfg.Entries = append(cfg.Entries, Entry{
+				Section: section,
+				Key:     key,
+				Value:   os.Expand(strings.TrimSpace(value), loader.Getenv),
+				File:    path,
+				Line:    lineNum,
+			})
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (loader *Loader) include(cfg *Config, from, pattern, section string, stack []string) error {
+	var (
+		paths []string
+		path  string
+		err   error
+	)
+
+	pattern = os.Expand(pattern, loader.Getenv)
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(from), pattern)
+	}
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return loader.load(cfg, filepath.Clean(pattern), section, stack)
+	}
+
+	paths, err = fs.Glob(loader.FS, fsName(pattern))
+	if err != nil {
+		return err
+	}
+
+	for _, path = range paths {
+		err = loader.load(cfg, "/"+path, section, stack)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fsName converts the absolute path to the equivalent name in a Loader's
+// FS.
+func fsName(path string) string {
+	if path == "/" {
+		return "."
+	}
+
+	return strings.TrimPrefix(path, "/")
+}
+
+// hostPathError restores the absolute path in a [fs.PathError] returned
+// by a Loader's FS, which only knows the relative name.
+func hostPathError(err error, path string) error {
+	var pathErr *fs.PathError
+
+	if errors.As(err, &pathErr) {
+		pathErr.Path = path
+	}
+
+	return err
+}
@@ -0,0 +1,272 @@
+//go:build linux
+
+package config_test
+
+import (
+	"errors"
+	"io/fs"
+	"slices"
+	"testing"
+	"testing/fstest"
+
+	"github.com/andrieee44/mylib/x/linux/config"
+)
+
+type loadTest struct {
+	name    string
+	files   map[string]string
+	entries []config.Entry
+	read    []string
+	err     error
+	syntax  *config.SyntaxError
+}
+
+// testLoader returns a Loader reading files, named by their paths
+// relative to the root, with a fixed environment: HOME, XDG_CONFIG_DIRS,
+// DIR as /etc/tool, and V as "value".
+func testLoader(files map[string]string) *config.Loader {
+	var (
+		loader *config.Loader
+		fsys   fstest.MapFS
+		name   string
+	)
+
+	loader = config.NewLoader(func(key string) string {
+		return map[string]string{
+			"HOME":            "/home/user",
+			"XDG_CONFIG_DIRS": "/etc/xdg:/usr/share/xdg",
+			"DIR":             "/etc/tool",
+			"V":               "value",
+		}[key]
+	})
+
+	fsys = make(fstest.MapFS)
+	for name = range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(files[name])}
+	}
+
+	loader.FS = fsys
+
+	return loader
+}
+
+func TestLoaderLoad(t *testing.T) {
+	var (
+		tests  []loadTest
+		test   loadTest
+		cfg    *config.Config
+		syntax *config.SyntaxError
+		err    error
+	)
+
+	tests = []loadTest{
+		{
+			name: "entries",
+			files: map[string]string{
+				"etc/tool/tool.conf": "top = 1\n" +
+					"# comment\n" +
+					"; comment\n" +
+					"\n" +
+					"[a]\n" +
+					"  x = $V \n" +
+					"y=${V}s\n" +
+					"[ b ]\n" +
+					"empty =\n" +
+					"url = http://host/?q=1\n",
+			},
+			entries: []config.Entry{
+				{Key: "top", Value: "1", File: "/etc/tool/tool.conf", Line: 1},
+				{Section: "a", Key: "x", Value: "value", File: "/etc/tool/tool.conf", Line: 6},
+				{Section: "a", Key: "y", Value: "values", File: "/etc/tool/tool.conf", Line: 7},
+				{Section: "b", Key: "empty", File: "/etc/tool/tool.conf", Line: 9},
+				{Section: "b", Key: "url", Value: "http://host/?q=1", File: "/etc/tool/tool.conf", Line: 10},
+			},
+			read: []string{"/etc/tool/tool.conf"},
+		},
+		{
+			name: "include",
+			files: map[string]string{
+				"etc/tool/tool.conf": "[a]\ninclude sub.conf\nk = main\n",
+				"etc/tool/sub.conf":  "k = sub\n[b]\nj = 1\n",
+			},
+			entries: []config.Entry{
+				{Section: "a", Key: "k", Value: "sub", File: "/etc/tool/sub.conf", Line: 1},
+				{Section: "b", Key: "j", Value: "1", File: "/etc/tool/sub.conf", Line: 3},
+				{Section: "a", Key: "k", Value: "main", File: "/etc/tool/tool.conf", Line: 3},
+			},
+			read: []string{"/etc/tool/tool.conf", "/etc/tool/sub.conf"},
+		},
+		{
+			name: "include glob",
+			files: map[string]string{
+				"etc/tool/tool.conf":     "include\tconf.d/*.conf\n",
+				"etc/tool/conf.d/b.conf": "k = b\n",
+				"etc/tool/conf.d/a.conf": "k = a\n",
+				"etc/tool/conf.d/a.bak":  "k = bak\n",
+			},
+			entries: []config.Entry{
+				{Key: "k", Value: "a", File: "/etc/tool/conf.d/a.conf", Line: 1},
+				{Key: "k", Value: "b", File: "/etc/tool/conf.d/b.conf", Line: 1},
+			},
+			read: []string{"/etc/tool/tool.conf", "/etc/tool/conf.d/a.conf", "/etc/tool/conf.d/b.conf"},
+		},
+		{
+			name: "include glob without matches",
+			files: map[string]string{
+				"etc/tool/tool.conf": "include conf.d/*.conf\n",
+			},
+			read: []string{"/etc/tool/tool.conf"},
+		},
+		{
+			name: "include variable",
+			files: map[string]string{
+				"etc/tool/tool.conf":   "include ${HOME}/local.conf\n",
+				"home/user/local.conf": "k = v\n",
+			},
+			entries: []config.Entry{{Key: "k", Value: "v", File: "/home/user/local.conf", Line: 1}},
+			read:    []string{"/etc/tool/tool.conf", "/home/user/local.conf"},
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"etc/tool/tool.conf": "include sub.conf\n",
+				"etc/tool/sub.conf":  "include $DIR/tool.conf\n",
+			},
+			err: config.ErrIncludeCycle,
+		},
+		{
+			name: "missing include",
+			files: map[string]string{
+				"etc/tool/tool.conf": "include missing.conf\n",
+			},
+			err: fs.ErrNotExist,
+		},
+		{
+			name: "unterminated section header",
+			files: map[string]string{
+				"etc/tool/tool.conf": "k = v\n[a\n",
+			},
+			syntax: &config.SyntaxError{File: "/etc/tool/tool.conf", Line: 2, Msg: "unterminated section header"},
+		},
+		{
+			name: "missing equals sign",
+			files: map[string]string{
+				"etc/tool/tool.conf": "[a]\nk v\n",
+			},
+			syntax: &config.SyntaxError{File: "/etc/tool/tool.conf", Line: 2, Msg: "expected key = value"},
+		},
+		{
+			name: "empty key in an include",
+			files: map[string]string{
+				"etc/tool/tool.conf": "include sub.conf\n",
+				"etc/tool/sub.conf":  "\n = v\n",
+			},
+			syntax: &config.SyntaxError{File: "/etc/tool/sub.conf", Line: 2, Msg: "expected key = value"},
+		},
+	}
+
+	for _, test = range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err = testLoader(test.files).Load("/etc/tool/tool.conf")
+
+			switch {
+			case test.syntax != nil:
+				if !errors.As(err, &syntax) || *syntax != *test.syntax {
+					t.Fatalf("Load = %v, want %v", err, test.syntax)
+				}
+
+				return
+			case test.err != nil:
+				if !errors.Is(err, test.err) {
+					t.Fatalf("Load = %v, want %v", err, test.err)
+				}
+
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(cfg.Entries, test.entries) {
+				t.Errorf("Entries = %+v, want %+v", cfg.Entries, test.entries)
+			}
+
+			if !slices.Equal(cfg.Files, test.read) {
+				t.Errorf("Files = %q, want %q", cfg.Files, test.read)
+			}
+		})
+	}
+}
+
+func TestLoaderFind(t *testing.T) {
+	var (
+		loader *config.Loader
+		cfg    *config.Config
+		value  string
+		err    error
+	)
+
+	loader = testLoader(map[string]string{
+		"home/user/.config/tool/tool.conf": "k = home\n",
+		"etc/xdg/tool/tool.conf":           "k = etc\n",
+		"etc/xdg/tool/dirs.conf":           "k = etc\n",
+		"usr/share/xdg/tool/dirs.conf":     "k = usr\n",
+	})
+
+	cfg, err = loader.Find("tool/tool.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, _ = cfg.Get("", "k")
+	if value != "home" {
+		t.Errorf("found k = %q, want ConfigHome searched first", value)
+	}
+
+	cfg, err = loader.Find("tool/dirs.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value, _ = cfg.Get("", "k")
+	if value != "etc" {
+		t.Errorf("found k = %q, want ConfigDirs searched in order", value)
+	}
+
+	_, err = loader.Find("tool/missing.conf")
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Find = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestConfigQueries(t *testing.T) {
+	var (
+		cfg   *config.Config
+		value string
+		ok    bool
+	)
+
+	cfg = &config.Config{Entries: []config.Entry{
+		{Section: "b", Key: "k", Value: "1"},
+		{Key: "top", Value: "2"},
+		{Section: "a", Key: "k", Value: "3"},
+		{Section: "b", Key: "k", Value: "4"},
+	}}
+
+	value, ok = cfg.Get("b", "k")
+	if !ok || value != "4" {
+		t.Errorf("Get(b, k) = %q, %t, want the later %q", value, ok, "4")
+	}
+
+	value, ok = cfg.Get("a", "top")
+	if ok {
+		t.Errorf("Get(a, top) = %q, want no entry", value)
+	}
+
+	if !slices.Equal(cfg.Sections(), []string{"b", "", "a"}) {
+		t.Errorf("Sections = %q, want %q", cfg.Sections(), []string{"b", "", "a"})
+	}
+
+	if !slices.Equal(cfg.Section("b"), []config.Entry{cfg.Entries[0], cfg.Entries[3]}) {
+		t.Errorf("Section(b) = %+v", cfg.Section("b"))
+	}
+}
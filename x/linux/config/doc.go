@@ -0,0 +1,26 @@
+//go:build linux
+
+// Package config loads small INI-like configuration files shared by the
+// tools in this module. A file is a sequence of lines, each one of:
+//
+//	# comment, also ; comment
+//	[section]
+//	key = value
+//	include path
+//
+// Values and include paths may reference environment variables as $VAR
+// or ${VAR}. Relative include paths are resolved against the directory of
+// the including file and may contain glob patterns, such as
+// "conf.d/*.conf", whose matches are included in lexical order.
+//
+// A tool finds and reads its file from the XDG config directories with:
+//
+//	cfg, err := config.Find("mytool/mytool.conf")
+//	if err != nil {
+//		return err
+//	}
+//
+//	delay, ok := cfg.Get("keyboard", "repeat_delay")
+//
+// This package is experimental and its API may change in any release.
+package config
@@ -0,0 +1,54 @@
+//go:build linux
+
+package config_test
+
+import (
+	"fmt"
+	"log"
+	"testing/fstest"
+
+	"github.com/andrieee44/mylib/x/linux/config"
+)
+
+func ExampleLoader_Find() {
+	var (
+		loader *config.Loader
+		cfg    *config.Config
+		entry  config.Entry
+		delay  string
+		err    error
+	)
+
+	loader = config.NewLoader(func(key string) string {
+		return map[string]string{
+			"HOME":  "/home/user",
+			"DELAY": "250",
+		}[key]
+	})
+
+	// An in-memory tree stands in for the host's files.
+	loader.FS = fstest.MapFS{
+		"home/user/.config/mytool/mytool.conf": {
+			Data: []byte("[keyboard]\nrepeat_delay = $DELAY\ninclude conf.d/*.conf\n"),
+		},
+		"home/user/.config/mytool/conf.d/fast.conf": {
+			Data: []byte("[keyboard]\nrepeat_rate = 40\n"),
+		},
+	}
+
+	cfg, err = loader.Find("mytool/mytool.conf")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	delay, _ = cfg.Get("keyboard", "repeat_delay")
+	fmt.Println("repeat_delay", delay)
+
+	for _, entry = range cfg.Section("keyboard") {
+		fmt.Printf("%s:%d: %s = %s\n", entry.File, entry.Line, entry.Key, entry.Value)
+	}
+	// Output:
+	// repeat_delay 250
+	// /home/user/.config/mytool/mytool.conf:2: repeat_delay = 250
+	// /home/user/.config/mytool/conf.d/fast.conf:2: repeat_rate = 40
+}
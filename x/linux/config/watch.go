@@ -0,0 +1,88 @@
+//go:build linux
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/andrieee44/mylib/internal/inotify"
+	"golang.org/x/sys/unix"
+)
+
+// Watch reports changes to the files at paths with inotify. The returned
+// channel receives the path of a file whenever it is written, created,
+// replaced, or removed. It watches the directories containing the files
+// rather than the files themselves, so that editors replacing a file by
+// renaming a new one over it are noticed too. The directories must
+// exist. The channel is closed when ctx is done or watching fails.
+func Watch(ctx context.Context, paths ...string) (<-chan string, error) {
+	const mask = unix.IN_CLOSE_WRITE | unix.IN_CREATE | unix.IN_DELETE |
+		unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+
+	var (
+		watcher *inotify.Watcher
+		dirs    map[int]string
+		watched map[string]bool
+		path    string
+		wd      int
+		changes chan string
+		err     error
+	)
+
+	watcher, err = inotify.New()
+	if err != nil {
+		return nil, fmt.Errorf("config.Watch: %w", err)
+	}
+
+	dirs = make(map[int]string)
+	watched = make(map[string]bool)
+
+	for _, path = range paths {
+		path = filepath.Clean(path)
+		watched[path] = true
+
+		wd, err = watcher.Add(filepath.Dir(path), mask)
+		if err != nil {
+			_ = watcher.Close()
+
+			return nil, fmt.Errorf("config.Watch: %w", err)
+		}
+
+		dirs[wd] = filepath.Dir(path)
+	}
+
+	changes = make(chan string)
+
+	go readEvents(ctx, watcher, dirs, watched, changes)
+
+	return changes, nil
+}
+
+func readEvents(
+	ctx context.Context,
+	watcher *inotify.Watcher,
+	dirs map[int]string,
+	watched map[string]bool,
+	changes chan<- string,
+) {
+	defer close(changes)
+	defer watcher.Close()
+
+	_ = watcher.Run(ctx, func(ev inotify.Event) bool {
+		var path string
+
+		path = filepath.Join(dirs[ev.Wd], ev.Name)
+		if !watched[path] {
+			return true
+		}
+
+		select {
+		case changes <- path:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	})
+}
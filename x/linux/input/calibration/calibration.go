@@ -0,0 +1,340 @@
+//go:build linux
+
+package calibration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/andrieee44/mylib"
+	"github.com/andrieee44/mylib/linux/input"
+	"github.com/andrieee44/mylib/linux/xdg"
+)
+
+// DefaultFile is the path of the calibration file shared by programs
+// using this package, relative to $XDG_STATE_HOME.
+const DefaultFile = "mylib/input-calibration.json"
+
+// Axis is the calibration of one absolute axis.
+type Axis struct {
+	// Minimum and Maximum replace the range reported by the driver.
+	Minimum int32 `json:"minimum"`
+	Maximum int32 `json:"maximum"`
+
+	// Flat replaces the dead zone reported by the driver.
+	Flat int32 `json:"flat"`
+
+	// Fuzz, if nonzero, replaces the noise filter reported by the
+	// driver.
+	Fuzz int32 `json:"fuzz,omitempty"`
+
+	// Inverted flips the direction of the axis.
+	Inverted bool `json:"inverted,omitempty"`
+}
+
+// Profile holds the calibrated axes of one device, keyed by ABS_* code.
+// Axes without an entry keep the parameters reported by the driver.
+type Profile map[mylib.InputCode]Axis
+
+// Store holds the profiles of every calibrated device, as read from and
+// written to a file under $XDG_STATE_HOME.
+type Store struct {
+	path     string
+	profiles map[input.ID]Profile
+}
+
+// Load reads the store at relPath under $XDG_STATE_HOME, such as
+// [DefaultFile]. A missing file is created and yields an empty store.
+func Load(relPath string) (*Store, error) {
+	var (
+		store *Store
+		data  []byte
+		err   error
+	)
+
+	store = &Store{path: relPath, profiles: make(map[input.ID]Profile)}
+
+	data, err = readState(relPath)
+	if err != nil {
+		return nil, fmt.Errorf("calibration.Load: %w", err)
+	}
+
+	if len(data) == 0 {
+		return store, nil
+	}
+
+	err = store.decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("calibration.Load: %s: %w", relPath, err)
+	}
+
+	return store, nil
+}
+
+// Profile returns the profile of the device with id, or nil if it has
+// none.
+func (store *Store) Profile(id input.ID) Profile {
+	return store.profiles[id]
+}
+
+// SetProfile replaces the profile of the device with id. A nil or empty
+// profile removes it. Call [Store.Save] to persist the change.
+func (store *Store) SetProfile(id input.ID, profile Profile) {
+	if len(profile) == 0 {
+		delete(store.profiles, id)
+
+		return
+	}
+
+	store.profiles[id] = profile
+}
+
+// Save writes the store back to its file.
+func (store *Store) Save() error {
+	var (
+		data []byte
+		err  error
+	)
+
+	data, err = store.encode()
+	if err == nil {
+		err = writeState(store.path, data)
+	}
+
+	if err != nil {
+		return fmt.Errorf("Store.Save: %w", err)
+	}
+
+	return nil
+}
+
+// NewAxis returns an [input.Axis] for the axis code of dev, using the
+// calibration stored for dev when there is one and the parameters
+// reported by the driver otherwise.
+func (store *Store) NewAxis(dev *input.Device, code mylib.InputCode, trigger bool) (*input.Axis, error) {
+	var (
+		id       input.ID
+		info     input.AbsInfo
+		inverted bool
+		axis     *input.Axis
+		err      error
+	)
+
+	id, err = dev.InputID()
+	if err == nil {
+		info, err = dev.AbsInfo(code)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("Store.NewAxis: %w", err)
+	}
+
+	info, inverted = store.Profile(id).Apply(code, info)
+
+	axis = input.NewAxis(info, trigger)
+	axis.SetInverted(inverted)
+
+	return axis, nil
+}
+
+// Apply returns info with the calibration of axis code applied, and
+// whether the axis is inverted. Without a calibration for code, info is
+// returned unchanged.
+func (profile Profile) Apply(code mylib.InputCode, info input.AbsInfo) (input.AbsInfo, bool) {
+	var (
+		axis Axis
+		ok   bool
+	)
+
+	axis, ok = profile[code]
+	if !ok {
+		return info, false
+	}
+
+	info.Minimum = axis.Minimum
+	info.Maximum = axis.Maximum
+	info.Flat = axis.Flat
+
+	if axis.Fuzz != 0 {
+		info.Fuzz = axis.Fuzz
+	}
+
+	return info, axis.Inverted
+}
+
+// fileDoc is the layout of the calibration file: profiles keyed by
+// "bus:vendor:product:version" in hexadecimal, each holding axes keyed
+// by name, such as "ABS_X".
+type fileDoc struct {
+	Profiles map[string]map[string]Axis `json:"profiles"`
+}
+
+func (store *Store) encode() ([]byte, error) {
+	var (
+		doc     fileDoc
+		id      input.ID
+		profile Profile
+		axes    map[string]Axis
+		code    mylib.InputCode
+		axis    Axis
+		name    string
+	)
+
+	doc.Profiles = make(map[string]map[string]Axis, len(store.profiles))
+
+	for id, profile = range store.profiles {
+		axes = make(map[string]Axis, len(profile))
+		for code, axis = range profile {
+			name = input.CodeName(input.EV_ABS, code)
+			if name == "" {
+				name = strconv.FormatUint(uint64(code), 10)
+			}
+
+			axes[name] = axis
+		}
+
+		doc.Profiles[fmt.Sprintf(
+			"%04x:%04x:%04x:%04x",
+			id.Bustype,
+			id.Vendor,
+			id.Product,
+			id.Version,
+		)] = axes
+	}
+
+	return json.MarshalIndent(doc, "", "\t")
+}
+
+func (store *Store) decode(data []byte) error {
+	var (
+		doc     fileDoc
+		key     string
+		axes    map[string]Axis
+		id      input.ID
+		profile Profile
+		name    string
+		axis    Axis
+		code    mylib.InputCode
+		err     error
+	)
+
+	err = json.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+
+	for key, axes = range doc.Profiles {
+		id, err = parseID(key)
+		if err != nil {
+			return err
+		}
+
+		profile = make(Profile, len(axes))
+		for name, axis = range axes {
+			code, err = parseAxis(name)
+			if err != nil {
+				return err
+			}
+
+			profile[code] = axis
+		}
+
+		store.profiles[id] = profile
+	}
+
+	return nil
+}
+
+func parseID(key string) (input.ID, error) {
+	var (
+		fields []string
+		values [4]uint64
+		i      int
+		err    error
+	)
+
+	fields = strings.Split(key, ":")
+	if len(fields) != len(values) {
+		return input.ID{}, fmt.Errorf("invalid device ID %q", key)
+	}
+
+	for i = range fields {
+		values[i], err = strconv.ParseUint(fields[i], 16, 16)
+		if err != nil {
+			return input.ID{}, fmt.Errorf("invalid device ID %q: %w", key, err)
+		}
+	}
+
+	return input.ID{
+		Bustype: uint16(values[0]),
+		Vendor:  uint16(values[1]),
+		Product: uint16(values[2]),
+		Version: uint16(values[3]),
+	}, nil
+}
+
+func parseAxis(name string) (mylib.InputCode, error) {
+	var (
+		value     uint64
+		eventType mylib.InputEvent
+		code      mylib.InputCode
+		err       error
+	)
+
+	value, err = strconv.ParseUint(name, 10, 16)
+	if err == nil {
+		return mylib.InputCode(value), nil
+	}
+
+	eventType, code, err = input.CodeByName(name)
+	if err != nil {
+		return 0, err
+	}
+
+	if eventType != input.EV_ABS {
+		return 0, fmt.Errorf("%w %q", input.ErrInvalidEventCode, name)
+	}
+
+	return code, nil
+}
+
+func readState(relPath string) ([]byte, error) {
+	var (
+		file *os.File
+		data []byte
+		err  error
+	)
+
+	file, err = xdg.StateFile(relPath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = io.ReadAll(file)
+
+	return data, errors.Join(err, file.Close())
+}
+
+func writeState(relPath string, data []byte) error {
+	var (
+		file *os.File
+		err  error
+	)
+
+	file, err = xdg.StateFile(relPath)
+	if err != nil {
+		return err
+	}
+
+	err = file.Truncate(0)
+	if err == nil {
+		_, err = file.Write(data)
+	}
+
+	return errors.Join(err, file.Close())
+}
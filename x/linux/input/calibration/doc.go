@@ -0,0 +1,21 @@
+//go:build linux
+
+// Package calibration keeps per-device joystick and gamepad calibration:
+// the range, dead zone, and direction the user chose for each absolute
+// axis. Profiles are stored by device ID in a JSON file under
+// $XDG_STATE_HOME and applied when building the normalized axes of
+// [input.Axis]:
+//
+//	store, err := calibration.Load(calibration.DefaultFile)
+//	if err != nil {
+//		return err
+//	}
+//
+//	stick, err := store.NewAxis(dev, input.ABS_X, false)
+//
+// A [Recorder] measures a new range and dead zone while the user moves
+// an axis through its extremes and then lets go of it. The abscalibrate
+// command saves its profiles to [DefaultFile] as well.
+//
+// This package is experimental and its API may change in any release.
+package calibration
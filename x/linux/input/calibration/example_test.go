@@ -0,0 +1,32 @@
+//go:build linux
+
+package calibration_test
+
+import (
+	"fmt"
+
+	"github.com/andrieee44/mylib/x/linux/input/calibration"
+)
+
+func ExampleRecorder() {
+	var (
+		recorder calibration.Recorder
+		value    int32
+		axis     calibration.Axis
+	)
+
+	// The user moves the stick through its extremes...
+	for _, value = range []int32{512, 40, 980, 1010, 3} {
+		recorder.Move(value)
+	}
+
+	// ...and lets go of it, where it drifts around the center.
+	for _, value = range []int32{500, 520, 491} {
+		recorder.Rest(value)
+	}
+
+	axis = recorder.Axis()
+	fmt.Println(axis.Minimum, axis.Maximum, axis.Flat)
+	// Output:
+	// 3 1010 15
+}
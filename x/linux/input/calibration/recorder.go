@@ -0,0 +1,61 @@
+//go:build linux
+
+package calibration
+
+// Recorder measures the calibration of an axis from its raw values.
+// Feed it the values read while the user moves the axis through its
+// extremes with [Recorder.Move], then the values read while the axis is
+// left alone with [Recorder.Rest]; [Recorder.Axis] then covers the whole
+// range reached and a dead zone wide enough to hide the drift seen at
+// rest.
+type Recorder struct {
+	minimum int32
+	maximum int32
+	moved   bool
+	rest    []int32
+}
+
+// Move records a value of the axis in motion.
+func (recorder *Recorder) Move(value int32) {
+	if !recorder.moved {
+		recorder.minimum, recorder.maximum = value, value
+		recorder.moved = true
+
+		return
+	}
+
+	recorder.minimum = min(recorder.minimum, value)
+	recorder.maximum = max(recorder.maximum, value)
+}
+
+// Rest records a value of the axis at rest.
+func (recorder *Recorder) Rest(value int32) {
+	recorder.rest = append(recorder.rest, value)
+}
+
+// Axis returns the calibration measured so far. The dead zone is the
+// largest distance from the center of the range seen at rest.
+func (recorder *Recorder) Axis() Axis {
+	var (
+		axis   Axis
+		center int64
+		value  int32
+	)
+
+	axis = Axis{Minimum: recorder.minimum, Maximum: recorder.maximum}
+	center = (int64(recorder.minimum) + int64(recorder.maximum)) / 2
+
+	for _, value = range recorder.rest {
+		axis.Flat = max(axis.Flat, int32(min(abs(int64(value)-center), int64(recorder.maximum)-center)))
+	}
+
+	return axis
+}
+
+func abs(value int64) int64 {
+	if value < 0 {
+		return -value
+	}
+
+	return value
+}
@@ -0,0 +1,12 @@
+//go:build linux
+
+// Package gestures recognizes touch gestures, such as taps, two-finger
+// scrolling, pinching, and swipes, from the contacts of a multi-touch
+// protocol B device tracked by [input.MTTracker].
+//
+// Distances are in device units, as reported by the ABS_MT_POSITION_X
+// and ABS_MT_POSITION_Y axes. Divide by the axis resolution from
+// [input.Device.AbsInfo] to convert them to millimeters.
+//
+// This package is experimental and its API may change in any release.
+package gestures
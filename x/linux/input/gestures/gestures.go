@@ -0,0 +1,320 @@
+//go:build linux
+
+package gestures
+
+import (
+	"math"
+	"time"
+
+	"github.com/andrieee44/mylib/linux/input"
+)
+
+// Kind identifies a recognized gesture.
+type Kind int
+
+const (
+	// Tap is a short touch without movement by one or more fingers.
+	Tap Kind = iota
+
+	// DoubleTap is a second tap with the same number of fingers soon
+	// after a first one. It is reported instead of the second Tap.
+	DoubleTap
+
+	// Scroll is two fingers moving together. It is reported on every
+	// frame while the fingers move.
+	Scroll
+
+	// Pinch is two fingers moving apart or together. It is reported on
+	// every frame while the distance between them changes.
+	Pinch
+
+	// Swipe is one or more fingers moving together and lifting off, for
+	// gestures not already recognized as Scroll or Pinch.
+	Swipe
+)
+
+// Gesture is a recognized gesture.
+type Gesture struct {
+	// Kind identifies the gesture.
+	Kind Kind
+
+	// Fingers is the number of fingers that made the gesture.
+	Fingers int
+
+	// DX and DY are the movement of the fingers' centroid: since the
+	// previous frame for Scroll, and over the whole gesture for Swipe.
+	DX, DY float64
+
+	// Scale is the distance between the two fingers of a Pinch relative
+	// to their distance when the pinch began.
+	Scale float64
+
+	// Time is the timestamp of the event frame that completed the
+	// gesture, as a duration since the epoch.
+	Time time.Duration
+}
+
+// Config holds the thresholds used to tell gestures apart.
+type Config struct {
+	// TapTimeout is the longest a touch may last to count as a tap.
+	TapTimeout time.Duration
+
+	// TapMaxMove is the farthest a finger may move during a tap.
+	TapMaxMove float64
+
+	// DoubleTapTimeout is the longest time between two taps for them
+	// to count as a double tap.
+	DoubleTapTimeout time.Duration
+
+	// ScrollMinMove is how far two fingers must move together before
+	// scrolling starts.
+	ScrollMinMove float64
+
+	// PinchMinScale is how much the distance between two fingers must
+	// change, as a fraction of the starting distance, before a pinch
+	// starts.
+	PinchMinScale float64
+
+	// SwipeMinMove is how far the fingers must move for a swipe.
+	SwipeMinMove float64
+}
+
+// Recognizer turns the events of a multi-touch device into gestures.
+type Recognizer struct {
+	config    Config
+	tracker   *input.MTTracker
+	onGesture func(Gesture)
+	now       time.Duration
+
+	start    map[int32]point
+	current  map[int32]point
+	began    time.Duration
+	fingers  int
+	maxMove  float64
+	moveX    float64
+	moveY    float64
+	mode     Kind
+	moving   bool
+	prevX    float64
+	prevY    float64
+	pinchGap float64
+
+	lastTap        time.Duration
+	lastTapFingers int
+}
+
+type point struct {
+	x, y float64
+}
+
+// DefaultConfig returns thresholds suited to a typical touchpad with a
+// resolution of around 10 units per millimeter.
+func DefaultConfig() Config {
+	return Config{
+		TapTimeout:       180 * time.Millisecond,
+		TapMaxMove:       30,
+		DoubleTapTimeout: 300 * time.Millisecond,
+		ScrollMinMove:    30,
+		PinchMinScale:    0.15,
+		SwipeMinMove:     200,
+	}
+}
+
+// NewRecognizer returns a Recognizer for a device with the given number
+// of multi-touch slots, calling onGesture for every gesture it
+// recognizes.
+func NewRecognizer(slots int, config Config, onGesture func(Gesture)) *Recognizer {
+	var recognizer *Recognizer
+
+	recognizer = &Recognizer{
+		config:    config,
+		onGesture: onGesture,
+		start:     make(map[int32]point),
+		current:   make(map[int32]point),
+	}
+
+	recognizer.tracker = input.NewMTTracker(slots, recognizer.contact)
+
+	return recognizer
+}
+
+// Handle feeds a single event to the recognizer. Gestures are recognized
+// when a frame ends with [input.SYN_REPORT].
+func (recognizer *Recognizer) Handle(ev input.Event) {
+	var isReport bool
+
+	isReport = ev.Type == input.EV_SYN && ev.Code == input.SYN_REPORT
+	if isReport {
+		recognizer.now = time.Duration(ev.Sec)*time.Second +
+			time.Duration(ev.Usec)*time.Microsecond
+	}
+
+	recognizer.tracker.Handle(ev)
+
+	if isReport {
+		recognizer.frame()
+	}
+}
+
+func (recognizer *Recognizer) contact(change input.ContactChange) {
+	var (
+		id  int32
+		pos point
+	)
+
+	id = change.Contact.TrackingID
+	pos = point{float64(change.Contact.X), float64(change.Contact.Y)}
+
+	switch change.Phase {
+	case input.ContactBegin:
+		if len(recognizer.current) == 0 {
+			recognizer.reset()
+		}
+
+		recognizer.start[id] = pos
+		recognizer.current[id] = pos
+		recognizer.fingers = max(recognizer.fingers, len(recognizer.current))
+	case input.ContactUpdate:
+		recognizer.current[id] = pos
+	case input.ContactEnd:
+		delete(recognizer.current, id)
+	}
+}
+
+func (recognizer *Recognizer) reset() {
+	clear(recognizer.start)
+	recognizer.began = recognizer.now
+	recognizer.fingers = 0
+	recognizer.maxMove = 0
+	recognizer.moveX, recognizer.moveY = 0, 0
+	recognizer.moving = false
+}
+
+// frame updates the gesture state after the contacts of a frame were
+// applied.
+func (recognizer *Recognizer) frame() {
+	var (
+		id       int32
+		pos      point
+		dx, dy   float64
+		sumX     float64
+		sumY     float64
+		count    int
+		gap      float64
+		centroid point
+	)
+
+	if len(recognizer.current) == 0 {
+		if recognizer.fingers != 0 {
+			recognizer.lift()
+			recognizer.fingers = 0
+		}
+
+		return
+	}
+
+	for id, pos = range recognizer.current {
+		dx, dy = pos.x-recognizer.start[id].x, pos.y-recognizer.start[id].y
+		recognizer.maxMove = max(recognizer.maxMove, math.Hypot(dx, dy))
+		sumX += dx
+		sumY += dy
+		count++
+		centroid.x += pos.x
+		centroid.y += pos.y
+	}
+
+	if count != recognizer.fingers {
+		return
+	}
+
+	recognizer.moveX, recognizer.moveY = sumX/float64(count), sumY/float64(count)
+	centroid.x /= float64(count)
+	centroid.y /= float64(count)
+
+	if count != 2 {
+		return
+	}
+
+	gap = recognizer.gap()
+
+	switch {
+	case recognizer.moving && recognizer.mode == Scroll:
+		recognizer.emit(Gesture{
+			Kind: Scroll,
+			DX:   centroid.x - recognizer.prevX,
+			DY:   centroid.y - recognizer.prevY,
+		})
+	case recognizer.moving && recognizer.mode == Pinch:
+		recognizer.emit(Gesture{Kind: Pinch, Scale: gap / recognizer.pinchGap})
+	case recognizer.startGap() > 0 &&
+		math.Abs(gap/recognizer.startGap()-1) >= recognizer.config.PinchMinScale:
+		recognizer.moving, recognizer.mode = true, Pinch
+		recognizer.pinchGap = recognizer.startGap()
+		recognizer.emit(Gesture{Kind: Pinch, Scale: gap / recognizer.pinchGap})
+	case math.Hypot(recognizer.moveX, recognizer.moveY) >= recognizer.config.ScrollMinMove:
+		recognizer.moving, recognizer.mode = true, Scroll
+		recognizer.emit(Gesture{Kind: Scroll, DX: recognizer.moveX, DY: recognizer.moveY})
+	}
+
+	recognizer.prevX, recognizer.prevY = centroid.x, centroid.y
+}
+
+// lift recognizes taps and swipes once every finger has lifted.
+func (recognizer *Recognizer) lift() {
+	switch {
+	case recognizer.moving:
+	case recognizer.now-recognizer.began <= recognizer.config.TapTimeout &&
+		recognizer.maxMove <= recognizer.config.TapMaxMove:
+		if recognizer.lastTapFingers == recognizer.fingers &&
+			recognizer.now-recognizer.lastTap <= recognizer.config.DoubleTapTimeout {
+			recognizer.lastTapFingers = 0
+			recognizer.emit(Gesture{Kind: DoubleTap})
+
+			return
+		}
+
+		recognizer.lastTap, recognizer.lastTapFingers = recognizer.now, recognizer.fingers
+		recognizer.emit(Gesture{Kind: Tap})
+	case math.Hypot(recognizer.moveX, recognizer.moveY) >= recognizer.config.SwipeMinMove:
+		recognizer.emit(Gesture{Kind: Swipe, DX: recognizer.moveX, DY: recognizer.moveY})
+	}
+}
+
+// gap returns the current distance between the two fingers.
+func (recognizer *Recognizer) gap() float64 {
+	return distance(recognizer.current, recognizer.current)
+}
+
+// startGap returns the distance between the two fingers when they
+// touched down.
+func (recognizer *Recognizer) startGap() float64 {
+	return distance(recognizer.start, recognizer.current)
+}
+
+func (recognizer *Recognizer) emit(gesture Gesture) {
+	gesture.Fingers = recognizer.fingers
+	gesture.Time = recognizer.now
+
+	if recognizer.onGesture != nil {
+		recognizer.onGesture(gesture)
+	}
+}
+
+// distance returns the distance between the points of the first two
+// contacts in ids, or 0 if there are fewer than two.
+func distance(points, ids map[int32]point) float64 {
+	var (
+		id   int32
+		pair []point
+	)
+
+	for id = range ids {
+		pair = append(pair, points[id])
+
+		if len(pair) == 2 {
+			return math.Hypot(pair[0].x-pair[1].x, pair[0].y-pair[1].y)
+		}
+	}
+
+	return 0
+}
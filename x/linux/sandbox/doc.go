@@ -0,0 +1,15 @@
+//go:build linux
+
+// Package sandbox confines processes built on this module with [Landlock]
+// and [seccomp].
+//
+// [Harden] applies a preset suited to input daemons: filesystem access is
+// limited to the evdev and uinput nodes, the related sysfs trees, and the
+// XDG base directories, and system calls are limited to those used by the
+// Go runtime and by this module.
+//
+// This package is experimental and its API may change in any release.
+//
+// [Landlock]: https://docs.kernel.org/userspace-api/landlock.html
+// [seccomp]: https://docs.kernel.org/userspace-api/seccomp_filter.html
+package sandbox
@@ -0,0 +1,299 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+
+	"github.com/andrieee44/mylib/linux/xdg"
+	"golang.org/x/sys/unix"
+)
+
+// Access is a set of Landlock filesystem access rights.
+type Access uint64
+
+const (
+	// AccessRead allows reading files and listing directories.
+	AccessRead Access = unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR
+
+	// AccessWrite allows reading, writing, creating, renaming, and
+	// removing files, directories, sockets, and named pipes.
+	AccessWrite Access = AccessRead |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_DIR |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_DIR |
+		unix.LANDLOCK_ACCESS_FS_MAKE_REG |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_MAKE_FIFO |
+		unix.LANDLOCK_ACCESS_FS_MAKE_SYM |
+		unix.LANDLOCK_ACCESS_FS_REFER
+
+	// AccessDevice allows reading, writing, and issuing ioctls on
+	// device nodes.
+	AccessDevice Access = AccessRead |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+
+	accessFile Access = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE |
+		unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+)
+
+// ErrUnsupported is returned when the kernel or architecture does not
+// support a sandboxing feature required by a [Policy].
+var ErrUnsupported error = errors.New("sandbox: unsupported")
+
+// PathRule grants access rights beneath a filesystem path.
+type PathRule struct {
+	// Path is the file or directory the rule applies to. Rules on a
+	// directory apply to everything beneath it.
+	Path string
+
+	// Access is the set of rights granted beneath Path. Rights that only
+	// apply to directories are dropped when Path is not a directory.
+	Access Access
+}
+
+// Policy describes the restrictions applied by [Policy.Apply].
+type Policy struct {
+	// Paths lists the only filesystem locations the process may access.
+	// Paths that do not exist are skipped.
+	Paths []PathRule
+
+	// Syscalls lists the only system call numbers the process may
+	// issue. Any other system call fails with EPERM, and on amd64 any
+	// call through the x32 ABI kills the process.
+	Syscalls []uintptr
+}
+
+// DefaultPolicy returns the policy applied by [Harden]. It covers the
+// evdev and uinput device nodes, the input sysfs trees, the XDG base
+// directories, the files read by the Go standard library for user and
+// time zone lookups, and the system calls made by the Go runtime and by
+// this module.
+func DefaultPolicy() *Policy {
+	return &Policy{
+		Paths: []PathRule{
+			{Path: "/dev/input", Access: AccessDevice},
+			{Path: "/dev/uinput", Access: AccessDevice},
+			{Path: "/sys/class/input", Access: AccessRead},
+			{Path: "/sys/devices", Access: AccessRead},
+			{Path: "/etc", Access: AccessRead},
+			{Path: "/usr/share/zoneinfo", Access: AccessRead},
+			{Path: xdg.DataHome(), Access: AccessWrite},
+			{Path: xdg.ConfigHome(), Access: AccessWrite},
+			{Path: xdg.StateHome(), Access: AccessWrite},
+			{Path: xdg.CacheHome(), Access: AccessWrite},
+			{Path: xdg.RuntimeDir(), Access: AccessWrite},
+		},
+		Syscalls: defaultSyscalls,
+	}
+}
+
+// Harden creates the XDG base directories if missing and applies
+// [DefaultPolicy] to every thread of the process. The restrictions
+// cannot be lifted and are inherited by child processes.
+//
+// Harden must run before any device or file it does not cover is
+// needed, typically at the start of main. It requires a binary built
+// without cgo, since only then can the Go runtime restrict all threads.
+func Harden() error {
+	const userOnly os.FileMode = 0o700
+
+	var (
+		policy *Policy
+		dir    string
+		err    error
+	)
+
+	policy = DefaultPolicy()
+
+	for _, dir = range []string{
+		xdg.DataHome(),
+		xdg.ConfigHome(),
+		xdg.StateHome(),
+		xdg.CacheHome(),
+	} {
+		err = os.MkdirAll(dir, userOnly)
+		if err != nil {
+			return fmt.Errorf("sandbox.Harden: %w", err)
+		}
+	}
+
+	err = policy.Apply()
+	if err != nil {
+		return fmt.Errorf("sandbox.Harden: %w", err)
+	}
+
+	return nil
+}
+
+// Apply restricts every thread of the process to policy, first with
+// Landlock and then with seccomp. It returns [ErrUnsupported] if the
+// kernel lacks Landlock or the architecture has no seccomp allowlist.
+func (policy *Policy) Apply() error {
+	var (
+		errno syscall.Errno
+		err   error
+	)
+
+	_, _, errno = syscall.AllThreadsSyscall(
+		unix.SYS_PRCTL,
+		unix.PR_SET_NO_NEW_PRIVS,
+		1,
+		0,
+	)
+	if errno != 0 {
+		return fmt.Errorf("Policy.Apply: %w", errno)
+	}
+
+	err = policy.landlock()
+	if err != nil {
+		return fmt.Errorf("Policy.Apply: %w", err)
+	}
+
+	err = policy.seccomp()
+	if err != nil {
+		return fmt.Errorf("Policy.Apply: %w", err)
+	}
+
+	return nil
+}
+
+func (policy *Policy) landlock() error {
+	var (
+		handled  Access
+		attr     unix.LandlockRulesetAttr
+		rule     PathRule
+		ruleset  uintptr
+		errno    syscall.Errno
+		err      error
+		abi      uintptr
+		rulesetF *os.File
+	)
+
+	abi, _, errno = unix.Syscall(
+		unix.SYS_LANDLOCK_CREATE_RULESET,
+		0,
+		0,
+		unix.LANDLOCK_CREATE_RULESET_VERSION,
+	)
+	if errno != 0 {
+		return fmt.Errorf("%w: landlock: %w", ErrUnsupported, errno)
+	}
+
+	handled = handledAccess(abi)
+	attr = unix.LandlockRulesetAttr{Access_fs: uint64(handled)}
+
+	ruleset, _, errno = unix.Syscall(
+		unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)),
+		unsafe.Offsetof(attr.Access_net),
+		0,
+	)
+	if errno != 0 {
+		return errno
+	}
+
+	rulesetF = os.NewFile(ruleset, "landlock-ruleset")
+	defer rulesetF.Close()
+
+	for _, rule = range policy.Paths {
+		err = addPathRule(ruleset, rule, handled)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, _, errno = syscall.AllThreadsSyscall(
+		unix.SYS_LANDLOCK_RESTRICT_SELF,
+		ruleset,
+		0,
+		0,
+	)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func handledAccess(abi uintptr) Access {
+	var handled Access
+
+	handled = AccessWrite | AccessDevice | unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_MAKE_CHAR | unix.LANDLOCK_ACCESS_FS_MAKE_BLOCK
+
+	if abi < 2 {
+		handled &^= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+
+	if abi < 3 {
+		handled &^= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+
+	if abi < 5 {
+		handled &^= unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+
+	return handled
+}
+
+func addPathRule(ruleset uintptr, rule PathRule, handled Access) error {
+	var (
+		fd    int
+		stat  unix.Stat_t
+		attr  unix.LandlockPathBeneathAttr
+		errno syscall.Errno
+		err   error
+	)
+
+	fd, err = unix.Open(rule.Path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %w", rule.Path, err)
+	}
+
+	defer unix.Close(fd)
+
+	err = unix.Fstat(fd, &stat)
+	if err != nil {
+		return fmt.Errorf("%s: %w", rule.Path, err)
+	}
+
+	attr = unix.LandlockPathBeneathAttr{
+		Allowed_access: uint64(rule.Access & handled),
+		Parent_fd:      int32(fd),
+	}
+
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		attr.Allowed_access &= uint64(accessFile)
+	}
+
+	_, _, errno = unix.Syscall6(
+		unix.SYS_LANDLOCK_ADD_RULE,
+		ruleset,
+		unix.LANDLOCK_RULE_PATH_BENEATH,
+		uintptr(unsafe.Pointer(&attr)),
+		0,
+		0,
+		0,
+	)
+	if errno != 0 {
+		return fmt.Errorf("%s: %w", rule.Path, errno)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+//go:build linux
+
+package sandbox
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+func (policy *Policy) seccomp() error {
+	const (
+		archOffset = 4
+		nrOffset   = 0
+	)
+
+	var (
+		filter []unix.SockFilter
+		prog   unix.SockFprog
+		nr     uintptr
+		errno  syscall.Errno
+	)
+
+	if auditArch == 0 {
+		return fmt.Errorf("%w: seccomp allowlist for this architecture", ErrUnsupported)
+	}
+
+	filter = make([]unix.SockFilter, 0, 2*len(policy.Syscalls)+6)
+	filter = append(
+		filter,
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, archOffset),
+		bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, auditArch, 1, 0),
+		bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_KILL_PROCESS),
+		bpfStmt(unix.BPF_LD|unix.BPF_W|unix.BPF_ABS, nrOffset),
+	)
+
+	if x32SyscallBit != 0 {
+		filter = append(
+			filter,
+			bpfJump(unix.BPF_JMP|unix.BPF_JSET|unix.BPF_K, x32SyscallBit, 0, 1),
+			bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_KILL_PROCESS),
+		)
+	}
+
+	for _, nr = range policy.Syscalls {
+		filter = append(
+			filter,
+			bpfJump(unix.BPF_JMP|unix.BPF_JEQ|unix.BPF_K, uint32(nr), 0, 1),
+			bpfStmt(unix.BPF_RET|unix.BPF_K, unix.SECCOMP_RET_ALLOW),
+		)
+	}
+
+	filter = append(
+		filter,
+		bpfStmt(
+			unix.BPF_RET|unix.BPF_K,
+			unix.SECCOMP_RET_ERRNO|uint32(unix.EPERM)&unix.SECCOMP_RET_DATA,
+		),
+	)
+
+	prog = unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+
+	_, _, errno = unix.Syscall(
+		unix.SYS_SECCOMP,
+		unix.SECCOMP_SET_MODE_FILTER,
+		unix.SECCOMP_FILTER_FLAG_TSYNC,
+		uintptr(unsafe.Pointer(&prog)),
+	)
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
+
+func bpfStmt(code uint16, k uint32) unix.SockFilter {
+	return unix.SockFilter{Code: code, K: k}
+}
+
+func bpfJump(code uint16, k uint32, jt, jf uint8) unix.SockFilter {
+	return unix.SockFilter{Code: code, Jt: jt, Jf: jf, K: k}
+}
@@ -0,0 +1,122 @@
+//go:build linux && (amd64 || arm64)
+
+package sandbox
+
+import (
+	"slices"
+
+	"golang.org/x/sys/unix"
+)
+
+// commonSyscalls lists the system calls made by the Go runtime and by this
+// module that share a name across the supported architectures.
+var commonSyscalls []uintptr = []uintptr{
+	unix.SYS_READ,
+	unix.SYS_WRITE,
+	unix.SYS_READV,
+	unix.SYS_WRITEV,
+	unix.SYS_PREAD64,
+	unix.SYS_PWRITE64,
+	unix.SYS_OPENAT,
+	unix.SYS_CLOSE,
+	unix.SYS_CLOSE_RANGE,
+	unix.SYS_FSTAT,
+	unix.SYS_NEWFSTATAT,
+	unix.SYS_STATX,
+	unix.SYS_LSEEK,
+	unix.SYS_GETDENTS64,
+	unix.SYS_READLINKAT,
+	unix.SYS_FACCESSAT,
+	unix.SYS_FACCESSAT2,
+	unix.SYS_MKDIRAT,
+	unix.SYS_MKNODAT,
+	unix.SYS_SYMLINKAT,
+	unix.SYS_LINKAT,
+	unix.SYS_UNLINKAT,
+	unix.SYS_RENAMEAT,
+	unix.SYS_RENAMEAT2,
+	unix.SYS_UTIMENSAT,
+	unix.SYS_FCHMOD,
+	unix.SYS_FCHMODAT,
+	unix.SYS_FCHOWN,
+	unix.SYS_FSYNC,
+	unix.SYS_FDATASYNC,
+	unix.SYS_FTRUNCATE,
+	unix.SYS_FCNTL,
+	unix.SYS_DUP,
+	unix.SYS_DUP3,
+	unix.SYS_IOCTL,
+	unix.SYS_GETXATTR,
+	unix.SYS_LGETXATTR,
+	unix.SYS_FGETXATTR,
+	unix.SYS_PIPE2,
+	unix.SYS_EVENTFD2,
+	unix.SYS_EPOLL_CREATE1,
+	unix.SYS_EPOLL_CTL,
+	unix.SYS_EPOLL_PWAIT,
+	unix.SYS_EPOLL_PWAIT2,
+	unix.SYS_PPOLL,
+	unix.SYS_PSELECT6,
+	unix.SYS_INOTIFY_INIT1,
+	unix.SYS_INOTIFY_ADD_WATCH,
+	unix.SYS_INOTIFY_RM_WATCH,
+	unix.SYS_SOCKET,
+	unix.SYS_SOCKETPAIR,
+	unix.SYS_BIND,
+	unix.SYS_LISTEN,
+	unix.SYS_ACCEPT4,
+	unix.SYS_CONNECT,
+	unix.SYS_GETSOCKNAME,
+	unix.SYS_GETPEERNAME,
+	unix.SYS_GETSOCKOPT,
+	unix.SYS_SETSOCKOPT,
+	unix.SYS_SENDTO,
+	unix.SYS_RECVFROM,
+	unix.SYS_SENDMSG,
+	unix.SYS_RECVMSG,
+	unix.SYS_SHUTDOWN,
+	unix.SYS_MMAP,
+	unix.SYS_MUNMAP,
+	unix.SYS_MPROTECT,
+	unix.SYS_MADVISE,
+	unix.SYS_MINCORE,
+	unix.SYS_BRK,
+	unix.SYS_RT_SIGACTION,
+	unix.SYS_RT_SIGPROCMASK,
+	unix.SYS_RT_SIGRETURN,
+	unix.SYS_SIGALTSTACK,
+	unix.SYS_CLONE,
+	unix.SYS_CLONE3,
+	unix.SYS_FUTEX,
+	unix.SYS_NANOSLEEP,
+	unix.SYS_CLOCK_GETTIME,
+	unix.SYS_CLOCK_NANOSLEEP,
+	unix.SYS_SCHED_YIELD,
+	unix.SYS_SCHED_GETAFFINITY,
+	unix.SYS_GETTID,
+	unix.SYS_GETPID,
+	unix.SYS_GETPPID,
+	unix.SYS_TGKILL,
+	unix.SYS_EXIT,
+	unix.SYS_EXIT_GROUP,
+	unix.SYS_WAITID,
+	unix.SYS_WAIT4,
+	unix.SYS_GETRANDOM,
+	unix.SYS_PRLIMIT64,
+	unix.SYS_RSEQ,
+	unix.SYS_SET_ROBUST_LIST,
+	unix.SYS_UNAME,
+	unix.SYS_GETUID,
+	unix.SYS_GETEUID,
+	unix.SYS_GETGID,
+	unix.SYS_GETEGID,
+	unix.SYS_GETGROUPS,
+	unix.SYS_GETCWD,
+	unix.SYS_RESTART_SYSCALL,
+	unix.SYS_TIMER_CREATE,
+	unix.SYS_TIMER_SETTIME,
+	unix.SYS_TIMER_DELETE,
+	unix.SYS_SETITIMER,
+}
+
+var defaultSyscalls []uintptr = slices.Concat(commonSyscalls, archSyscalls)
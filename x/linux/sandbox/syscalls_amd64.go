@@ -0,0 +1,27 @@
+//go:build linux
+
+package sandbox
+
+import "golang.org/x/sys/unix"
+
+const auditArch = unix.AUDIT_ARCH_X86_64
+
+// x32SyscallBit marks the system calls of the x32 ABI, which share
+// [auditArch] with amd64 and so must be rejected by number.
+const x32SyscallBit = 0x40000000
+
+// archSyscalls lists the legacy system calls that only exist on amd64.
+var archSyscalls []uintptr = []uintptr{
+	unix.SYS_ARCH_PRCTL,
+	unix.SYS_OPEN,
+	unix.SYS_STAT,
+	unix.SYS_LSTAT,
+	unix.SYS_POLL,
+	unix.SYS_EPOLL_WAIT,
+	unix.SYS_ACCESS,
+	unix.SYS_PIPE,
+	unix.SYS_MKDIR,
+	unix.SYS_UNLINK,
+	unix.SYS_RENAME,
+	unix.SYS_READLINK,
+}
@@ -0,0 +1,12 @@
+//go:build linux
+
+package sandbox
+
+import "golang.org/x/sys/unix"
+
+const auditArch = unix.AUDIT_ARCH_AARCH64
+
+// x32SyscallBit is zero, as this architecture has no x32 ABI.
+const x32SyscallBit = 0
+
+var archSyscalls []uintptr
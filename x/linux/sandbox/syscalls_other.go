@@ -0,0 +1,10 @@
+//go:build linux && !amd64 && !arm64
+
+package sandbox
+
+const auditArch = 0
+
+// x32SyscallBit is zero, as this architecture has no x32 ABI.
+const x32SyscallBit = 0
+
+var defaultSyscalls []uintptr
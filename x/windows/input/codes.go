@@ -0,0 +1,133 @@
+//go:build windows
+
+package input
+
+// Event types, numbered like their Linux counterparts.
+const (
+	// EV_SYN marks the end of a frame of events.
+	EV_SYN = 0x00
+
+	// EV_KEY reports keys and buttons.
+	EV_KEY = 0x01
+
+	// EV_REL reports relative motion, such as that of a mouse.
+	EV_REL = 0x02
+
+	// EV_ABS reports absolute axes, such as gamepad sticks.
+	EV_ABS = 0x03
+)
+
+// SYN_REPORT is the [EV_SYN] code ending a frame.
+const SYN_REPORT = 0x00
+
+// Keyboard keys. Codes 1 through [KEY_F12] are the scan codes of set 1,
+// which is how Windows reports keyboard keys.
+const (
+	// KEY_ESC is the Escape key, the first keyboard key.
+	KEY_ESC = 1
+
+	// KEY_A is the A key.
+	KEY_A = 30
+
+	// KEY_F12 is the F12 key, the last key of the basic set.
+	KEY_F12 = 88
+)
+
+// Mouse buttons.
+const (
+	// BTN_LEFT is the left mouse button.
+	BTN_LEFT = 0x110
+
+	// BTN_RIGHT is the right mouse button.
+	BTN_RIGHT = 0x111
+
+	// BTN_MIDDLE is the middle mouse button.
+	BTN_MIDDLE = 0x112
+
+	// BTN_SIDE is the first side button, usually "back".
+	BTN_SIDE = 0x113
+
+	// BTN_EXTRA is the second side button, usually "forward".
+	BTN_EXTRA = 0x114
+)
+
+// Gamepad buttons.
+const (
+	// BTN_SOUTH is the bottom face button, A on Xbox controllers.
+	BTN_SOUTH = 0x130
+
+	// BTN_EAST is the right face button, B on Xbox controllers.
+	BTN_EAST = 0x131
+
+	// BTN_NORTH is the top face button, Y on Xbox controllers.
+	BTN_NORTH = 0x133
+
+	// BTN_WEST is the left face button, X on Xbox controllers.
+	BTN_WEST = 0x134
+
+	// BTN_TL is the left shoulder button.
+	BTN_TL = 0x136
+
+	// BTN_TR is the right shoulder button.
+	BTN_TR = 0x137
+
+	// BTN_SELECT is the back or view button.
+	BTN_SELECT = 0x13a
+
+	// BTN_START is the start or menu button.
+	BTN_START = 0x13b
+
+	// BTN_THUMBL is the left stick button.
+	BTN_THUMBL = 0x13d
+
+	// BTN_THUMBR is the right stick button.
+	BTN_THUMBR = 0x13e
+
+	// BTN_DPAD_UP is up on the directional pad.
+	BTN_DPAD_UP = 0x220
+
+	// BTN_DPAD_DOWN is down on the directional pad.
+	BTN_DPAD_DOWN = 0x221
+
+	// BTN_DPAD_LEFT is left on the directional pad.
+	BTN_DPAD_LEFT = 0x222
+
+	// BTN_DPAD_RIGHT is right on the directional pad.
+	BTN_DPAD_RIGHT = 0x223
+)
+
+// Relative axes.
+const (
+	// REL_X is horizontal motion.
+	REL_X = 0x00
+
+	// REL_Y is vertical motion.
+	REL_Y = 0x01
+
+	// REL_HWHEEL is the horizontal scroll wheel.
+	REL_HWHEEL = 0x06
+
+	// REL_WHEEL is the vertical scroll wheel.
+	REL_WHEEL = 0x08
+)
+
+// Absolute axes.
+const (
+	// ABS_X is the horizontal axis of the left stick.
+	ABS_X = 0x00
+
+	// ABS_Y is the vertical axis of the left stick, growing downwards.
+	ABS_Y = 0x01
+
+	// ABS_Z is the left trigger.
+	ABS_Z = 0x02
+
+	// ABS_RX is the horizontal axis of the right stick.
+	ABS_RX = 0x03
+
+	// ABS_RY is the vertical axis of the right stick, growing downwards.
+	ABS_RY = 0x04
+
+	// ABS_RZ is the right trigger.
+	ABS_RZ = 0x05
+)
@@ -0,0 +1,32 @@
+//go:build windows
+
+// Package input implements the [mylib.InputDevice] interface on Windows.
+// Keyboards and mice are enumerated through [Raw Input], and Xbox
+// controllers and other gamepads through [XInput]:
+//
+//	devs, err := input.Devices()
+//	if err != nil {
+//		return err
+//	}
+//
+//	for _, dev := range devs {
+//		name, _ := dev.Name()
+//		fmt.Println(name)
+//	}
+//
+// Event types and codes use the numbering of the Linux backend, such as
+// [EV_KEY] with [KEY_A] or [BTN_SOUTH], so that applications can share
+// their code tables between platforms.
+//
+// Only gamepads deliver events, through [Gamepad.ReadInput]. Keyboards
+// and mice are enumerated only: a [RawDevice] reports its name, ID, and
+// capabilities, but does not implement [mylib.EventReader], since Raw
+// Input delivers events as WM_INPUT messages to a window, through a
+// message loop that this package does not provide. Register for them
+// with RegisterRawInputDevices to read them.
+//
+// This package is experimental and its API may change in any release.
+//
+// [Raw Input]: https://learn.microsoft.com/en-us/windows/win32/inputdev/raw-input
+// [XInput]: https://learn.microsoft.com/en-us/windows/win32/xinput/getting-started-with-xinput
+package input
@@ -0,0 +1,345 @@
+//go:build windows
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/andrieee44/mylib"
+	"golang.org/x/sys/windows"
+)
+
+// ErrClosed is returned by the methods of a device once it is closed.
+var ErrClosed error = errors.New("device closed")
+
+// ErrInvalidEventType is returned when a device is asked for the codes
+// of an event type it does not support.
+var ErrInvalidEventType error = errors.New("invalid event type")
+
+const (
+	rimTypeMouse    = 0
+	rimTypeKeyboard = 1
+
+	ridiDeviceName = 0x20000007
+	ridiDeviceInfo = 0x2000000b
+)
+
+var (
+	user32 *windows.LazyDLL = windows.NewLazySystemDLL("user32.dll")
+	hid    *windows.LazyDLL = windows.NewLazySystemDLL("hid.dll")
+
+	procGetRawInputDeviceList  *windows.LazyProc = user32.NewProc("GetRawInputDeviceList")
+	procGetRawInputDeviceInfoW *windows.LazyProc = user32.NewProc("GetRawInputDeviceInfoW")
+	procHidDGetProductString   *windows.LazyProc = hid.NewProc("HidD_GetProductString")
+
+	// vidPID matches the vendor and product IDs in a device interface
+	// path, such as \\?\HID#VID_046D&PID_C52B&MI_00#....
+	vidPID *regexp.Regexp = regexp.MustCompile(`(?i)VID_([0-9A-F]{4})&PID_([0-9A-F]{4})`)
+)
+
+// rawInputDeviceList is RAWINPUTDEVICELIST.
+type rawInputDeviceList struct {
+	device windows.Handle
+	kind   uint32
+}
+
+// ridDeviceInfo is RID_DEVICE_INFO. Its union is decoded by the mouse
+// and keyboard accessors.
+type ridDeviceInfo struct {
+	size  uint32
+	kind  uint32
+	union [6]uint32
+}
+
+// RawDevice is a keyboard or mouse enumerated through Raw Input. It
+// describes the device but does not read its events, so it implements
+// [mylib.InputDevice] but not [mylib.EventReader].
+type RawDevice struct {
+	handle windows.Handle
+	path   string
+	info   ridDeviceInfo
+	closed atomic.Bool
+}
+
+var _ mylib.InputDevice = (*RawDevice)(nil)
+
+// Devices returns the attached keyboards and mice, followed by the
+// connected XInput gamepads.
+func Devices() ([]mylib.InputDevice, error) {
+	var (
+		raws    []*RawDevice
+		raw     *RawDevice
+		pads    []*Gamepad
+		pad     *Gamepad
+		devices []mylib.InputDevice
+		err     error
+	)
+
+	raws, err = RawDevices()
+	if err != nil {
+		return nil, fmt.Errorf("input.Devices: %w", err)
+	}
+
+	for _, raw = range raws {
+		devices = append(devices, raw)
+	}
+
+	pads, err = Gamepads()
+	if err != nil {
+		return nil, fmt.Errorf("input.Devices: %w", err)
+	}
+
+	for _, pad = range pads {
+		devices = append(devices, pad)
+	}
+
+	return devices, nil
+}
+
+// RawDevices returns the keyboards and mice known to Raw Input. Other
+// HID devices are left out, since their reports have no fixed meaning.
+func RawDevices() ([]*RawDevice, error) {
+	var (
+		count   uint32
+		list    []rawInputDeviceList
+		entry   rawInputDeviceList
+		dev     *RawDevice
+		devices []*RawDevice
+		ret     uintptr
+		err     error
+	)
+
+	ret, _, err = procGetRawInputDeviceList.Call(
+		0,
+		uintptr(unsafe.Pointer(&count)),
+		unsafe.Sizeof(entry),
+	)
+	if int32(ret) == -1 {
+		return nil, fmt.Errorf("input.RawDevices: %w", err)
+	}
+
+	list = make([]rawInputDeviceList, count)
+	if count != 0 {
+		ret, _, err = procGetRawInputDeviceList.Call(
+			uintptr(unsafe.Pointer(&list[0])),
+			uintptr(unsafe.Pointer(&count)),
+			unsafe.Sizeof(entry),
+		)
+		if int32(ret) == -1 {
+			return nil, fmt.Errorf("input.RawDevices: %w", err)
+		}
+
+		list = list[:ret]
+	}
+
+	for _, entry = range list {
+		if entry.kind != rimTypeMouse && entry.kind != rimTypeKeyboard {
+			continue
+		}
+
+		dev, err = newRawDevice(entry.device)
+		if err != nil {
+			return nil, fmt.Errorf("input.RawDevices: %w", err)
+		}
+
+		devices = append(devices, dev)
+	}
+
+	return devices, nil
+}
+
+func newRawDevice(handle windows.Handle) (*RawDevice, error) {
+	var (
+		dev  *RawDevice
+		size uint32
+		name []uint16
+		ret  uintptr
+		err  error
+	)
+
+	dev = &RawDevice{handle: handle}
+
+	ret, _, err = procGetRawInputDeviceInfoW.Call(
+		uintptr(handle),
+		ridiDeviceName,
+		0,
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if ret != 0 {
+		return nil, err
+	}
+
+	name = make([]uint16, size+1)
+
+	ret, _, err = procGetRawInputDeviceInfoW.Call(
+		uintptr(handle),
+		ridiDeviceName,
+		uintptr(unsafe.Pointer(&name[0])),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if int32(ret) < 0 {
+		return nil, err
+	}
+
+	dev.path = windows.UTF16ToString(name)
+	dev.info.size = uint32(unsafe.Sizeof(dev.info))
+	size = dev.info.size
+
+	ret, _, err = procGetRawInputDeviceInfoW.Call(
+		uintptr(handle),
+		ridiDeviceInfo,
+		uintptr(unsafe.Pointer(&dev.info)),
+		uintptr(unsafe.Pointer(&size)),
+	)
+	if int32(ret) < 0 {
+		return nil, err
+	}
+
+	return dev, nil
+}
+
+// Path returns the device interface path, such as
+// \\?\HID#VID_046D&PID_C52B&MI_00#....
+func (dev *RawDevice) Path() string {
+	return dev.path
+}
+
+// Name returns the product string of the device, or its interface path
+// if the driver reports none.
+func (dev *RawDevice) Name() (string, error) {
+	var (
+		path   *uint16
+		handle windows.Handle
+		buf    [127]uint16
+		ret    uintptr
+		err    error
+	)
+
+	if dev.closed.Load() {
+		return "", fmt.Errorf("RawDevice.Name: %w", ErrClosed)
+	}
+
+	path, err = windows.UTF16PtrFromString(dev.path)
+	if err != nil {
+		return "", fmt.Errorf("RawDevice.Name: %w", err)
+	}
+
+	// No access rights are needed to query the attributes of a HID
+	// device, which keeps keyboards and mice, held exclusively by
+	// Windows, openable.
+	handle, err = windows.CreateFile(
+		path,
+		0,
+		windows.FILE_SHARE_READ|windows.FILE_SHARE_WRITE,
+		nil,
+		windows.OPEN_EXISTING,
+		0,
+		0,
+	)
+	if err != nil {
+		return dev.path, nil
+	}
+
+	defer windows.CloseHandle(handle)
+
+	ret, _, _ = procHidDGetProductString.Call(
+		uintptr(handle),
+		uintptr(unsafe.Pointer(&buf[0])),
+		unsafe.Sizeof(buf),
+	)
+	if ret == 0 || buf[0] == 0 {
+		return dev.path, nil
+	}
+
+	return windows.UTF16ToString(buf[:]), nil
+}
+
+// ID returns the vendor and product of the device, formatted like
+// "vendor 0x46d product 0xc52b", or its interface path if the path holds
+// neither.
+func (dev *RawDevice) ID() (string, error) {
+	var (
+		match           []string
+		vendor, product uint64
+	)
+
+	if dev.closed.Load() {
+		return "", fmt.Errorf("RawDevice.ID: %w", ErrClosed)
+	}
+
+	match = vidPID.FindStringSubmatch(dev.path)
+	if match == nil {
+		return dev.path, nil
+	}
+
+	vendor, _ = strconv.ParseUint(match[1], 16, 16)
+	product, _ = strconv.ParseUint(match[2], 16, 16)
+
+	return fmt.Sprintf("vendor %#x product %#x", vendor, product), nil
+}
+
+// Events returns [EV_SYN], [EV_KEY], and, for mice, [EV_REL].
+func (dev *RawDevice) Events() ([]mylib.InputEvent, error) {
+	if dev.closed.Load() {
+		return nil, fmt.Errorf("RawDevice.Events: %w", ErrClosed)
+	}
+
+	if dev.info.kind == rimTypeMouse {
+		return []mylib.InputEvent{EV_SYN, EV_KEY, EV_REL}, nil
+	}
+
+	return []mylib.InputEvent{EV_SYN, EV_KEY}, nil
+}
+
+// Codes returns the codes of eventType the device has. Keyboards report
+// the keys of the basic set, [KEY_ESC] through [KEY_F12], since Raw
+// Input only tells how many keys they have. Mice report their buttons
+// and wheels.
+func (dev *RawDevice) Codes(eventType mylib.InputEvent) ([]mylib.InputCode, error) {
+	var (
+		codes   []mylib.InputCode
+		code    mylib.InputCode
+		buttons uint32
+	)
+
+	if dev.closed.Load() {
+		return nil, fmt.Errorf("RawDevice.Codes: %w", ErrClosed)
+	}
+
+	switch {
+	case eventType == EV_SYN:
+		codes = []mylib.InputCode{SYN_REPORT}
+	case eventType == EV_KEY && dev.info.kind == rimTypeKeyboard:
+		for code = KEY_ESC; code <= KEY_F12; code++ {
+			codes = append(codes, code)
+		}
+	case eventType == EV_KEY && dev.info.kind == rimTypeMouse:
+		// RID_DEVICE_INFO_MOUSE: dwId, dwNumberOfButtons, dwSampleRate,
+		// fHasHorizontalWheel.
+		buttons = min(dev.info.union[1], BTN_EXTRA-BTN_LEFT+1)
+		for code = BTN_LEFT; code < BTN_LEFT+mylib.InputCode(buttons); code++ {
+			codes = append(codes, code)
+		}
+	case eventType == EV_REL && dev.info.kind == rimTypeMouse:
+		codes = []mylib.InputCode{REL_X, REL_Y, REL_WHEEL}
+		if dev.info.union[3] != 0 {
+			codes = []mylib.InputCode{REL_X, REL_Y, REL_HWHEEL, REL_WHEEL}
+		}
+	default:
+		return nil, fmt.Errorf("RawDevice.Codes: %w %d", ErrInvalidEventType, eventType)
+	}
+
+	return codes, nil
+}
+
+// Close marks the device closed. Raw Input devices hold no resources.
+func (dev *RawDevice) Close() error {
+	dev.closed.Store(true)
+
+	return nil
+}
@@ -0,0 +1,361 @@
+//go:build windows
+
+package input
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unsafe"
+
+	"github.com/andrieee44/mylib"
+	"golang.org/x/sys/windows"
+)
+
+// ErrDisconnected is returned by [Gamepad.ReadInput] when the gamepad
+// was disconnected.
+var ErrDisconnected error = errors.New("gamepad disconnected")
+
+// xinputUsers is the number of controllers XInput supports.
+const xinputUsers = 4
+
+// pollInterval is how often [Gamepad.ReadInput] polls XInput, which has
+// no way to wait for input.
+const pollInterval = 4 * time.Millisecond
+
+var (
+	xinput *windows.LazyDLL = loadXInput()
+
+	procXInputGetState *windows.LazyProc = xinput.NewProc("XInputGetState")
+	procXInputSetState *windows.LazyProc = xinput.NewProc("XInputSetState")
+)
+
+// xinputButton is a button bit of XINPUT_GAMEPAD and its code.
+type xinputButton struct {
+	bit  uint16
+	code mylib.InputCode
+}
+
+// xinputButtons maps the XINPUT_GAMEPAD button bits to button codes.
+var xinputButtons []xinputButton = []xinputButton{
+	{0x0001, BTN_DPAD_UP},
+	{0x0002, BTN_DPAD_DOWN},
+	{0x0004, BTN_DPAD_LEFT},
+	{0x0008, BTN_DPAD_RIGHT},
+	{0x0010, BTN_START},
+	{0x0020, BTN_SELECT},
+	{0x0040, BTN_THUMBL},
+	{0x0080, BTN_THUMBR},
+	{0x0100, BTN_TL},
+	{0x0200, BTN_TR},
+	{0x1000, BTN_SOUTH},
+	{0x2000, BTN_EAST},
+	{0x4000, BTN_WEST},
+	{0x8000, BTN_NORTH},
+}
+
+// xinputGamepad is XINPUT_GAMEPAD.
+type xinputGamepad struct {
+	buttons      uint16
+	leftTrigger  uint8
+	rightTrigger uint8
+	thumbLX      int16
+	thumbLY      int16
+	thumbRX      int16
+	thumbRY      int16
+}
+
+// xinputState is XINPUT_STATE.
+type xinputState struct {
+	packet  uint32
+	gamepad xinputGamepad
+}
+
+// xinputVibration is XINPUT_VIBRATION.
+type xinputVibration struct {
+	leftMotorSpeed  uint16
+	rightMotorSpeed uint16
+}
+
+// Gamepad is a controller connected through XInput, such as an Xbox
+// controller. XInput has no way to wait for input, so
+// [Gamepad.ReadInput] polls the controller.
+type Gamepad struct {
+	user    uint32
+	mutex   sync.Mutex
+	last    xinputState
+	started bool
+	pending []mylib.Event
+	closed  atomic.Bool
+	rumbles atomic.Uint64
+}
+
+var (
+	_ mylib.InputDevice = (*Gamepad)(nil)
+	_ mylib.EventReader = (*Gamepad)(nil)
+	_ mylib.Haptics     = (*Gamepad)(nil)
+)
+
+// loadXInput returns the newest XInput library: xinput1_4.dll, shipped
+// since Windows 8, or xinput9_1_0.dll before it.
+func loadXInput() *windows.LazyDLL {
+	var dll *windows.LazyDLL
+
+	dll = windows.NewLazySystemDLL("xinput1_4.dll")
+	if dll.Load() != nil {
+		dll = windows.NewLazySystemDLL("xinput9_1_0.dll")
+	}
+
+	return dll
+}
+
+// Gamepads returns the controllers currently connected through XInput.
+// It returns no error if XInput is unavailable.
+func Gamepads() ([]*Gamepad, error) {
+	var (
+		pads  []*Gamepad
+		user  uint32
+		state xinputState
+		err   error
+	)
+
+	if procXInputGetState.Find() != nil {
+		return nil, nil
+	}
+
+	for user = range xinputUsers {
+		err = getState(user, &state)
+		if errors.Is(err, windows.ERROR_DEVICE_NOT_CONNECTED) {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("input.Gamepads: %w", err)
+		}
+
+		pads = append(pads, &Gamepad{user: user})
+	}
+
+	return pads, nil
+}
+
+// User returns the XInput user index of the controller, 0 through 3,
+// which matches the quadrant lit on Xbox controllers.
+func (pad *Gamepad) User() int {
+	return int(pad.user)
+}
+
+// Name returns "XInput Controller" followed by the user index counted
+// from 1.
+func (pad *Gamepad) Name() (string, error) {
+	if pad.closed.Load() {
+		return "", fmt.Errorf("Gamepad.Name: %w", ErrClosed)
+	}
+
+	return fmt.Sprintf("XInput Controller %d", pad.user+1), nil
+}
+
+// ID returns "xinput" followed by the user index, such as "xinput 0".
+// XInput does not report the vendor and product of controllers.
+func (pad *Gamepad) ID() (string, error) {
+	if pad.closed.Load() {
+		return "", fmt.Errorf("Gamepad.ID: %w", ErrClosed)
+	}
+
+	return fmt.Sprintf("xinput %d", pad.user), nil
+}
+
+// Events returns [EV_SYN], [EV_KEY], and [EV_ABS].
+func (pad *Gamepad) Events() ([]mylib.InputEvent, error) {
+	if pad.closed.Load() {
+		return nil, fmt.Errorf("Gamepad.Events: %w", ErrClosed)
+	}
+
+	return []mylib.InputEvent{EV_SYN, EV_KEY, EV_ABS}, nil
+}
+
+// Codes returns the buttons and axes of an Xbox controller. The sticks
+// range from -32768 to 32767, and the triggers, [ABS_Z] and [ABS_RZ],
+// from 0 to 255.
+func (pad *Gamepad) Codes(eventType mylib.InputEvent) ([]mylib.InputCode, error) {
+	var (
+		codes  []mylib.InputCode
+		button xinputButton
+	)
+
+	if pad.closed.Load() {
+		return nil, fmt.Errorf("Gamepad.Codes: %w", ErrClosed)
+	}
+
+	switch eventType {
+	case EV_SYN:
+		return []mylib.InputCode{SYN_REPORT}, nil
+	case EV_KEY:
+		for _, button = range xinputButtons {
+			codes = append(codes, button.code)
+		}
+
+		return codes, nil
+	case EV_ABS:
+		return []mylib.InputCode{ABS_X, ABS_Y, ABS_Z, ABS_RX, ABS_RY, ABS_RZ}, nil
+	default:
+		return nil, fmt.Errorf("Gamepad.Codes: %w %d", ErrInvalidEventType, eventType)
+	}
+}
+
+// ReadInput implements [mylib.EventReader]. It polls the controller until
+// its state changes and returns the changes one event at a time, each
+// frame ending with a [SYN_REPORT]. The first call only records the
+// current state. It returns [ErrDisconnected] once the controller is
+// disconnected and [ErrClosed] once the Gamepad is closed.
+func (pad *Gamepad) ReadInput() (mylib.Event, error) {
+	var (
+		state xinputState
+		ev    mylib.Event
+		err   error
+	)
+
+	pad.mutex.Lock()
+	defer pad.mutex.Unlock()
+
+	for len(pad.pending) == 0 {
+		if pad.closed.Load() {
+			return mylib.Event{}, fmt.Errorf("Gamepad.ReadInput: %w", ErrClosed)
+		}
+
+		err = getState(pad.user, &state)
+		if errors.Is(err, windows.ERROR_DEVICE_NOT_CONNECTED) {
+			return mylib.Event{}, fmt.Errorf("Gamepad.ReadInput: %w", ErrDisconnected)
+		}
+
+		if err != nil {
+			return mylib.Event{}, fmt.Errorf("Gamepad.ReadInput: %w", err)
+		}
+
+		if pad.started && state.packet != pad.last.packet {
+			pad.diff(state.gamepad, time.Now())
+		}
+
+		pad.started = true
+		pad.last = state
+
+		if len(pad.pending) == 0 {
+			time.Sleep(pollInterval)
+		}
+	}
+
+	ev = pad.pending[0]
+	pad.pending = pad.pending[1:]
+
+	return ev, nil
+}
+
+// Rumble implements [mylib.Haptics]. The strong magnitude drives the
+// left, low-frequency motor and the weak one the right, high-frequency
+// motor. The motors are stopped after duration unless Rumble is called
+// again in the meantime.
+func (pad *Gamepad) Rumble(strong, weak uint16, duration time.Duration) error {
+	var (
+		generation uint64
+		err        error
+	)
+
+	if pad.closed.Load() {
+		return fmt.Errorf("Gamepad.Rumble: %w", ErrClosed)
+	}
+
+	err = setState(pad.user, &xinputVibration{leftMotorSpeed: strong, rightMotorSpeed: weak})
+	if err != nil {
+		return fmt.Errorf("Gamepad.Rumble: %w", err)
+	}
+
+	generation = pad.rumbles.Add(1)
+
+	time.AfterFunc(duration, func() {
+		if pad.rumbles.Load() == generation {
+			_ = setState(pad.user, &xinputVibration{})
+		}
+	})
+
+	return nil
+}
+
+// Close stops the controller from being read. XInput holds no
+// resources for it.
+func (pad *Gamepad) Close() error {
+	pad.closed.Store(true)
+
+	return nil
+}
+
+// diff queues the events turning the last state into gamepad.
+func (pad *Gamepad) diff(gamepad xinputGamepad, now time.Time) {
+	var (
+		old    xinputGamepad
+		button xinputButton
+		value  int32
+	)
+
+	old = pad.last.gamepad
+
+	for _, button = range xinputButtons {
+		if (old.buttons^gamepad.buttons)&button.bit == 0 {
+			continue
+		}
+
+		value = 0
+		if gamepad.buttons&button.bit != 0 {
+			value = 1
+		}
+
+		pad.pending = append(pad.pending, mylib.Event{Time: now, Type: EV_KEY, Code: button.code, Value: value})
+	}
+
+	// XInput sticks grow upwards; they are flipped to grow downwards like
+	// evdev axes, saturating -32768 at 32767.
+	pad.axis(now, ABS_X, int32(old.thumbLX), int32(gamepad.thumbLX))
+	pad.axis(now, ABS_Y, flipStick(old.thumbLY), flipStick(gamepad.thumbLY))
+	pad.axis(now, ABS_Z, int32(old.leftTrigger), int32(gamepad.leftTrigger))
+	pad.axis(now, ABS_RX, int32(old.thumbRX), int32(gamepad.thumbRX))
+	pad.axis(now, ABS_RY, flipStick(old.thumbRY), flipStick(gamepad.thumbRY))
+	pad.axis(now, ABS_RZ, int32(old.rightTrigger), int32(gamepad.rightTrigger))
+
+	if len(pad.pending) != 0 {
+		pad.pending = append(pad.pending, mylib.Event{Time: now, Type: EV_SYN, Code: SYN_REPORT})
+	}
+}
+
+func (pad *Gamepad) axis(now time.Time, code mylib.InputCode, old, value int32) {
+	if old != value {
+		pad.pending = append(pad.pending, mylib.Event{Time: now, Type: EV_ABS, Code: code, Value: value})
+	}
+}
+
+func flipStick(value int16) int32 {
+	return min(-int32(value), 32767)
+}
+
+func getState(user uint32, state *xinputState) error {
+	var ret uintptr
+
+	ret, _, _ = procXInputGetState.Call(uintptr(user), uintptr(unsafe.Pointer(state)))
+	if ret != 0 {
+		return syscall.Errno(ret)
+	}
+
+	return nil
+}
+
+func setState(user uint32, vibration *xinputVibration) error {
+	var ret uintptr
+
+	ret, _, _ = procXInputSetState.Call(uintptr(user), uintptr(unsafe.Pointer(vibration)))
+	if ret != 0 {
+		return syscall.Errno(ret)
+	}
+
+	return nil
+}